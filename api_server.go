@@ -1,57 +1,356 @@
 package main
 
 import (
-	"github.com/gin-gonic/gin"
-	aws_unused "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
-	gcp_unused "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	aws_unused_resources "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	unused_gcp_resources "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/jobs"
+	"github.com/sawlemon/unused-cloud-resources/remediation"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/scheduler"
+	"github.com/sawlemon/unused-cloud-resources/store"
 )
 
-func getAWSEBSData(c *gin.Context) {
-	unused_ebs_data := aws_unused.Get_unused_ebs_volumes("us-east-1", "limited-admin")
-	percentage_unused := 100 * unused_ebs_data.UnusedInstancesCount / unused_ebs_data.TotalInstancesCount
-	c.JSON(http.StatusOK, gin.H{
-		"resource_ids": unused_ebs_data.ResourceIDs,
-		"percentage":   percentage_unused,
-		"total_count":  unused_ebs_data.TotalInstancesCount,
-		"unused_count": unused_ebs_data.UnusedInstancesCount,
-	})
+// scanHandler returns the KPI of one detector from its latest run in runs.
+// It never scans: until a run is collected, by cmd/collect or the
+// scheduler, it answers 404.
+func scanHandler(detector string, runs store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		run, err := runs.LatestRun(c.Request.Context(), detector)
+		if errors.Is(err, scanner.ErrNotFound) {
+			err = &scanner.Error{Kind: scanner.KindNotFound, Resource: detector, Err: errors.New("not collected yet, see GET /scans")}
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		body := kpi(run.Result)
+		body["run_id"] = run.ID
+		body["scanned_at"] = run.FinishedAt
+		c.JSON(http.StatusOK, body)
+	}
 }
 
-func getGCPUnusedDisk(c *gin.Context) {
-	unused_disk_data := gcp_unused.Get_Unused_Disks("finops-accelerator", "us-central1-a")
-	percentage_unused := 100 * unused_disk_data.UnusedInstancesCount / unused_disk_data.TotalInstancesCount
-	c.JSON(http.StatusOK, gin.H{
-		"resource_ids": unused_disk_data.ResourceIDs,
-		"percentage":   percentage_unused,
-		"total_count":  unused_disk_data.TotalInstancesCount,
-		"unused_count": unused_disk_data.UnusedInstancesCount,
-	})
+// kpi is the response body describing result. The percentage of unused
+// resources is 0 when nothing was scanned.
+func kpi(result scanner.Result) gin.H {
+	return gin.H{
+		"resource_ids":     result.ResourceIDs,
+		"percentage":       result.Percentage(),
+		"total_count":      result.TotalInstancesCount,
+		"unused_count":     result.UnusedInstancesCount,
+		"excluded_count":   result.ExcludedInstancesCount,
+		"findings":         result.Findings,
+		"skipped":          result.Skipped,
+		"regions":          result.Regions,
+		"accounts":         result.Accounts,
+		"projects":         result.Projects,
+		"monthly_cost_usd": result.MonthlyCost,
+	}
 }
 
-func getGCPUnusedIP(c *gin.Context) {
-	unusedIPsData := gcp_unused.Get_Unused_IPs("finops-accelerator", "us-central1")
-	percentage_unused := 100 * unusedIPsData.UnusedInstancesCount / unusedIPsData.TotalInstancesCount
-	c.JSON(http.StatusOK, gin.H{
-		"resource_ids": unusedIPsData.ResourceIDs,
-		"percentage":   percentage_unused,
-		"total_count":  unusedIPsData.TotalInstancesCount,
-		"unused_count": unusedIPsData.UnusedInstancesCount,
-	})
+// historyHandler returns the KPI of one detector over time: a point per
+// interval (?interval=hour, day, week or month; day by default) from ?from
+// to ?to, given as RFC 3339 times or dates. By default it covers the last
+// 30 intervals.
+func historyHandler(detector string, runs store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		interval, err := store.ParseInterval(c.Query("interval"))
+		to := time.Now().UTC()
+		if err == nil && c.Query("to") != "" {
+			to, err = parseTime("to", c.Query("to"))
+		}
+		from := interval.Add(to, -30)
+		if err == nil && c.Query("from") != "" {
+			from, err = parseTime("from", c.Query("from"))
+		}
+		var points []store.Point
+		if err == nil {
+			points, err = store.History(c.Request.Context(), runs, detector, from, to, interval)
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if points == nil {
+			points = []store.Point{}
+		}
+		c.JSON(http.StatusOK, gin.H{"detector": detector, "interval": interval, "from": from, "to": to, "points": points})
+	}
+}
+
+// parseTime parses the value of query parameter name as an RFC 3339 time
+// or a date.
+func parseTime(name, value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("%s: %q is not an RFC 3339 time or a date", name, value)}
+}
+
+// scansHandler lists the scheduled detectors and the recent scheduled
+// scans, newest first, of ?detector or of all of them, up to ?limit.
+func scansHandler(sched *scheduler.Scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 {
+			abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: errors.New("limit must be a positive number")})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"schedules": sched.Entries(),
+			"runs":      sched.Recent(c.Query("detector"), limit),
+		})
+	}
+}
+
+// ebsRemediation is the body of POST /aws/ebs/remediate.
+type ebsRemediation struct {
+	Region    string   `json:"region" binding:"required"`
+	Account   string   `json:"account"`
+	Role      string   `json:"role"`
+	VolumeIDs []string `json:"volume_ids" binding:"required"`
+	DryRun    *bool    `json:"dry_run"` // only true, the default, is accepted
+}
+
+// remediateEBS returns the plan for snapshotting and deleting unattached
+// volumes, checked with EC2 DryRun. It never changes anything: volumes are
+// deleted by approved plans, through POST /plans.
+func remediateEBS(c *gin.Context) {
+	var req ebsRemediation
+	if !bindDryRun(c, &req, &req.DryRun) {
+		return
+	}
+	ctx := c.Request.Context()
+	opts, err := aws_unused_resources.AccountOptions(ctx, req.Account, req.Role)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	actions, err := aws_unused_resources.SnapshotAndDeleteVolumes(ctx, req.Region, req.VolumeIDs, true, opts...)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": true, "actions": actions})
+}
+
+// ec2Stop is the body of POST /aws/ec2/stop and POST /aws/ec2/start.
+type ec2Stop struct {
+	Region      string   `json:"region" binding:"required"`
+	Account     string   `json:"account"`
+	Role        string   `json:"role"`
+	InstanceIDs []string `json:"instance_ids"` // required to stop; empty starts every instance a stop stopped
+	By          string   `json:"by"`           // who stops them, required to stop
+	Reason      string   `json:"reason"`
+	DryRun      *bool    `json:"dry_run"` // only true, the default, is accepted
+}
+
+// instancesHandler returns the plan for stopping idle instances, or with
+// start set for starting the ones a stop stopped, checked with EC2 DryRun.
+// It never changes anything: instances are stopped by approved plans.
+func instancesHandler(start bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ec2Stop
+		if !bindDryRun(c, &req, &req.DryRun) {
+			return
+		}
+		if !start && (len(req.InstanceIDs) == 0 || req.By == "") {
+			abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: errors.New("instance_ids and by are required")})
+			return
+		}
+		ctx := c.Request.Context()
+		opts, err := aws_unused_resources.AccountOptions(ctx, req.Account, req.Role)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		var actions []aws_unused_resources.InstanceAction
+		if start {
+			actions, err = aws_unused_resources.StartInstances(ctx, req.Region, req.InstanceIDs, true, opts...)
+		} else {
+			actions, err = aws_unused_resources.StopInstances(ctx, req.Region, aws_unused_resources.StopRequest{
+				InstanceIDs: req.InstanceIDs, By: req.By, Reason: req.Reason, DryRun: true,
+			}, opts...)
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "actions": actions})
+	}
+}
+
+// ipRelease is the body of POST /gcp/ips/release.
+type ipRelease struct {
+	Project   string   `json:"project" binding:"required"`
+	Addresses []string `json:"addresses" binding:"required"` // location/name, e.g. us-central1/ip-1
+	DryRun    *bool    `json:"dry_run"`                      // only true, the default, is accepted
+}
+
+// releaseIPs returns the plan for releasing unused reserved addresses. It
+// never changes anything: addresses are released by approved plans.
+func releaseIPs(c *gin.Context) {
+	var req ipRelease
+	if !bindDryRun(c, &req, &req.DryRun) {
+		return
+	}
+	actions, err := unused_gcp_resources.ReleaseAddresses(c.Request.Context(), req.Project, req.Addresses, true, nil)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": true, "actions": actions})
+}
+
+// bindDryRun binds the JSON body of a remediation preview into req and
+// refuses "dry_run": false, since only an approved plan may change
+// anything. It reports whether the handler should go on.
+func bindDryRun(c *gin.Context, req any, dryRun **bool) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("body: %w", err)})
+		return false
+	}
+	if *dryRun != nil && !**dryRun {
+		abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: errors.New("dry_run must be true: remediations run only as approved plans, see POST /plans")})
+		return false
+	}
+	return true
+}
+
+// abortWithError answers with the status and body every route outside /v1
+// uses for errors: {"error": message, "kind": kind}.
+func abortWithError(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(errorStatus(err), gin.H{"error": err.Error(), "kind": scanner.KindOf(err)})
+}
+
+// errorStatus maps a detector error to the HTTP status returned to clients.
+func errorStatus(err error) int {
+	switch scanner.KindOf(err) {
+	case scanner.KindNotFound:
+		return http.StatusNotFound
+	case scanner.KindThrottling:
+		return http.StatusServiceUnavailable
+	case scanner.KindConflict:
+		return http.StatusConflict
+	case scanner.KindInvalid:
+		return http.StatusBadRequest
+	case scanner.KindAuth, scanner.KindPermission:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// listScanners returns every registered detector.
+func listScanners(c *gin.Context) {
+	detectors := scanner.All()
+	result := make([]gin.H, 0, len(detectors))
+	for _, d := range detectors {
+		result = append(result, gin.H{
+			"name":          d.Name,
+			"provider":      d.Provider,
+			"resource_kind": d.ResourceKind,
+			"scope":         d.Scope,
+			"defaults":      d.Defaults,
+		})
+	}
+	c.JSON(http.StatusOK, result)
 }
 
 func main() {
+	configPath := flag.String("config", "", "scan configuration file (YAML or JSON); empty serves the built-in default")
+	flag.Parse()
+
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	catalog, err := pricing.Open(cfg.Pricing)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	audit, err := os.OpenFile(cfg.AuditLog(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer audit.Close()
+	plans, err := remediation.NewFileStore(cfg.Plans())
+	if err != nil {
+		log.Fatal(err)
+	}
+	runs, err := store.Open(context.Background(), cfg.Store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer runs.Close()
+
+	sched := scheduler.New(runs, catalog)
+	if err := sched.Schedule(cfg); err != nil {
+		log.Fatal(err)
+	}
+	sched.Start()
+	defer sched.Stop()
+
+	scanJobs := jobs.New(catalog, jobs.DefaultWorkers)
+	defer scanJobs.Close()
+
 	r := gin.Default()
+	routes(r, cfg, catalog, runs, sched, remediation.NewService(plans, remediation.Executors(audit)), scanJobs)
+	r.Run(cfg.Addr())
+}
 
+// routes registers every route of the server on r. openapi.json documents
+// them all but the legacy ones it lists under x-legacy-routes.
+func routes(r *gin.Engine, cfg *config.Config, catalog *pricing.Catalog, runs store.Store, sched *scheduler.Scheduler,
+	plans *remediation.Service, scanJobs *jobs.Manager) {
 	r.GET("/healthcheck", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status": "Okay",
 		})
 	})
 
-	r.GET("/aws/ebs", getAWSEBSData)
-	r.GET("/gcp/disks", getGCPUnusedDisk)
-	r.GET("/gcp/ips", getGCPUnusedIP)
+	r.GET("/scanners", listScanners)
+	r.GET("/scans", scansHandler(sched))
+	r.POST("/aws/ebs/remediate", remediateEBS)
+	r.POST("/gcp/ips/release", releaseIPs)
+	r.POST("/aws/ec2/stop", instancesHandler(false))
+	r.POST("/aws/ec2/start", instancesHandler(true))
+	plansAPI{plans: plans, cfg: cfg}.register(r)
+	v1API{cfg: cfg, catalog: catalog, jobs: scanJobs, runs: runs}.register(r)
+	r.GET("/openapi.json", serveOpenAPI)
+	r.NoRoute(func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, "/v1/") {
+			abortV1(c, &scanner.Error{Kind: scanner.KindNotFound, Resource: c.Request.URL.Path, Err: errors.New("no such endpoint")})
+			return
+		}
+		c.String(http.StatusNotFound, "404 page not found")
+	})
 
-	r.Run(":9090") // listen and serve on 0.0.0.0:8080 (for windows "localhost:8080")
+	// Routes per configured detector, e.g. GET /aws/ebs and
+	// GET /kpi/aws/ebs/history for "aws/ebs".
+	for _, scans := range store.ByDetector(cfg.Scans()) {
+		name := scans[0].Detector.Name
+		r.GET("/"+name, scanHandler(name, runs))
+		r.GET("/kpi/"+name+"/history", historyHandler(name, runs))
+	}
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+func TestScanHandlerServesStoredRuns(t *testing.T) {
+	_, runs := newV1(t)
+	r := gin.New()
+	r.GET("/aws/fake", scanHandler("aws/fake", runs))
+
+	var body map[string]any
+	if code := get(t, r, "/aws/fake", &body); code != http.StatusNotFound || body["kind"] != string(scanner.KindNotFound) {
+		t.Errorf("before a run: %d %v, want 404", code, body)
+	}
+
+	run := store.Run{ID: "run-1", Detector: "aws/fake", StartedAt: time.Now(), FinishedAt: time.Now(), Result: scanner.Result{TotalInstancesCount: 4, UnusedInstancesCount: 1}}
+	if err := runs.PutRun(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+	if code := get(t, r, "/aws/fake", &body); code != http.StatusOK || body["run_id"] != "run-1" || body["percentage"] != 25.0 {
+		t.Errorf("after a run: %d %v", code, body)
+	}
+}
+
+func TestRemediationRoutesOnlyPreview(t *testing.T) {
+	r := gin.New()
+	r.POST("/aws/ebs/remediate", remediateEBS)
+	r.POST("/gcp/ips/release", releaseIPs)
+	r.POST("/aws/ec2/stop", instancesHandler(false))
+	for target, payload := range map[string]string{
+		"/aws/ebs/remediate": `{"region": "us-east-1", "volume_ids": ["vol-1"], "dry_run": false}`,
+		"/gcp/ips/release":   `{"project": "p", "addresses": ["us-central1/ip-1"], "dry_run": false}`,
+		"/aws/ec2/stop":      `{"region": "us-east-1", "instance_ids": ["i-1"], "by": "alice", "dry_run": false}`,
+	} {
+		var body struct {
+			Error string       `json:"error"`
+			Kind  scanner.Kind `json:"kind"`
+		}
+		if code := do(t, r, http.MethodPost, target, payload, &body); code != http.StatusBadRequest || body.Kind != scanner.KindInvalid || body.Error == "" {
+			t.Errorf("%s: %d %+v, want executions refused", target, code, body)
+		}
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	r := newServer(t)
+
+	// Unknown /v1 endpoints answer with the /v1 error envelope.
+	var body apiError
+	if code := get(t, r, "/v1/aws/ebs/extra", &body); code != http.StatusNotFound || body.Error.Kind != scanner.KindNotFound {
+		t.Errorf("GET /v1/aws/ebs/extra: %d %+v, want not_found", code, body)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if w.Code != http.StatusNotFound || w.Body.String() != "404 page not found" {
+		t.Errorf("GET /nope: %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,210 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// DefaultOrganizationRole is the role AWS Organizations creates in member accounts.
+const DefaultOrganizationRole = "OrganizationAccountAccessRole"
+
+// DefaultAccountWorkers bounds how many accounts are scanned at once.
+const DefaultAccountWorkers = 4
+
+// roleSessionName identifies the scanner's sessions in CloudTrail.
+const roleSessionName = "unused-cloud-resources"
+
+// Account is an AWS account to scan.
+type Account struct {
+	ID    string // 12 digit account ID
+	Alias string // IAM account alias, or the organization's name for the account
+}
+
+// OrganizationsAPI is the subset of the Organizations client used to list member accounts.
+type OrganizationsAPI interface {
+	ListAccounts(ctx context.Context, params *organizations.ListAccountsInput, optFns ...func(*organizations.Options)) (*organizations.ListAccountsOutput, error)
+}
+
+// STSAPI is the subset of the STS client used to reach member accounts.
+type STSAPI interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+}
+
+// IAMAPI is the subset of the IAM client used to look up account aliases.
+type IAMAPI interface {
+	ListAccountAliases(ctx context.Context, params *iam.ListAccountAliasesInput, optFns ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error)
+}
+
+// WithOrganizationsClient uses client to list organization accounts.
+func WithOrganizationsClient(client OrganizationsAPI) Option {
+	return func(o *options) { o.organizations = client }
+}
+
+// WithSTSClient uses client to assume roles in member accounts.
+func WithSTSClient(client STSAPI) Option { return func(o *options) { o.sts = client } }
+
+// WithIAMClient uses client to look up account aliases.
+func WithIAMClient(client IAMAPI) Option { return func(o *options) { o.iam = client } }
+
+// AccountScanFunc scans one account. opts carry that account's credentials.
+type AccountScanFunc func(ctx context.Context, opts ...Option) (UnusedResourceMetrics, error)
+
+// OrganizationAccounts lists the active member accounts of the caller's organization.
+func OrganizationAccounts(ctx context.Context, opts ...Option) ([]Account, error) {
+	o := newOptions(opts)
+	client := o.organizations
+	if client == nil {
+		cfg, err := o.config(ctx, "")
+		if err != nil {
+			return nil, wrapError("LoadDefaultConfig", "", err)
+		}
+		client = organizations.NewFromConfig(cfg)
+	}
+
+	var accounts []Account
+	paginator := organizations.NewListAccountsPaginator(client, &organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, wrapError("ListAccounts", "", err)
+		}
+		for _, a := range page.Accounts {
+			if a.Status != orgTypes.AccountStatusActive {
+				continue
+			}
+			accounts = append(accounts, Account{ID: aws.ToString(a.Id), Alias: aws.ToString(a.Name)})
+		}
+	}
+	return accounts, nil
+}
+
+// AssumeRole returns an AWS config whose credentials come from assuming
+// roleName in accountID. The role is assumed once up front so an account
+// that cannot be reached fails here rather than on its first API call.
+func AssumeRole(ctx context.Context, accountID, roleName string, opts ...Option) (aws.Config, error) {
+	o := newOptions(opts)
+	base, err := o.config(ctx, "")
+	if err != nil {
+		return aws.Config{}, wrapError("LoadDefaultConfig", accountID, err)
+	}
+	client := o.sts
+	if client == nil {
+		client = sts.NewFromConfig(base)
+	}
+
+	roleARN := fmt.Sprintf("arn:%s:iam::%s:role/%s", partition(base.Region), accountID, roleName)
+	provider := stscreds.NewAssumeRoleProvider(client, roleARN, func(p *stscreds.AssumeRoleOptions) {
+		p.RoleSessionName = roleSessionName
+	})
+	cfg := base.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return aws.Config{}, wrapError("AssumeRole", roleARN, err)
+	}
+	return cfg, nil
+}
+
+// AccountOptions returns the options that reach accountID by assuming
+// roleName, or DefaultOrganizationRole when roleName is empty. An empty
+// accountID keeps the default credentials.
+func AccountOptions(ctx context.Context, accountID, roleName string, opts ...Option) ([]Option, error) {
+	if accountID == "" {
+		return opts, nil
+	}
+	if roleName == "" {
+		roleName = DefaultOrganizationRole
+	}
+	cfg, err := AssumeRole(ctx, accountID, roleName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, WithConfig(cfg)), nil
+}
+
+// ScanAccounts assumes roleName in every account, runs scan with the assumed
+// credentials using at most 'workers' goroutines, and merges the results with
+// the account stamped on every finding and per-account subtotals. Accounts
+// that cannot be reached are reported in Skipped; ScanAccounts only returns
+// an error when the context is cancelled or every account fails.
+func ScanAccounts(
+	ctx context.Context,
+	accounts []Account,
+	roleName string,
+	workers int,
+	scan AccountScanFunc,
+	opts ...Option,
+) (UnusedResourceMetrics, error) {
+	if workers <= 0 {
+		workers = DefaultAccountWorkers
+	}
+	aliases := make([]string, len(accounts))
+	results, errs := scanner.FanOut(ctx, len(accounts), workers, func(ctx context.Context, i int) (UnusedResourceMetrics, error) {
+		cfg, err := AssumeRole(ctx, accounts[i].ID, roleName, opts...)
+		if err != nil {
+			return UnusedResourceMetrics{}, err
+		}
+		aliases[i] = accountAlias(ctx, cfg, accounts[i], opts)
+		return scan(ctx, WithConfig(cfg))
+	})
+
+	if err := ctx.Err(); err != nil {
+		return UnusedResourceMetrics{}, wrapError("ScanAccounts", "", err)
+	}
+
+	merged := UnusedResourceMetrics{}
+	failed := 0
+	var firstErr error
+	for i, account := range accounts {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			skipped := scanner.Skip(account.ID, errs[i])
+			skipped.Account = account.ID
+			merged.Skipped = append(merged.Skipped, skipped)
+			continue
+		}
+		results[i].InAccount(account.ID, aliases[i])
+		merged.Merge(results[i])
+	}
+	if len(accounts) > 0 && failed == len(accounts) {
+		return UnusedResourceMetrics{}, firstErr
+	}
+	return merged, nil
+}
+
+// accountAlias returns the IAM alias of the account behind cfg, falling back
+// to the alias already known for the account when IAM has none or the call fails.
+func accountAlias(ctx context.Context, cfg aws.Config, account Account, opts []Option) string {
+	client := newOptions(opts).iam
+	if client == nil {
+		client = iam.NewFromConfig(cfg)
+	}
+	resp, err := client.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	if err != nil || len(resp.AccountAliases) == 0 {
+		return account.Alias
+	}
+	return resp.AccountAliases[0]
+}
+
+// partition returns the ARN partition for a region.
+func partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
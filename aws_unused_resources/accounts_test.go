@@ -0,0 +1,211 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// orgStandIn is a local stand-in for the STS, IAM and Organizations endpoints.
+// Assumed credentials use the account ID as access key so later calls can
+// tell which account they are made from.
+type orgStandIn struct {
+	accounts []map[string]string // Organizations ListAccounts entries
+	aliases  map[string]string   // IAM alias by account ID
+	denied   map[string]bool     // accounts whose role cannot be assumed
+	pageSize int
+}
+
+func (s *orgStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		s.listAccounts(w, r, target)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.Form.Get("Action") {
+	case "AssumeRole":
+		s.assumeRole(w, r.Form.Get("RoleArn"))
+	case "ListAccountAliases":
+		s.listAccountAliases(w, accessKey(r))
+	default:
+		http.Error(w, "unsupported action "+r.Form.Get("Action"), http.StatusBadRequest)
+	}
+}
+
+func (s *orgStandIn) listAccounts(w http.ResponseWriter, r *http.Request, target string) {
+	if target != "AWSOrganizationsV20161128.ListAccounts" {
+		http.Error(w, "unsupported target "+target, http.StatusBadRequest)
+		return
+	}
+	var in struct{ NextToken string }
+	json.NewDecoder(r.Body).Decode(&in)
+	start := 0
+	fmt.Sscan(in.NextToken, &start)
+	end := start + s.pageSize
+	out := map[string]any{}
+	if end >= len(s.accounts) {
+		end = len(s.accounts)
+	} else {
+		out["NextToken"] = fmt.Sprint(end)
+	}
+	out["Accounts"] = s.accounts[start:end]
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	json.NewEncoder(w).Encode(out)
+}
+
+func (s *orgStandIn) assumeRole(w http.ResponseWriter, roleARN string) {
+	account := strings.Split(roleARN, ":")[4]
+	w.Header().Set("Content-Type", "text/xml")
+	if s.denied[account] {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>not authorized to assume `+roleARN+`</Message></Error><RequestId>1</RequestId></ErrorResponse>`)
+		return
+	}
+	fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>%s</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser><Arn>%s</Arn><AssumedRoleId>AROA:%s</AssumedRoleId></AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>1</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`, account, roleARN, roleSessionName)
+}
+
+func (s *orgStandIn) listAccountAliases(w http.ResponseWriter, account string) {
+	aliases := ""
+	if alias, ok := s.aliases[account]; ok {
+		aliases = "<member>" + alias + "</member>"
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<ListAccountAliasesResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <ListAccountAliasesResult><AccountAliases>%s</AccountAliases><IsTruncated>false</IsTruncated></ListAccountAliasesResult>
+  <ResponseMetadata><RequestId>1</RequestId></ResponseMetadata>
+</ListAccountAliasesResponse>`, aliases)
+}
+
+// accessKey extracts the access key ID from a SigV4 Authorization header.
+func accessKey(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	i := strings.Index(auth, "Credential=")
+	if i < 0 {
+		return ""
+	}
+	return strings.SplitN(auth[i+len("Credential="):], "/", 2)[0]
+}
+
+// standInConfig points every client at the stand-in with static base credentials.
+func standInConfig(url string) aws.Config {
+	return aws.Config{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("management", "secret", ""),
+		BaseEndpoint: aws.String(url),
+	}
+}
+
+func TestOrganizationAccounts(t *testing.T) {
+	srv := httptest.NewServer(&orgStandIn{
+		pageSize: 1,
+		accounts: []map[string]string{
+			{"Id": "111111111111", "Name": "prod", "Status": "ACTIVE"},
+			{"Id": "222222222222", "Name": "old", "Status": "SUSPENDED"},
+			{"Id": "333333333333", "Name": "dev", "Status": "ACTIVE"},
+		},
+	})
+	defer srv.Close()
+
+	got, err := OrganizationAccounts(context.Background(), WithConfig(standInConfig(srv.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Account{{ID: "111111111111", Alias: "prod"}, {ID: "333333333333", Alias: "dev"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OrganizationAccounts() = %v, want %v", got, want)
+	}
+}
+
+func TestScanAccountsAssumesRoleInEachAccount(t *testing.T) {
+	srv := httptest.NewServer(&orgStandIn{
+		aliases: map[string]string{"111111111111": "prod-alias"},
+		denied:  map[string]bool{"333333333333": true},
+	})
+	defer srv.Close()
+
+	accounts := []Account{{ID: "111111111111", Alias: "prod"}, {ID: "222222222222", Alias: "staging"}, {ID: "333333333333"}}
+	scan := func(ctx context.Context, opts ...Option) (UnusedResourceMetrics, error) {
+		// The scan sees the assumed credentials, which the stand-in keys by account.
+		cfg, err := newOptions(opts).config(ctx, "")
+		if err != nil {
+			return UnusedResourceMetrics{}, err
+		}
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return UnusedResourceMetrics{}, err
+		}
+		id := "vol-" + creds.AccessKeyID
+		return UnusedResourceMetrics{
+			ResourceIDs:          []string{id},
+			TotalInstancesCount:  2,
+			UnusedInstancesCount: 1,
+			Findings:             []scanner.Finding{{ResourceID: id}},
+		}, nil
+	}
+
+	got, err := ScanAccounts(context.Background(), accounts, "FinOpsReadOnly", 2, scan, WithConfig(standInConfig(srv.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"vol-111111111111", "vol-222222222222"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if f := got.Findings[0]; f.Account != "111111111111" || f.AccountAlias != "prod-alias" {
+		t.Errorf("finding = %+v, want account and IAM alias", f)
+	}
+	if f := got.Findings[1]; f.AccountAlias != "staging" {
+		t.Errorf("alias = %q, want fallback to the organization name", f.AccountAlias)
+	}
+	if sub := got.Accounts["222222222222"]; sub.TotalInstancesCount != 2 || sub.UnusedInstancesCount != 1 {
+		t.Errorf("account subtotal = %+v", sub)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].Account != "333333333333" || got.Skipped[0].Kind != scanner.KindPermission {
+		t.Errorf("Skipped = %+v", got.Skipped)
+	}
+}
+
+func TestScanAccountsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scan := func(ctx context.Context, opts ...Option) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{}, nil
+	}
+	_, err := ScanAccounts(ctx, []Account{{ID: "111111111111"}}, "FinOpsReadOnly", 1, scan)
+	var e *scanner.Error
+	if !errors.As(err, &e) || e.Op != "ScanAccounts" || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %#v, want a scanner.Error from ScanAccounts wrapping context.Canceled", err)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	for region, want := range map[string]string{"us-east-1": "aws", "cn-north-1": "aws-cn", "us-gov-west-1": "aws-us-gov"} {
+		if got := partition(region); got != want {
+			t.Errorf("partition(%q) = %q, want %q", region, got, want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// EC2API is the subset of the EC2 client the detectors and remediations use.
+type EC2API interface {
+	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
+	DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)
+	CreateSnapshot(ctx context.Context, params *ec2.CreateSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error)
+	DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+	DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error)
+	StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+	DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error)
+}
+
+// CloudWatchAPI is the subset of the CloudWatch client the detectors use.
+type CloudWatchAPI interface {
+	GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+// RDSAPI is the subset of the RDS client the detectors use.
+type RDSAPI interface {
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+}
+
+// S3API is the subset of the S3 client the detectors use.
+type S3API interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+}
+
+// ELBv2API is the subset of the Elastic Load Balancing v2 client the detectors use.
+type ELBv2API interface {
+	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeTags(ctx context.Context, params *elasticloadbalancingv2.DescribeTagsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTagsOutput, error)
+}
+
+// Option overrides how a detector reaches AWS. Clients that are not
+// provided are built from the default credential chain for the region.
+type Option func(*options)
+
+type options struct {
+	cfg        *aws.Config
+	ec2        EC2API
+	cloudwatch CloudWatchAPI
+	regionalCW map[string]CloudWatchAPI
+	rds        RDSAPI
+	s3         S3API
+	regionalS3 map[string]S3API
+	elbv2      ELBv2API
+
+	organizations OrganizationsAPI
+	sts           STSAPI
+	iam           IAMAPI
+
+	pricing PricingAPI
+
+	tags scanner.TagRules
+
+	snapshotTimeout time.Duration
+}
+
+// WithConfig uses cfg instead of config.LoadDefaultConfig.
+func WithConfig(cfg aws.Config) Option { return func(o *options) { o.cfg = &cfg } }
+
+// WithTagRules leaves out resources whose tags the rules exclude.
+func WithTagRules(rules scanner.TagRules) Option { return func(o *options) { o.tags = rules } }
+
+// WithEC2Client uses client for EC2 calls.
+func WithEC2Client(client EC2API) Option { return func(o *options) { o.ec2 = client } }
+
+// WithCloudWatchClient uses client for CloudWatch calls.
+func WithCloudWatchClient(client CloudWatchAPI) Option {
+	return func(o *options) { o.cloudwatch = client }
+}
+
+// WithRegionalCloudWatchClient uses client for CloudWatch calls in region,
+// ahead of WithCloudWatchClient.
+func WithRegionalCloudWatchClient(region string, client CloudWatchAPI) Option {
+	return func(o *options) {
+		if o.regionalCW == nil {
+			o.regionalCW = map[string]CloudWatchAPI{}
+		}
+		o.regionalCW[region] = client
+	}
+}
+
+// WithRDSClient uses client for RDS calls.
+func WithRDSClient(client RDSAPI) Option { return func(o *options) { o.rds = client } }
+
+// WithS3Client uses client for S3 calls.
+func WithS3Client(client S3API) Option { return func(o *options) { o.s3 = client } }
+
+// WithRegionalS3Client uses client for S3 calls in region, ahead of
+// WithS3Client.
+func WithRegionalS3Client(region string, client S3API) Option {
+	return func(o *options) {
+		if o.regionalS3 == nil {
+			o.regionalS3 = map[string]S3API{}
+		}
+		o.regionalS3[region] = client
+	}
+}
+
+// WithELBv2Client uses client for Elastic Load Balancing v2 calls.
+func WithELBv2Client(client ELBv2API) Option { return func(o *options) { o.elbv2 = client } }
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// fallbackRegion is used for account-wide calls when no region is configured.
+const fallbackRegion = "us-east-1"
+
+// config returns the AWS config for region, loading it on first use.
+func (o *options) config(ctx context.Context, region string) (aws.Config, error) {
+	if o.cfg == nil {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+		if err != nil {
+			return aws.Config{}, err
+		}
+		o.cfg = &cfg
+	}
+	cfg := o.cfg.Copy()
+	if region != "" {
+		cfg.Region = region
+	}
+	if cfg.Region == "" {
+		cfg.Region = fallbackRegion
+	}
+	return cfg, nil
+}
+
+func (o *options) ec2Client(ctx context.Context, region string) (EC2API, error) {
+	if o.ec2 != nil {
+		return o.ec2, nil
+	}
+	cfg, err := o.config(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return ec2.NewFromConfig(cfg), nil
+}
+
+func (o *options) cloudwatchClient(ctx context.Context, region string) (CloudWatchAPI, error) {
+	if client := o.regionalCW[region]; client != nil {
+		return client, nil
+	}
+	if o.cloudwatch != nil {
+		return o.cloudwatch, nil
+	}
+	cfg, err := o.config(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return cloudwatch.NewFromConfig(cfg), nil
+}
+
+func (o *options) rdsClient(ctx context.Context, region string) (RDSAPI, error) {
+	if o.rds != nil {
+		return o.rds, nil
+	}
+	cfg, err := o.config(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return rds.NewFromConfig(cfg), nil
+}
+
+func (o *options) s3Client(ctx context.Context, region string) (S3API, error) {
+	if client := o.regionalS3[region]; client != nil {
+		return client, nil
+	}
+	if o.s3 != nil {
+		return o.s3, nil
+	}
+	cfg, err := o.config(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+func (o *options) elbv2Client(ctx context.Context, region string) (ELBv2API, error) {
+	if o.elbv2 != nil {
+		return o.elbv2, nil
+	}
+	cfg, err := o.config(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return elasticloadbalancingv2.NewFromConfig(cfg), nil
+}
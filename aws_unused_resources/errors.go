@@ -0,0 +1,91 @@
+package aws_unused_resources
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Error codes AWS services use for credential and authorization failures.
+var (
+	authErrorCodes = map[string]bool{
+		"AuthFailure":                 true,
+		"ExpiredToken":                true,
+		"ExpiredTokenException":       true,
+		"InvalidAccessKeyId":          true,
+		"InvalidClientTokenId":        true,
+		"RequestExpired":              true,
+		"SignatureDoesNotMatch":       true,
+		"UnrecognizedClientException": true,
+	}
+	permissionErrorCodes = map[string]bool{
+		"AccessDenied":          true,
+		"AccessDeniedException": true,
+		"AuthorizationError":    true,
+		"Forbidden":             true,
+		"UnauthorizedOperation": true,
+	}
+	conflictErrorCodes = map[string]bool{
+		"IncorrectState":         true,
+		"InvalidVolume.InUse":    true,
+		"VolumeInUse":            true,
+		"IncorrectInstanceState": true,
+	}
+)
+
+// wrapError classifies an AWS SDK error into a *scanner.Error.
+func wrapError(op, resource string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *scanner.Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	return &scanner.Error{Kind: classify(err), Op: op, Resource: resource, Err: err}
+}
+
+// classify maps an AWS SDK error to a scanner.Kind using the API error code,
+// falling back to the HTTP status of the response.
+func classify(err error) scanner.Kind {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		if _, ok := retry.DefaultThrottleErrorCodes[code]; ok {
+			return scanner.KindThrottling
+		}
+		switch {
+		case authErrorCodes[code]:
+			return scanner.KindAuth
+		case permissionErrorCodes[code]:
+			return scanner.KindPermission
+		case conflictErrorCodes[code]:
+			return scanner.KindConflict
+		case strings.Contains(code, "NotFound"), strings.HasPrefix(code, "NoSuch"):
+			return scanner.KindNotFound
+		}
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case http.StatusUnauthorized:
+			return scanner.KindAuth
+		case http.StatusForbidden:
+			return scanner.KindPermission
+		case http.StatusNotFound:
+			return scanner.KindNotFound
+		case http.StatusTooManyRequests:
+			return scanner.KindThrottling
+		}
+	}
+	// The credential providers do not export a typed error.
+	if strings.Contains(err.Error(), "failed to refresh cached credentials") {
+		return scanner.KindAuth
+	}
+	return scanner.KindUnknown
+}
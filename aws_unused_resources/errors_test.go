@@ -0,0 +1,54 @@
+package aws_unused_resources
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestClassify(t *testing.T) {
+	withStatus := func(code int) error {
+		return &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: code}},
+			Err:      errors.New("failed"),
+		}}
+	}
+	tests := []struct {
+		err  error
+		want scanner.Kind
+	}{
+		{&smithy.GenericAPIError{Code: "RequestLimitExceeded"}, scanner.KindThrottling},
+		{&smithy.GenericAPIError{Code: "SlowDown"}, scanner.KindThrottling},
+		{&smithy.GenericAPIError{Code: "ExpiredToken"}, scanner.KindAuth},
+		{&smithy.GenericAPIError{Code: "AccessDenied"}, scanner.KindPermission},
+		{&smithy.GenericAPIError{Code: "InvalidVolume.NotFound"}, scanner.KindNotFound},
+		{&smithy.GenericAPIError{Code: "NoSuchBucket"}, scanner.KindNotFound},
+		{&smithy.GenericAPIError{Code: "VolumeInUse"}, scanner.KindConflict},
+		{fmt.Errorf("op: %w", &smithy.GenericAPIError{Code: "DBInstanceNotFound"}), scanner.KindNotFound},
+		{withStatus(http.StatusForbidden), scanner.KindPermission},
+		{withStatus(http.StatusTooManyRequests), scanner.KindThrottling},
+		{errors.New("failed to refresh cached credentials, no EC2 IMDS role found"), scanner.KindAuth},
+		{errors.New("boom"), scanner.KindUnknown},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.err); got != tt.want {
+			t.Errorf("classify(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWrapErrorKeepsClassifiedErrors(t *testing.T) {
+	inner := wrapError("DescribeVolumes", "us-east-1", &smithy.GenericAPIError{Code: "Throttling"})
+	if got := wrapError("Outer", "", inner); got != inner {
+		t.Errorf("wrapError re-wrapped an already classified error: %v", got)
+	}
+	if !errors.Is(inner, scanner.ErrThrottled) {
+		t.Errorf("errors.Is(%v, ErrThrottled) = false", inner)
+	}
+}
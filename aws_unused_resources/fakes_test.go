@@ -0,0 +1,431 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2Types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	awsPricing "github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// pageOf returns the page of items starting at token and the token of the
+// next page, so every fake paginates the same way the real APIs do.
+func pageOf[T any](items []T, token *string, size int) ([]T, *string) {
+	start := 0
+	if token != nil {
+		start, _ = strconv.Atoi(*token)
+	}
+	if size <= 0 {
+		size = len(items)
+	}
+	end := start + size
+	if end >= len(items) {
+		return items[start:], nil
+	}
+	return items[start:end], aws.String(strconv.Itoa(end))
+}
+
+// fakeEC2 serves volumes, instances and VPCs from memory.
+type fakeEC2 struct {
+	pageSize  int
+	volumes   []ec2Types.Volume
+	instances []ec2Types.Instance
+	vpcs      []ec2Types.Vpc
+	regions   []string
+	err       error
+
+	deniedOps map[string]bool     // operations whose DryRun check is refused
+	protected map[string]bool     // instances with termination protection
+	failOps   map[string]bool     // operations that fail when not a DryRun
+	snapshots []ec2Types.Snapshot // snapshots created, completed immediately
+	deleted   []string            // volumes deleted
+	inputs    []any               // mutating requests in call order
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *fakeEC2) record(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[op]++
+}
+
+func (f *fakeEC2) DescribeVolumes(ctx context.Context, in *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	f.record("DescribeVolumes")
+	if f.err != nil {
+		return nil, f.err
+	}
+	volumes := f.volumes
+	if len(in.VolumeIds) > 0 {
+		volumes = nil
+		for _, id := range in.VolumeIds {
+			found := false
+			for _, v := range f.volumes {
+				if aws.ToString(v.VolumeId) == id {
+					volumes, found = append(volumes, v), true
+				}
+			}
+			if !found {
+				return nil, &smithy.GenericAPIError{Code: "InvalidVolume.NotFound", Message: "The volume '" + id + "' does not exist."}
+			}
+		}
+	}
+	items, next := pageOf(volumes, in.NextToken, f.pageSize)
+	return &ec2.DescribeVolumesOutput{Volumes: items, NextToken: next}, nil
+}
+
+// instance returns the stored instance with the given ID.
+func (f *fakeEC2) instance(id string) *ec2Types.Instance {
+	for i := range f.instances {
+		if aws.ToString(f.instances[i].InstanceId) == id {
+			return &f.instances[i]
+		}
+	}
+	return nil
+}
+
+// mutate records a mutating request, failing it when the operation is in failOps.
+func (f *fakeEC2) mutate(op string, in any) error {
+	if f.failOps[op] {
+		return &smithy.GenericAPIError{Code: "IncorrectInstanceState", Message: op + " failed"}
+	}
+	f.inputs = append(f.inputs, in)
+	return nil
+}
+
+func (f *fakeEC2) DescribeInstanceAttribute(ctx context.Context, in *ec2.DescribeInstanceAttributeInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error) {
+	f.record("DescribeInstanceAttribute")
+	return &ec2.DescribeInstanceAttributeOutput{
+		InstanceId:            in.InstanceId,
+		DisableApiTermination: &ec2Types.AttributeBooleanValue{Value: aws.Bool(f.protected[aws.ToString(in.InstanceId)])},
+	}, nil
+}
+
+func (f *fakeEC2) StopInstances(ctx context.Context, in *ec2.StopInstancesInput, _ ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	f.record("StopInstances")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("StopInstances")
+	}
+	if err := f.mutate("StopInstances", in); err != nil {
+		return nil, err
+	}
+	for _, id := range in.InstanceIds {
+		f.instance(id).State = &ec2Types.InstanceState{Name: ec2Types.InstanceStateNameStopped}
+	}
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (f *fakeEC2) StartInstances(ctx context.Context, in *ec2.StartInstancesInput, _ ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	f.record("StartInstances")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("StartInstances")
+	}
+	if err := f.mutate("StartInstances", in); err != nil {
+		return nil, err
+	}
+	for _, id := range in.InstanceIds {
+		f.instance(id).State = &ec2Types.InstanceState{Name: ec2Types.InstanceStateNameRunning}
+	}
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+func (f *fakeEC2) CreateTags(ctx context.Context, in *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	f.record("CreateTags")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("CreateTags")
+	}
+	if err := f.mutate("CreateTags", in); err != nil {
+		return nil, err
+	}
+	for _, id := range in.Resources {
+		inst := f.instance(id)
+		inst.Tags = append(inst.Tags, in.Tags...)
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (f *fakeEC2) DeleteTags(ctx context.Context, in *ec2.DeleteTagsInput, _ ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	f.record("DeleteTags")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("DeleteTags")
+	}
+	if err := f.mutate("DeleteTags", in); err != nil {
+		return nil, err
+	}
+	for _, id := range in.Resources {
+		inst := f.instance(id)
+		inst.Tags = slices.DeleteFunc(inst.Tags, func(t ec2Types.Tag) bool {
+			return slices.ContainsFunc(in.Tags, func(d ec2Types.Tag) bool { return aws.ToString(d.Key) == aws.ToString(t.Key) })
+		})
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+// dryRun answers a DryRun request the way EC2 does.
+func (f *fakeEC2) dryRun(op string) error {
+	if f.deniedOps[op] {
+		return &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "You are not authorized to perform this operation."}
+	}
+	return &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+}
+
+func (f *fakeEC2) CreateSnapshot(ctx context.Context, in *ec2.CreateSnapshotInput, _ ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	f.record("CreateSnapshot")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("CreateSnapshot")
+	}
+	f.inputs = append(f.inputs, in)
+	id := "snap-" + strconv.Itoa(len(f.snapshots)+1)
+	f.snapshots = append(f.snapshots, ec2Types.Snapshot{
+		SnapshotId: aws.String(id),
+		VolumeId:   in.VolumeId,
+		State:      ec2Types.SnapshotStateCompleted,
+		Tags:       in.TagSpecifications[0].Tags,
+	})
+	return &ec2.CreateSnapshotOutput{SnapshotId: aws.String(id), VolumeId: in.VolumeId, State: ec2Types.SnapshotStatePending}, nil
+}
+
+func (f *fakeEC2) DescribeSnapshots(ctx context.Context, in *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	f.record("DescribeSnapshots")
+	out := &ec2.DescribeSnapshotsOutput{}
+	for _, snap := range f.snapshots {
+		for _, id := range in.SnapshotIds {
+			if aws.ToString(snap.SnapshotId) == id {
+				out.Snapshots = append(out.Snapshots, snap)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEC2) DeleteVolume(ctx context.Context, in *ec2.DeleteVolumeInput, _ ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	f.record("DeleteVolume")
+	if aws.ToBool(in.DryRun) {
+		return nil, f.dryRun("DeleteVolume")
+	}
+	f.inputs = append(f.inputs, in)
+	f.deleted = append(f.deleted, aws.ToString(in.VolumeId))
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeInstances(ctx context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.record("DescribeInstances")
+	if f.err != nil {
+		return nil, f.err
+	}
+	var matched []ec2Types.Instance
+	for _, inst := range f.instances {
+		if len(in.InstanceIds) > 0 && !slices.Contains(in.InstanceIds, aws.ToString(inst.InstanceId)) {
+			continue
+		}
+		if instanceMatches(inst, in.Filters) {
+			matched = append(matched, inst)
+		}
+	}
+	items, next := pageOf(matched, in.NextToken, f.pageSize)
+	out := &ec2.DescribeInstancesOutput{NextToken: next}
+	for _, inst := range items {
+		out.Reservations = append(out.Reservations, ec2Types.Reservation{Instances: []ec2Types.Instance{inst}})
+	}
+	return out, nil
+}
+
+func (f *fakeEC2) DescribeVpcs(ctx context.Context, in *ec2.DescribeVpcsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.record("DescribeVpcs")
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.vpcs, in.NextToken, f.pageSize)
+	return &ec2.DescribeVpcsOutput{Vpcs: items, NextToken: next}, nil
+}
+
+// instanceMatches applies the DescribeInstances filters the detectors use.
+func instanceMatches(inst ec2Types.Instance, filters []ec2Types.Filter) bool {
+	for _, f := range filters {
+		var value string
+		switch aws.ToString(f.Name) {
+		case "instance-state-name":
+			value = instanceState(inst)
+		case "vpc-id":
+			value = aws.ToString(inst.VpcId)
+		case "tag-key":
+			if _, ok := ec2Tags(inst.Tags)[f.Values[0]]; !ok {
+				return false
+			}
+			continue
+		default:
+			continue
+		}
+		found := false
+		for _, v := range f.Values {
+			if v == value {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeCloudWatch returns datapoints keyed by the first dimension value.
+type fakeCloudWatch struct {
+	datapoints map[string][]cwTypes.Datapoint
+	errs       map[string]error
+
+	mu     sync.Mutex
+	inputs []*cloudwatch.GetMetricStatisticsInput
+}
+
+func (f *fakeCloudWatch) GetMetricStatistics(ctx context.Context, in *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	f.mu.Lock()
+	f.inputs = append(f.inputs, in)
+	f.mu.Unlock()
+	key := aws.ToString(in.Dimensions[0].Value)
+	if err := f.errs[key]; err != nil {
+		return nil, err
+	}
+	return &cloudwatch.GetMetricStatisticsOutput{Datapoints: f.datapoints[key]}, nil
+}
+
+// averages builds datapoints carrying the Average statistic.
+func averages(values ...float64) []cwTypes.Datapoint {
+	var result []cwTypes.Datapoint
+	for _, v := range values {
+		result = append(result, cwTypes.Datapoint{Average: aws.Float64(v)})
+	}
+	return result
+}
+
+// sums builds datapoints carrying the Sum statistic.
+func sums(values ...float64) []cwTypes.Datapoint {
+	var result []cwTypes.Datapoint
+	for _, v := range values {
+		result = append(result, cwTypes.Datapoint{Sum: aws.Float64(v)})
+	}
+	return result
+}
+
+// fakeRDS serves DB instances from memory.
+type fakeRDS struct {
+	pageSize  int
+	instances []rdsTypes.DBInstance
+	err       error
+}
+
+func (f *fakeRDS) DescribeDBInstances(ctx context.Context, in *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.instances, in.Marker, f.pageSize)
+	return &rds.DescribeDBInstancesOutput{DBInstances: items, Marker: next}, nil
+}
+
+// fakeS3 serves buckets from memory.
+type fakeS3 struct {
+	pageSize int
+	buckets  []s3Types.Bucket
+	tags     map[string][]s3Types.Tag // by bucket name; missing buckets have no tag set
+	region   string                   // when set, tags of buckets in other regions are refused, as S3 does
+	err      error
+}
+
+func (f *fakeS3) ListBuckets(ctx context.Context, in *s3.ListBucketsInput, _ ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.buckets, in.ContinuationToken, f.pageSize)
+	return &s3.ListBucketsOutput{Buckets: items, ContinuationToken: next}, nil
+}
+
+func (f *fakeS3) GetBucketTagging(ctx context.Context, in *s3.GetBucketTaggingInput, _ ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	for _, b := range f.buckets {
+		if f.region != "" && aws.ToString(b.Name) == aws.ToString(in.Bucket) && aws.ToString(b.BucketRegion) != f.region {
+			return nil, &smithy.GenericAPIError{Code: "PermanentRedirect", Message: "The bucket you are attempting to access must be addressed using the specified endpoint"}
+		}
+	}
+	tags, ok := f.tags[aws.ToString(in.Bucket)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "NoSuchTagSet", Message: "The TagSet does not exist"}
+	}
+	return &s3.GetBucketTaggingOutput{TagSet: tags}, nil
+}
+
+// fakeELBv2 serves load balancers from memory.
+type fakeELBv2 struct {
+	pageSize      int
+	loadBalancers []elbv2Types.LoadBalancer
+	tags          map[string][]elbv2Types.Tag // by load balancer ARN
+	tagCalls      int
+	err           error
+}
+
+func (f *fakeELBv2) DescribeLoadBalancers(ctx context.Context, in *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.loadBalancers, in.Marker, f.pageSize)
+	return &elasticloadbalancingv2.DescribeLoadBalancersOutput{LoadBalancers: items, NextMarker: next}, nil
+}
+
+func (f *fakeELBv2) DescribeTags(ctx context.Context, in *elasticloadbalancingv2.DescribeTagsInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTagsOutput, error) {
+	f.tagCalls++
+	if len(in.ResourceArns) > describeTagsBatch {
+		return nil, fmt.Errorf("too many ARNs: %d", len(in.ResourceArns))
+	}
+	out := &elasticloadbalancingv2.DescribeTagsOutput{}
+	for _, arn := range in.ResourceArns {
+		out.TagDescriptions = append(out.TagDescriptions, elbv2Types.TagDescription{ResourceArn: aws.String(arn), Tags: f.tags[arn]})
+	}
+	return out, nil
+}
+
+func (f *fakeEC2) DescribeRegions(ctx context.Context, in *ec2.DescribeRegionsInput, _ ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	f.record("DescribeRegions")
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := &ec2.DescribeRegionsOutput{}
+	for _, name := range f.regions {
+		out.Regions = append(out.Regions, ec2Types.Region{RegionName: aws.String(name)})
+	}
+	return out, nil
+}
+
+// fakePricing serves Price List documents keyed by service code and product
+// family, one document per page.
+type fakePricing struct {
+	products map[string][]string
+	inputs   []*awsPricing.GetProductsInput
+}
+
+func (f *fakePricing) GetProducts(ctx context.Context, in *awsPricing.GetProductsInput, _ ...func(*awsPricing.Options)) (*awsPricing.GetProductsOutput, error) {
+	f.inputs = append(f.inputs, in)
+	family := ""
+	for _, filter := range in.Filters {
+		if aws.ToString(filter.Field) == "productFamily" {
+			family = aws.ToString(filter.Value)
+		}
+	}
+	docs, next := pageOf(f.products[aws.ToString(in.ServiceCode)+"/"+family], in.NextToken, 1)
+	return &awsPricing.GetProductsOutput{PriceList: docs, NextToken: next}, nil
+}
@@ -0,0 +1,23 @@
+package aws_unused_resources
+
+import (
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// averageOf returns the mean of the Average statistic across datapoints.
+// Datapoints without an Average are ignored; no datapoints yields 0.
+func averageOf(datapoints []cwTypes.Datapoint) float64 {
+	var sum float64
+	count := 0
+	for _, dp := range datapoints {
+		if dp.Average == nil {
+			continue
+		}
+		sum += *dp.Average
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,29 @@
+package aws_unused_resources
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+func TestAverageOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		datapoints []cwTypes.Datapoint
+		want       float64
+	}{
+		{"no datapoints", nil, 0},
+		{"single", averages(4), 4},
+		{"mean", averages(1, 2, 3), 2},
+		{"missing average ignored", []cwTypes.Datapoint{{}, {Average: aws.Float64(6)}}, 6},
+		{"only missing", []cwTypes.Datapoint{{}, {}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := averageOf(tt.datapoints); got != tt.want {
+				t.Errorf("averageOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsPricing "github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingTypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+// priceListRegion hosts the AWS Price List API.
+const priceListRegion = "us-east-1"
+
+// PricingAPI is the subset of the Price List client used to refresh the pricing catalog.
+type PricingAPI interface {
+	GetProducts(ctx context.Context, params *awsPricing.GetProductsInput, optFns ...func(*awsPricing.Options)) (*awsPricing.GetProductsOutput, error)
+}
+
+// WithPricingClient uses client to query the AWS Price List.
+func WithPricingClient(client PricingAPI) Option { return func(o *options) { o.pricing = client } }
+
+// priceQuery selects a family of products from the Price List and names the
+// catalog SKU of each product; an empty SKU drops the product.
+type priceQuery struct {
+	service     string            // catalog service
+	serviceCode string            // Price List service code
+	filters     map[string]string // TERM_MATCH filters besides the region
+	sku         func(attributes map[string]string) string
+}
+
+// priceQueries lists the products each detector's findings are priced with.
+var priceQueries = []priceQuery{
+	{
+		service:     "ebs",
+		serviceCode: "AmazonEC2",
+		filters:     map[string]string{"productFamily": "Storage"},
+		sku:         func(a map[string]string) string { return a["volumeApiName"] },
+	},
+	{
+		service:     "ebs",
+		serviceCode: "AmazonEC2",
+		filters:     map[string]string{"productFamily": "System Operation", "group": "EBS IOPS"},
+		sku:         func(a map[string]string) string { return a["volumeApiName"] },
+	},
+	{
+		service:     "ec2",
+		serviceCode: "AmazonEC2",
+		filters: map[string]string{
+			"productFamily":   "Compute Instance",
+			"operatingSystem": "Linux",
+			"tenancy":         "Shared",
+			"preInstalledSw":  "NA",
+			"capacitystatus":  "Used",
+		},
+		sku: func(a map[string]string) string { return a["instanceType"] },
+	},
+	{
+		service:     "rds",
+		serviceCode: "AmazonRDS",
+		filters:     map[string]string{"productFamily": "Database Instance", "deploymentOption": "Single-AZ"},
+		sku: func(a map[string]string) string {
+			engine, ok := rdsEngines[a["databaseEngine"]]
+			if !ok {
+				return ""
+			}
+			return a["instanceType"] + "/" + engine
+		},
+	},
+	{
+		service:     "rds-storage",
+		serviceCode: "AmazonRDS",
+		filters:     map[string]string{"productFamily": "Database Storage", "deploymentOption": "Single-AZ"},
+		sku:         func(a map[string]string) string { return rdsStorageTypes[a["volumeType"]] },
+	},
+	{
+		service:     "elb",
+		serviceCode: "AWSELB",
+		filters:     map[string]string{"productFamily": "Load Balancer-Application"},
+		sku:         func(map[string]string) string { return "application" },
+	},
+	{
+		service:     "elb",
+		serviceCode: "AWSELB",
+		filters:     map[string]string{"productFamily": "Load Balancer-Network"},
+		sku:         func(map[string]string) string { return "network" },
+	},
+	{
+		service:     "elb",
+		serviceCode: "AWSELB",
+		filters:     map[string]string{"productFamily": "Load Balancer-Gateway"},
+		sku:         func(map[string]string) string { return "gateway" },
+	},
+}
+
+// rdsEngines maps Price List engine names to the engine DescribeDBInstances reports.
+var rdsEngines = map[string]string{
+	"MySQL":             "mysql",
+	"PostgreSQL":        "postgres",
+	"MariaDB":           "mariadb",
+	"Aurora MySQL":      "aurora-mysql",
+	"Aurora PostgreSQL": "aurora-postgresql",
+}
+
+// rdsStorageTypes maps Price List volume types to RDS storage types.
+var rdsStorageTypes = map[string]string{
+	"General Purpose":     "gp2",
+	"General Purpose-GP3": "gp3",
+	"Provisioned IOPS":    "io1",
+	"Magnetic":            "standard",
+}
+
+// priceListProduct is the part of a Price List product document the catalog needs.
+type priceListProduct struct {
+	Product struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				Unit         string            `json:"unit"`
+				BeginRange   string            `json:"beginRange"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// PriceList fetches on-demand list prices for the given regions from the
+// AWS Price List API, in the form the pricing catalog stores them.
+func PriceList(ctx context.Context, regions []string, opts ...Option) ([]pricing.Price, error) {
+	o := newOptions(opts)
+	client := o.pricing
+	if client == nil {
+		cfg, err := o.config(ctx, priceListRegion)
+		if err != nil {
+			return nil, wrapError("LoadDefaultConfig", priceListRegion, err)
+		}
+		client = awsPricing.NewFromConfig(cfg)
+	}
+
+	var prices []pricing.Price
+	for _, region := range regions {
+		for _, q := range priceQueries {
+			filters := []pricingTypes.Filter{termMatch("regionCode", region)}
+			for field, value := range q.filters {
+				filters = append(filters, termMatch(field, value))
+			}
+			paginator := awsPricing.NewGetProductsPaginator(client, &awsPricing.GetProductsInput{
+				ServiceCode:   aws.String(q.serviceCode),
+				Filters:       filters,
+				FormatVersion: aws.String("aws_v1"),
+			})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, wrapError("GetProducts", q.serviceCode+"/"+region, err)
+				}
+				for _, doc := range page.PriceList {
+					prices = append(prices, parsePriceListProduct(doc, q, region)...)
+				}
+			}
+		}
+	}
+	return prices, nil
+}
+
+// parsePriceListProduct extracts the first-tier on-demand USD prices of one
+// Price List product document. Documents that do not parse are ignored.
+func parsePriceListProduct(doc string, q priceQuery, region string) []pricing.Price {
+	var product priceListProduct
+	if err := json.Unmarshal([]byte(doc), &product); err != nil {
+		return nil
+	}
+	sku := q.sku(product.Product.Attributes)
+	if sku == "" {
+		return nil
+	}
+	var prices []pricing.Price
+	for _, term := range product.Terms.OnDemand {
+		for _, dim := range term.PriceDimensions {
+			unit := catalogUnit(dim.Unit)
+			if unit == "" || (dim.BeginRange != "" && dim.BeginRange != "0") {
+				continue
+			}
+			usd, err := strconv.ParseFloat(dim.PricePerUnit["USD"], 64)
+			if err != nil {
+				continue
+			}
+			prices = append(prices, pricing.Price{
+				Provider: scanner.AWS,
+				Service:  q.service,
+				Region:   region,
+				SKU:      sku,
+				Unit:     unit,
+				USD:      usd,
+			})
+		}
+	}
+	return prices
+}
+
+// catalogUnit maps a Price List unit to the catalog's, or "" when the
+// catalog does not use it. Network and gateway load balancers bill NLCU and
+// GLCU hours, which the catalog treats as LCU hours.
+func catalogUnit(unit string) string {
+	switch {
+	case unit == pricing.UnitGBMonth, unit == pricing.UnitIOPSMonth, unit == pricing.UnitHour:
+		return unit
+	case strings.HasSuffix(unit, pricing.UnitLCUHour):
+		return pricing.UnitLCUHour
+	default:
+		return ""
+	}
+}
+
+func termMatch(field, value string) pricingTypes.Filter {
+	return pricingTypes.Filter{Field: aws.String(field), Type: pricingTypes.FilterTypeTermMatch, Value: aws.String(value)}
+}
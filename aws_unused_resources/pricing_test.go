@@ -0,0 +1,68 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+// priceDoc builds a Price List product document with one on-demand dimension per unit/price pair.
+func priceDoc(attributes string, dimensions string) string {
+	return `{"product":{"attributes":` + attributes + `},"terms":{"OnDemand":{"T1":{"priceDimensions":` + dimensions + `}}}}`
+}
+
+func TestPriceList(t *testing.T) {
+	client := &fakePricing{products: map[string][]string{
+		"AmazonEC2/Storage": {
+			priceDoc(`{"volumeApiName":"gp3"}`, `{"D1":{"unit":"GB-Mo","pricePerUnit":{"USD":"0.0800000000"}}}`),
+			priceDoc(`{"volumeApiName":"sc1"}`, `{"D1":{"unit":"GB-Mo","pricePerUnit":{"USD":"0.0150000000"}}}`),
+		},
+		"AmazonEC2/System Operation": {
+			priceDoc(`{"volumeApiName":"io2"}`, `{"D1":{"unit":"IOPS-Mo","beginRange":"0","pricePerUnit":{"USD":"0.065"}},"D2":{"unit":"IOPS-Mo","beginRange":"32000","pricePerUnit":{"USD":"0.0455"}}}`),
+		},
+		"AmazonRDS/Database Instance": {
+			priceDoc(`{"instanceType":"db.t3.micro","databaseEngine":"PostgreSQL"}`, `{"D1":{"unit":"Hrs","pricePerUnit":{"USD":"0.018"}}}`),
+			priceDoc(`{"instanceType":"db.t3.micro","databaseEngine":"Oracle"}`, `{"D1":{"unit":"Hrs","pricePerUnit":{"USD":"0.04"}}}`),
+		},
+		"AWSELB/Load Balancer-Network": {
+			priceDoc(`{}`, `{"D1":{"unit":"Hrs","pricePerUnit":{"USD":"0.0225"}},"D2":{"unit":"NLCU-Hrs","pricePerUnit":{"USD":"0.006"}}}`),
+		},
+	}}
+
+	got, err := PriceList(context.Background(), []string{"us-east-1"}, WithPricingClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(got, func(i, j int) bool {
+		return got[i].Service+got[i].SKU+got[i].Unit < got[j].Service+got[j].SKU+got[j].Unit
+	})
+	price := func(service, sku, unit string, usd float64) pricing.Price {
+		return pricing.Price{Provider: scanner.AWS, Service: service, Region: "us-east-1", SKU: sku, Unit: unit, USD: usd}
+	}
+	want := []pricing.Price{
+		price("ebs", "gp3", pricing.UnitGBMonth, 0.08),
+		price("ebs", "io2", pricing.UnitIOPSMonth, 0.065),
+		price("ebs", "sc1", pricing.UnitGBMonth, 0.015),
+		price("elb", "network", pricing.UnitHour, 0.0225),
+		price("elb", "network", pricing.UnitLCUHour, 0.006),
+		price("rds", "db.t3.micro/postgres", pricing.UnitHour, 0.018),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prices = %+v\nwant %+v", got, want)
+	}
+
+	for _, in := range client.inputs {
+		if f := in.Filters[0]; aws.ToString(f.Field) != "regionCode" || aws.ToString(f.Value) != "us-east-1" {
+			t.Errorf("first filter = %s=%s, want regionCode=us-east-1", aws.ToString(f.Field), aws.ToString(f.Value))
+		}
+	}
+	if len(client.inputs) != len(priceQueries)+2 {
+		t.Errorf("GetProducts calls = %d, want one per query and page", len(client.inputs))
+	}
+}
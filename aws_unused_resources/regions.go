@@ -0,0 +1,78 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// DefaultRegionWorkers bounds how many regions are scanned at once.
+const DefaultRegionWorkers = 4
+
+// RegionScanFunc scans a single region.
+type RegionScanFunc func(ctx context.Context, region string) (UnusedResourceMetrics, error)
+
+// EnabledRegions returns the regions enabled for the account, sorted by name.
+func EnabledRegions(ctx context.Context, opts ...Option) ([]string, error) {
+	client, err := newOptions(opts).ec2Client(ctx, "")
+	if err != nil {
+		return nil, wrapError("LoadDefaultConfig", "", err)
+	}
+	// Without AllRegions, DescribeRegions only returns the enabled regions.
+	resp, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, wrapError("DescribeRegions", "", err)
+	}
+	regions := make([]string, 0, len(resp.Regions))
+	for _, r := range resp.Regions {
+		regions = append(regions, aws.ToString(r.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// ScanRegions runs scan for every region using at most 'workers' goroutines
+// and merges the results in region order, keeping per-region subtotals.
+// A region that fails is reported in Skipped; ScanRegions only returns an
+// error when the context is cancelled or every region fails.
+func ScanRegions(
+	ctx context.Context,
+	regions []string,
+	workers int,
+	scan RegionScanFunc,
+) (UnusedResourceMetrics, error) {
+	if workers <= 0 {
+		workers = DefaultRegionWorkers
+	}
+	results, errs := scanner.FanOut(ctx, len(regions), workers, func(ctx context.Context, i int) (UnusedResourceMetrics, error) {
+		return scan(ctx, regions[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return UnusedResourceMetrics{}, wrapError("ScanRegions", "", err)
+	}
+
+	merged := UnusedResourceMetrics{}
+	failed := 0
+	var firstErr error
+	for i, region := range regions {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			skipped := scanner.Skip(region, errs[i])
+			skipped.Region = region
+			merged.Skipped = append(merged.Skipped, skipped)
+			continue
+		}
+		merged.Merge(results[i])
+	}
+	if len(regions) > 0 && failed == len(regions) {
+		return UnusedResourceMetrics{}, firstErr
+	}
+	return merged, nil
+}
@@ -0,0 +1,116 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestEnabledRegions(t *testing.T) {
+	client := &fakeEC2{regions: []string{"us-west-2", "eu-west-1", "us-east-1"}}
+
+	got, err := EnabledRegions(context.Background(), WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"eu-west-1", "us-east-1", "us-west-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EnabledRegions() = %v, want %v", got, want)
+	}
+}
+
+func TestScanRegionsMergesInRegionOrder(t *testing.T) {
+	volumes := map[string][]ec2Types.Volume{
+		"us-east-1": {{VolumeId: aws.String("vol-e1")}, {VolumeId: aws.String("vol-e2"), Attachments: []ec2Types.VolumeAttachment{{}}}},
+		"us-west-2": {{VolumeId: aws.String("vol-w1")}},
+		"eu-west-1": {},
+	}
+	var running, peak int32
+	scan := func(ctx context.Context, region string) (UnusedResourceMetrics, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return Get_unused_ebs_volumes(ctx, region, WithEC2Client(&fakeEC2{volumes: volumes[region]}))
+	}
+
+	got, err := ScanRegions(context.Background(), []string{"eu-west-1", "us-east-1", "us-west-2"}, 2, scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"vol-e1", "vol-w1"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 3 || got.UnusedInstancesCount != 2 {
+		t.Errorf("counts = %d/%d, want 2/3", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	if sub := got.Regions["us-east-1"]; sub.TotalInstancesCount != 2 || sub.UnusedInstancesCount != 1 {
+		t.Errorf("us-east-1 subtotal = %+v", sub)
+	}
+	if _, ok := got.Regions["eu-west-1"]; !ok {
+		t.Error("regions with no resources should still report a subtotal")
+	}
+	if got.Findings[1].Region != "us-west-2" {
+		t.Errorf("finding region = %q, want us-west-2", got.Findings[1].Region)
+	}
+	if peak > 2 {
+		t.Errorf("%d regions scanned at once, want at most 2", peak)
+	}
+}
+
+func TestScanRegionsPartialFailure(t *testing.T) {
+	scan := func(ctx context.Context, region string) (UnusedResourceMetrics, error) {
+		if region == "ap-south-1" {
+			return UnusedResourceMetrics{}, wrapError("DescribeVolumes", region, &smithy.GenericAPIError{Code: "AuthFailure"})
+		}
+		return UnusedResourceMetrics{ResourceIDs: []string{region}, TotalInstancesCount: 1, UnusedInstancesCount: 1}, nil
+	}
+
+	got, err := ScanRegions(context.Background(), []string{"ap-south-1", "us-east-1"}, 0, scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].Region != "ap-south-1" || got.Skipped[0].Kind != scanner.KindAuth {
+		t.Errorf("Skipped = %+v", got.Skipped)
+	}
+	if got.TotalInstancesCount != 1 {
+		t.Errorf("TotalInstancesCount = %d, want 1", got.TotalInstancesCount)
+	}
+}
+
+func TestScanRegionsAllFail(t *testing.T) {
+	boom := errors.New("boom")
+	scan := func(ctx context.Context, region string) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{}, fmt.Errorf("%s: %w", region, boom)
+	}
+	if _, err := ScanRegions(context.Background(), []string{"a", "b"}, 2, scan); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom", err)
+	}
+}
+
+func TestScanRegionsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scan := func(ctx context.Context, region string) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{}, nil
+	}
+	_, err := ScanRegions(ctx, []string{"a", "b"}, 2, scan)
+	var e *scanner.Error
+	if !errors.As(err, &e) || e.Op != "ScanRegions" || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %#v, want a scanner.Error from ScanRegions wrapping context.Canceled", err)
+	}
+}
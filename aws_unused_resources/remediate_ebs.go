@@ -0,0 +1,180 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// DefaultSnapshotTimeout bounds how long a remediation waits for a snapshot
+// to complete before giving up on deleting its volume.
+const DefaultSnapshotTimeout = time.Hour
+
+// Tags set on every snapshot taken before a volume is deleted, so the volume
+// can be restored from the snapshot alone.
+const (
+	TagSourceVolume     = "finops:source-volume"
+	TagSourceZone       = "finops:source-availability-zone"
+	TagSourceVolumeType = "finops:source-volume-type"
+	TagRemediation      = "finops:remediation"
+)
+
+// remediationDeleteVolume is the TagRemediation value of snapshots taken by
+// SnapshotAndDeleteVolumes.
+const remediationDeleteVolume = "snapshot-then-delete"
+
+// dryRunOperation is the error code EC2 returns when a DryRun request would
+// have succeeded.
+const dryRunOperation = "DryRunOperation"
+
+// WithSnapshotTimeout overrides DefaultSnapshotTimeout.
+func WithSnapshotTimeout(d time.Duration) Option {
+	return func(o *options) { o.snapshotTimeout = d }
+}
+
+// VolumeAction is the plan for, and then the outcome of, snapshotting and
+// deleting one volume.
+type VolumeAction struct {
+	VolumeID         string `json:"volume_id"`
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availability_zone"`
+	VolumeType       string `json:"volume_type"`
+	SizeGiB          int32  `json:"size_gib"`
+	SnapshotID       string `json:"snapshot_id,omitempty"` // snapshot to restore the volume from
+	Deleted          bool   `json:"deleted"`
+	Error            string `json:"error,omitempty"` // why the volume was left in place
+}
+
+// SnapshotAndDeleteVolumes snapshots each volume, waits for the snapshot to
+// complete and then deletes the volume. It always plans first: the volumes
+// must exist and be unattached, and CreateSnapshot and DeleteVolume must pass
+// EC2's DryRun permission check for every one of them, otherwise nothing is
+// changed. With dryRun set it returns the plan without changing anything.
+//
+// A volume whose snapshot fails is not deleted; its action carries the error
+// and the returned error joins every such failure.
+func SnapshotAndDeleteVolumes(ctx context.Context, region string, volumeIDs []string, dryRun bool, opts ...Option) ([]VolumeAction, error) {
+	o := newOptions(opts)
+	client, err := o.ec2Client(ctx, region)
+	if err != nil {
+		return nil, wrapError("LoadDefaultConfig", region, err)
+	}
+	actions, err := planVolumeDeletion(ctx, client, region, volumeIDs)
+	if err != nil || dryRun {
+		return actions, err
+	}
+
+	timeout := o.snapshotTimeout
+	if timeout == 0 {
+		timeout = DefaultSnapshotTimeout
+	}
+	var errs []error
+	for i := range actions {
+		if err := snapshotAndDelete(ctx, client, &actions[i], timeout); err != nil {
+			actions[i].Error = err.Error()
+			errs = append(errs, err)
+		}
+	}
+	return actions, errors.Join(errs...)
+}
+
+// planVolumeDeletion describes the volumes and checks, with DryRun, that
+// each can be snapshotted and deleted.
+func planVolumeDeletion(ctx context.Context, client EC2API, region string, volumeIDs []string) ([]VolumeAction, error) {
+	if len(volumeIDs) == 0 {
+		return nil, nil
+	}
+	var actions []VolumeAction
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{VolumeIds: volumeIDs})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, wrapError("DescribeVolumes", region, err)
+		}
+		for _, volume := range page.Volumes {
+			id := aws.ToString(volume.VolumeId)
+			if len(volume.Attachments) > 0 || volume.State != ec2Types.VolumeStateAvailable {
+				return nil, &scanner.Error{Kind: scanner.KindConflict, Op: "DescribeVolumes", Resource: id,
+					Err: fmt.Errorf("volume is %s, only unattached volumes can be deleted", volume.State)}
+			}
+			actions = append(actions, VolumeAction{
+				VolumeID:         id,
+				Region:           region,
+				AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+				VolumeType:       string(volume.VolumeType),
+				SizeGiB:          aws.ToInt32(volume.Size),
+			})
+		}
+	}
+
+	for _, action := range actions {
+		_, err := client.CreateSnapshot(ctx, snapshotInput(action, true))
+		if err := dryRunError("CreateSnapshot", action.VolumeID, err); err != nil {
+			return nil, err
+		}
+		_, err = client.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(action.VolumeID), DryRun: aws.Bool(true)})
+		if err := dryRunError("DeleteVolume", action.VolumeID, err); err != nil {
+			return nil, err
+		}
+	}
+	return actions, nil
+}
+
+// snapshotAndDelete carries out one planned action, recording the snapshot
+// ID as soon as it is known.
+func snapshotAndDelete(ctx context.Context, client EC2API, action *VolumeAction, timeout time.Duration) error {
+	snapshot, err := client.CreateSnapshot(ctx, snapshotInput(*action, false))
+	if err != nil {
+		return wrapError("CreateSnapshot", action.VolumeID, err)
+	}
+	action.SnapshotID = aws.ToString(snapshot.SnapshotId)
+
+	waiter := ec2.NewSnapshotCompletedWaiter(client)
+	if err := waiter.Wait(ctx, &ec2.DescribeSnapshotsInput{SnapshotIds: []string{action.SnapshotID}}, timeout); err != nil {
+		return wrapError("DescribeSnapshots", action.SnapshotID, err)
+	}
+
+	if _, err := client.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(action.VolumeID)}); err != nil {
+		return wrapError("DeleteVolume", action.VolumeID, err)
+	}
+	action.Deleted = true
+	return nil
+}
+
+// snapshotInput is the tagged CreateSnapshot request for action.
+func snapshotInput(action VolumeAction, dryRun bool) *ec2.CreateSnapshotInput {
+	return &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(action.VolumeID),
+		Description: aws.String("Taken before deleting unused volume " + action.VolumeID),
+		DryRun:      aws.Bool(dryRun),
+		TagSpecifications: []ec2Types.TagSpecification{{
+			ResourceType: ec2Types.ResourceTypeSnapshot,
+			Tags: []ec2Types.Tag{
+				{Key: aws.String(TagSourceVolume), Value: aws.String(action.VolumeID)},
+				{Key: aws.String(TagSourceZone), Value: aws.String(action.AvailabilityZone)},
+				{Key: aws.String(TagSourceVolumeType), Value: aws.String(action.VolumeType)},
+				{Key: aws.String(TagRemediation), Value: aws.String(remediationDeleteVolume)},
+			},
+		}},
+	}
+}
+
+// dryRunError turns the outcome of a DryRun request into an error: nil when
+// EC2 answered DryRunOperation, the classified error otherwise.
+func dryRunError(op, resource string, err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == dryRunOperation {
+		return nil
+	}
+	if err == nil {
+		return &scanner.Error{Kind: scanner.KindUnknown, Op: op, Resource: resource, Err: errors.New("DryRun request was not rejected")}
+	}
+	return wrapError(op, resource, err)
+}
@@ -0,0 +1,106 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func availableVolume(id string) ec2Types.Volume {
+	return ec2Types.Volume{
+		VolumeId:         aws.String(id),
+		State:            ec2Types.VolumeStateAvailable,
+		AvailabilityZone: aws.String("us-east-1a"),
+		VolumeType:       ec2Types.VolumeTypeGp3,
+		Size:             aws.Int32(100),
+	}
+}
+
+func TestSnapshotAndDeleteVolumesDryRunChangesNothing(t *testing.T) {
+	client := &fakeEC2{volumes: []ec2Types.Volume{availableVolume("vol-1"), availableVolume("vol-2")}}
+
+	plan, err := SnapshotAndDeleteVolumes(context.Background(), "us-east-1", []string{"vol-1"}, true, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []VolumeAction{{VolumeID: "vol-1", Region: "us-east-1", AvailabilityZone: "us-east-1a", VolumeType: "gp3", SizeGiB: 100}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("plan = %+v, want %+v", plan, want)
+	}
+	if client.calls["CreateSnapshot"] != 1 || client.calls["DeleteVolume"] != 1 {
+		t.Errorf("calls = %v, want one DryRun of each operation", client.calls)
+	}
+	if len(client.inputs) != 0 {
+		t.Errorf("dry run made changes: %v", client.inputs)
+	}
+}
+
+func TestSnapshotAndDeleteVolumes(t *testing.T) {
+	client := &fakeEC2{volumes: []ec2Types.Volume{availableVolume("vol-1"), availableVolume("vol-2")}}
+
+	got, err := SnapshotAndDeleteVolumes(context.Background(), "us-east-1", []string{"vol-1", "vol-2"}, false, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got[0].SnapshotID != "snap-1" || !got[0].Deleted || got[1].SnapshotID != "snap-2" || !got[1].Deleted {
+		t.Errorf("actions = %+v", got)
+	}
+	if want := []string{"vol-1", "vol-2"}; !reflect.DeepEqual(client.deleted, want) {
+		t.Errorf("deleted = %v, want %v", client.deleted, want)
+	}
+	// Each volume is deleted only after its own snapshot was taken.
+	var order []string
+	for _, in := range client.inputs {
+		switch in := in.(type) {
+		case *ec2.CreateSnapshotInput:
+			order = append(order, "snapshot "+aws.ToString(in.VolumeId))
+		case *ec2.DeleteVolumeInput:
+			order = append(order, "delete "+aws.ToString(in.VolumeId))
+		}
+	}
+	if want := []string{"snapshot vol-1", "delete vol-1", "snapshot vol-2", "delete vol-2"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if tags := ec2Tags(client.snapshots[0].Tags); tags[TagSourceVolume] != "vol-1" || tags[TagSourceZone] != "us-east-1a" || tags[TagRemediation] == "" {
+		t.Errorf("snapshot tags = %v", tags)
+	}
+}
+
+func TestSnapshotAndDeleteVolumesRefusesBeforeChanging(t *testing.T) {
+	attached := availableVolume("vol-attached")
+	attached.State = ec2Types.VolumeStateInUse
+	attached.Attachments = []ec2Types.VolumeAttachment{{InstanceId: aws.String("i-1")}}
+
+	tests := []struct {
+		name    string
+		client  *fakeEC2
+		volumes []string
+		want    error
+	}{
+		{"attached", &fakeEC2{volumes: []ec2Types.Volume{availableVolume("vol-1"), attached}}, []string{"vol-1", "vol-attached"}, scanner.ErrConflict},
+		{"missing", &fakeEC2{volumes: []ec2Types.Volume{availableVolume("vol-1")}}, []string{"vol-1", "vol-gone"}, scanner.ErrNotFound},
+		{"not allowed to delete", &fakeEC2{
+			volumes:   []ec2Types.Volume{availableVolume("vol-1")},
+			deniedOps: map[string]bool{"DeleteVolume": true},
+		}, []string{"vol-1"}, scanner.ErrPermission},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SnapshotAndDeleteVolumes(context.Background(), "us-east-1", tt.volumes, false, WithEC2Client(tt.client))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if len(tt.client.inputs) != 0 {
+				t.Errorf("made changes despite a failed plan: %v", tt.client.inputs)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Tags set on every instance StopInstances stops. StartInstances only starts
+// instances carrying TagStoppedAt and removes all three.
+const (
+	TagStoppedBy     = "finops:stopped-by"
+	TagStoppedAt     = "finops:stopped-at"
+	TagStoppedReason = "finops:stopped-reason"
+)
+
+// tagAutoScalingGroup is set by Auto Scaling on every instance it launches.
+const tagAutoScalingGroup = "aws:autoscaling:groupName"
+
+// StopRequest names the instances to stop and records who stops them and why.
+type StopRequest struct {
+	InstanceIDs []string
+	By          string // who asked, recorded in TagStoppedBy
+	Reason      string // why, recorded in TagStoppedReason, e.g. "avg_cpu below 5% for 7 days"
+	DryRun      bool   // only plan and check permissions
+}
+
+// InstanceAction is the plan for, and then the outcome of, stopping or
+// starting one instance.
+type InstanceAction struct {
+	InstanceID   string `json:"instance_id"`
+	Region       string `json:"region"`
+	InstanceType string `json:"instance_type"`
+	Hibernate    bool   `json:"hibernate,omitempty"` // hibernated rather than stopped
+	Skipped      string `json:"skipped,omitempty"`   // why the instance is left alone
+	Done         bool   `json:"done"`
+	Error        string `json:"error,omitempty"`
+}
+
+// StopInstances stops, or hibernates where the instance was launched with
+// hibernation configured, each running instance in req and tags it with
+// who, when and why. Instances in an Auto Scaling group, with termination
+// protection or not running are skipped. Like SnapshotAndDeleteVolumes it
+// always plans and checks the calls with EC2 DryRun first, and with
+// req.DryRun it stops there.
+func StopInstances(ctx context.Context, region string, req StopRequest, opts ...Option) ([]InstanceAction, error) {
+	client, err := newOptions(opts).ec2Client(ctx, region)
+	if err != nil {
+		return nil, wrapError("LoadDefaultConfig", region, err)
+	}
+	instances, err := describeInstances(ctx, client, region, req.InstanceIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]InstanceAction, 0, len(instances))
+	var ids []string
+	for _, inst := range instances {
+		action := instanceAction(inst, region)
+		action.Hibernate = inst.HibernationOptions != nil && aws.ToBool(inst.HibernationOptions.Configured)
+		switch {
+		case instanceState(inst) != string(ec2Types.InstanceStateNameRunning):
+			action.Skipped = "instance is " + instanceState(inst)
+		case ec2Tags(inst.Tags)[tagAutoScalingGroup] != "":
+			action.Skipped = "member of Auto Scaling group " + ec2Tags(inst.Tags)[tagAutoScalingGroup]
+		default:
+			protected, err := terminationProtected(ctx, client, action.InstanceID)
+			if err != nil {
+				return nil, err
+			}
+			if protected {
+				action.Skipped = "termination protection is enabled"
+			}
+		}
+		if action.Skipped == "" {
+			ids = append(ids, action.InstanceID)
+		}
+		actions = append(actions, action)
+	}
+	if len(ids) == 0 {
+		return actions, nil
+	}
+
+	tags := []ec2Types.Tag{
+		{Key: aws.String(TagStoppedBy), Value: aws.String(req.By)},
+		{Key: aws.String(TagStoppedAt), Value: aws.String(time.Now().UTC().Format(time.RFC3339))},
+		{Key: aws.String(TagStoppedReason), Value: aws.String(req.Reason)},
+	}
+	_, err = client.CreateTags(ctx, &ec2.CreateTagsInput{Resources: ids, Tags: tags, DryRun: aws.Bool(true)})
+	if err := dryRunError("CreateTags", region, err); err != nil {
+		return nil, err
+	}
+	_, err = client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: ids, DryRun: aws.Bool(true)})
+	if err := dryRunError("StopInstances", region, err); err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		return actions, nil
+	}
+
+	var errs []error
+	for i := range actions {
+		action := &actions[i]
+		if action.Skipped != "" {
+			continue
+		}
+		if err := stopInstance(ctx, client, action, tags); err != nil {
+			action.Error = err.Error()
+			errs = append(errs, err)
+			continue
+		}
+		action.Done = true
+	}
+	return actions, errors.Join(errs...)
+}
+
+// stopInstance tags the instance and then stops it, removing the tags again
+// when it cannot be stopped so StartInstances does not pick it up. When
+// that fails too, both errors are returned: the instance still carries the
+// tags while running.
+func stopInstance(ctx context.Context, client EC2API, action *InstanceAction, tags []ec2Types.Tag) error {
+	ids := []string{action.InstanceID}
+	if _, err := client.CreateTags(ctx, &ec2.CreateTagsInput{Resources: ids, Tags: tags}); err != nil {
+		return wrapError("CreateTags", action.InstanceID, err)
+	}
+	_, err := client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: ids, Hibernate: aws.Bool(action.Hibernate)})
+	if err != nil {
+		stopErr := wrapError("StopInstances", action.InstanceID, err)
+		if _, err := client.DeleteTags(ctx, &ec2.DeleteTagsInput{Resources: ids, Tags: stoppedTagKeys()}); err != nil {
+			return errors.Join(stopErr, wrapError("DeleteTags", action.InstanceID, err))
+		}
+		return stopErr
+	}
+	return nil
+}
+
+// StartInstances reverses StopInstances: it starts the stopped instances
+// that carry TagStoppedAt, or all of them in the region when instanceIDs is
+// empty, and removes the tags StopInstances set. Other instances are
+// skipped. With dryRun it only plans and checks the calls with EC2 DryRun.
+func StartInstances(ctx context.Context, region string, instanceIDs []string, dryRun bool, opts ...Option) ([]InstanceAction, error) {
+	client, err := newOptions(opts).ec2Client(ctx, region)
+	if err != nil {
+		return nil, wrapError("LoadDefaultConfig", region, err)
+	}
+	var filters []ec2Types.Filter
+	if len(instanceIDs) == 0 {
+		filters = []ec2Types.Filter{
+			{Name: aws.String("tag-key"), Values: []string{TagStoppedAt}},
+			{Name: aws.String("instance-state-name"), Values: []string{string(ec2Types.InstanceStateNameStopped)}},
+		}
+	}
+	instances, err := describeInstances(ctx, client, region, instanceIDs, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]InstanceAction, 0, len(instances))
+	var ids []string
+	for _, inst := range instances {
+		action := instanceAction(inst, region)
+		switch {
+		case instanceState(inst) != string(ec2Types.InstanceStateNameStopped):
+			action.Skipped = "instance is " + instanceState(inst)
+		case ec2Tags(inst.Tags)[TagStoppedAt] == "":
+			action.Skipped = "instance was not stopped by a remediation"
+		default:
+			ids = append(ids, action.InstanceID)
+		}
+		actions = append(actions, action)
+	}
+	if len(ids) == 0 {
+		return actions, nil
+	}
+
+	_, err = client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: ids, DryRun: aws.Bool(true)})
+	if err := dryRunError("StartInstances", region, err); err != nil {
+		return nil, err
+	}
+	_, err = client.DeleteTags(ctx, &ec2.DeleteTagsInput{Resources: ids, Tags: stoppedTagKeys(), DryRun: aws.Bool(true)})
+	if err := dryRunError("DeleteTags", region, err); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return actions, nil
+	}
+
+	var errs []error
+	for i := range actions {
+		action := &actions[i]
+		if action.Skipped != "" {
+			continue
+		}
+		ids := []string{action.InstanceID}
+		if _, err := client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: ids}); err != nil {
+			err = wrapError("StartInstances", action.InstanceID, err)
+			action.Error = err.Error()
+			errs = append(errs, err)
+			continue
+		}
+		action.Done = true
+		if _, err := client.DeleteTags(ctx, &ec2.DeleteTagsInput{Resources: ids, Tags: stoppedTagKeys()}); err != nil {
+			err = wrapError("DeleteTags", action.InstanceID, err)
+			action.Error = err.Error()
+			errs = append(errs, err)
+		}
+	}
+	return actions, errors.Join(errs...)
+}
+
+// describeInstances returns the instances with the given IDs, or matching
+// filters when ids is empty.
+func describeInstances(ctx context.Context, client EC2API, region string, ids []string, filters []ec2Types.Filter) ([]ec2Types.Instance, error) {
+	if len(ids) == 0 && len(filters) == 0 {
+		return nil, nil
+	}
+	var result []ec2Types.Instance
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{InstanceIds: ids, Filters: filters})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, wrapError("DescribeInstances", region, err)
+		}
+		for _, res := range page.Reservations {
+			result = append(result, res.Instances...)
+		}
+	}
+	return result, nil
+}
+
+// terminationProtected reports whether the instance has termination protection.
+func terminationProtected(ctx context.Context, client EC2API, instanceID string) (bool, error) {
+	resp, err := client.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		Attribute:  ec2Types.InstanceAttributeNameDisableApiTermination,
+	})
+	if err != nil {
+		return false, wrapError("DescribeInstanceAttribute", instanceID, err)
+	}
+	return resp.DisableApiTermination != nil && aws.ToBool(resp.DisableApiTermination.Value), nil
+}
+
+// instanceAction starts the action for inst.
+func instanceAction(inst ec2Types.Instance, region string) InstanceAction {
+	return InstanceAction{
+		InstanceID:   aws.ToString(inst.InstanceId),
+		Region:       region,
+		InstanceType: string(inst.InstanceType),
+	}
+}
+
+// stoppedTagKeys are the tags StopInstances sets, for DeleteTags.
+func stoppedTagKeys() []ec2Types.Tag {
+	return []ec2Types.Tag{{Key: aws.String(TagStoppedBy)}, {Key: aws.String(TagStoppedAt)}, {Key: aws.String(TagStoppedReason)}}
+}
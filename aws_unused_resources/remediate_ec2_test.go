@@ -0,0 +1,153 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func instance(id string, state ec2Types.InstanceStateName, tags ...ec2Types.Tag) ec2Types.Instance {
+	return ec2Types.Instance{
+		InstanceId:   aws.String(id),
+		InstanceType: ec2Types.InstanceTypeT3Micro,
+		State:        &ec2Types.InstanceState{Name: state},
+		Tags:         tags,
+	}
+}
+
+func stopFixture() *fakeEC2 {
+	hibernating := instance("i-hibernate", ec2Types.InstanceStateNameRunning)
+	hibernating.HibernationOptions = &ec2Types.HibernationOptions{Configured: aws.Bool(true)}
+	return &fakeEC2{
+		instances: []ec2Types.Instance{
+			instance("i-idle", ec2Types.InstanceStateNameRunning),
+			hibernating,
+			instance("i-asg", ec2Types.InstanceStateNameRunning, ec2Tag("aws:autoscaling:groupName", "web")),
+			instance("i-protected", ec2Types.InstanceStateNameRunning),
+			instance("i-stopped", ec2Types.InstanceStateNameStopped),
+		},
+		protected: map[string]bool{"i-protected": true},
+	}
+}
+
+var allStopFixture = []string{"i-idle", "i-hibernate", "i-asg", "i-protected", "i-stopped"}
+
+func TestStopInstances(t *testing.T) {
+	client := stopFixture()
+
+	got, err := StopInstances(context.Background(), "us-east-1", StopRequest{
+		InstanceIDs: allStopFixture,
+		By:          "alice",
+		Reason:      "avg_cpu below 5% for 7 days",
+	}, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"i-idle":      "",
+		"i-hibernate": "",
+		"i-asg":       "member of Auto Scaling group web",
+		"i-protected": "termination protection is enabled",
+		"i-stopped":   "instance is stopped",
+	}
+	for _, a := range got {
+		if a.Skipped != want[a.InstanceID] || a.Done != (want[a.InstanceID] == "") {
+			t.Errorf("%s: %+v, want skipped %q", a.InstanceID, a, want[a.InstanceID])
+		}
+	}
+	if !got[1].Hibernate || got[0].Hibernate {
+		t.Errorf("hibernate = %v/%v, want only i-hibernate", got[0].Hibernate, got[1].Hibernate)
+	}
+	for _, id := range []string{"i-idle", "i-hibernate"} {
+		inst := client.instance(id)
+		tags := ec2Tags(inst.Tags)
+		if instanceState(*inst) != "stopped" || tags[TagStoppedBy] != "alice" || tags[TagStoppedAt] == "" || tags[TagStoppedReason] == "" {
+			t.Errorf("%s: state %s, tags %v", id, instanceState(*inst), tags)
+		}
+	}
+	if instanceState(*client.instance("i-asg")) != "running" {
+		t.Error("stopped an Auto Scaling group member")
+	}
+}
+
+func TestStopInstancesDryRun(t *testing.T) {
+	client := stopFixture()
+
+	plan, err := StopInstances(context.Background(), "us-east-1", StopRequest{InstanceIDs: allStopFixture, DryRun: true}, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 5 || len(client.inputs) != 0 {
+		t.Errorf("plan = %+v, changes = %v", plan, client.inputs)
+	}
+
+	client.deniedOps = map[string]bool{"StopInstances": true}
+	if _, err := StopInstances(context.Background(), "us-east-1", StopRequest{InstanceIDs: allStopFixture}, WithEC2Client(client)); !errors.Is(err, scanner.ErrPermission) {
+		t.Errorf("err = %v, want a permission error", err)
+	}
+	if len(client.inputs) != 0 {
+		t.Errorf("changed instances despite a failed dry run: %v", client.inputs)
+	}
+}
+
+func TestStopInstancesUntagsWhenStopFails(t *testing.T) {
+	client := stopFixture()
+	client.failOps = map[string]bool{"StopInstances": true}
+
+	got, err := StopInstances(context.Background(), "us-east-1", StopRequest{InstanceIDs: []string{"i-idle"}, By: "alice"}, WithEC2Client(client))
+
+	if !errors.Is(err, scanner.ErrConflict) || got[0].Done || got[0].Error == "" {
+		t.Errorf("actions = %+v, err = %v", got, err)
+	}
+	if tags := ec2Tags(client.instance("i-idle").Tags); tags[TagStoppedAt] != "" {
+		t.Errorf("tags = %v, want the stop tags removed", tags)
+	}
+}
+
+func TestStopInstancesReportsFailedUntag(t *testing.T) {
+	client := stopFixture()
+	client.failOps = map[string]bool{"StopInstances": true, "DeleteTags": true}
+
+	got, err := StopInstances(context.Background(), "us-east-1", StopRequest{InstanceIDs: []string{"i-idle"}, By: "alice"}, WithEC2Client(client))
+
+	if !errors.Is(err, scanner.ErrConflict) || !strings.Contains(err.Error(), "DeleteTags") {
+		t.Errorf("err = %v, want the failed stop and the failed untag", err)
+	}
+	if got[0].Done || !strings.Contains(got[0].Error, "StopInstances") || !strings.Contains(got[0].Error, "DeleteTags") {
+		t.Errorf("action = %+v", got[0])
+	}
+}
+
+func TestStartInstancesReversesStop(t *testing.T) {
+	client := stopFixture()
+	if _, err := StopInstances(context.Background(), "us-east-1", StopRequest{InstanceIDs: allStopFixture, By: "alice"}, WithEC2Client(client)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := StartInstances(context.Background(), "us-east-1", nil, false, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// i-stopped was already stopped and carries no tags, so it is not picked up.
+	if len(got) != 2 || !got[0].Done || !got[1].Done {
+		t.Errorf("actions = %+v, want i-idle and i-hibernate started", got)
+	}
+	for _, id := range []string{"i-idle", "i-hibernate"} {
+		inst := client.instance(id)
+		if instanceState(*inst) != "running" || len(inst.Tags) != 0 {
+			t.Errorf("%s: state %s, tags %v", id, instanceState(*inst), ec2Tags(inst.Tags))
+		}
+	}
+
+	got, err = StartInstances(context.Background(), "us-east-1", []string{"i-stopped"}, false, WithEC2Client(client))
+	if err != nil || got[0].Skipped != "instance was not stopped by a remediation" {
+		t.Errorf("actions = %+v, err = %v", got, err)
+	}
+}
@@ -0,0 +1,135 @@
+package aws_unused_resources
+
+import (
+	"context"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Resource kinds reported by the AWS detectors.
+const (
+	kindEBS = "ebs"
+	kindEC2 = "ec2"
+	kindRDS = "rds"
+	kindS3  = "s3"
+	kindLB  = "lb"
+	kindVPC = "vpc"
+)
+
+// regionalScan runs a detector in one region.
+type regionalScan func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error)
+
+// regional adapts a single-region detector so that Scope.Region may also be
+// scanner.AllRegions, in which case every enabled region is scanned.
+func regional(scan regionalScan) scanner.ScanFunc {
+	return accountScoped(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+		if scope.Region != scanner.AllRegions {
+			return scan(ctx, scope.Region, params, opts...)
+		}
+		regions, err := EnabledRegions(ctx, opts...)
+		if err != nil {
+			return UnusedResourceMetrics{}, err
+		}
+		return ScanRegions(ctx, regions, DefaultRegionWorkers, func(ctx context.Context, region string) (UnusedResourceMetrics, error) {
+			return scan(ctx, region, params, opts...)
+		})
+	})
+}
+
+// scopedScan runs a detector with the credentials carried by opts.
+type scopedScan func(ctx context.Context, scope scanner.Scope, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error)
+
+// accountScoped resolves Scope.Account: empty uses the default credentials,
+// scanner.AllAccounts scans every active organization member, and an account
+// ID scans that account. Member accounts are reached by assuming Scope.Role,
+// or DefaultOrganizationRole when no role is given. Scope.Tags is applied
+// to every scan.
+func accountScoped(scan scopedScan) scanner.ScanFunc {
+	return func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		tags := WithTagRules(scope.Tags)
+		if scope.Account == "" {
+			return scan(ctx, scope, params, tags)
+		}
+		role := scope.Role
+		if role == "" {
+			role = DefaultOrganizationRole
+		}
+		accounts := []Account{{ID: scope.Account}}
+		if scope.Account == scanner.AllAccounts {
+			var err error
+			if accounts, err = OrganizationAccounts(ctx); err != nil {
+				return scanner.Result{}, err
+			}
+		}
+		return ScanAccounts(ctx, accounts, role, DefaultAccountWorkers, func(ctx context.Context, opts ...Option) (UnusedResourceMetrics, error) {
+			return scan(ctx, scope, params, append(opts, tags)...)
+		})
+	}
+}
+
+// Register every AWS detector with the shared scanner registry.
+func init() {
+	scanner.Register(scanner.Detector{
+		Name:         "aws/ebs",
+		Provider:     scanner.AWS,
+		ResourceKind: kindEBS,
+		Scope:        scanner.Regional,
+		Scan: regional(func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			return Get_unused_ebs_volumes(ctx, region, opts...)
+		}),
+	})
+	scanner.Register(scanner.Detector{
+		Name:         "aws/ec2",
+		Provider:     scanner.AWS,
+		ResourceKind: kindEC2,
+		Scope:        scanner.Regional,
+		Defaults:     scanner.Parameters{Threshold: 5.0, Days: 7},
+		Scan: regional(func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			return GetUnusedEC2Instances(ctx, region, params.Threshold, params.Days, opts...)
+		}),
+	})
+	scanner.Register(scanner.Detector{
+		Name:         "aws/rds",
+		Provider:     scanner.AWS,
+		ResourceKind: kindRDS,
+		Scope:        scanner.Regional,
+		Defaults:     scanner.Parameters{Threshold: 5.0, Days: 7},
+		Scan: regional(func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			return GetUnusedRDSInstances(ctx, region, params.Threshold, params.Days, opts...)
+		}),
+	})
+	// Buckets are listed account-wide, so S3 is scanned once rather than per region.
+	scanner.Register(scanner.Detector{
+		Name:         "aws/s3",
+		Provider:     scanner.AWS,
+		ResourceKind: kindS3,
+		Scope:        scanner.Global,
+		Defaults:     scanner.Parameters{Threshold: 1, Days: 7},
+		Scan: accountScoped(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			region := scope.Region
+			if region == scanner.AllRegions {
+				region = ""
+			}
+			return GetUnusedS3Buckets(ctx, region, params.Threshold, params.Days, opts...)
+		}),
+	})
+	scanner.Register(scanner.Detector{
+		Name:         "aws/lb",
+		Provider:     scanner.AWS,
+		ResourceKind: kindLB,
+		Scope:        scanner.Regional,
+		Defaults:     scanner.Parameters{Threshold: 100, Days: 7},
+		Scan: regional(func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			return GetUnusedLoadBalancers(ctx, region, params.Threshold, params.Days, opts...)
+		}),
+	})
+	scanner.Register(scanner.Detector{
+		Name:         "aws/vpc",
+		Provider:     scanner.AWS,
+		ResourceKind: kindVPC,
+		Scope:        scanner.Regional,
+		Scan: regional(func(ctx context.Context, region string, params scanner.Parameters, opts ...Option) (UnusedResourceMetrics, error) {
+			return GetUnusedVPCs(ctx, region, int(params.Threshold), opts...)
+		}),
+	})
+}
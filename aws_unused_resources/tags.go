@@ -0,0 +1,69 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2Types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// describeTagsBatch is the most load balancers DescribeTags accepts per call.
+const describeTagsBatch = 20
+
+// tagMap converts SDK tags of any shape to a map.
+func tagMap[T any](tags []T, keyValue func(T) (*string, *string)) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		k, v := keyValue(t)
+		m[aws.ToString(k)] = aws.ToString(v)
+	}
+	return m
+}
+
+func ec2Tags(tags []ec2Types.Tag) map[string]string {
+	return tagMap(tags, func(t ec2Types.Tag) (*string, *string) { return t.Key, t.Value })
+}
+
+func rdsTags(tags []rdsTypes.Tag) map[string]string {
+	return tagMap(tags, func(t rdsTypes.Tag) (*string, *string) { return t.Key, t.Value })
+}
+
+// bucketTags returns the tags of a bucket; a bucket without a tag set has none.
+func bucketTags(ctx context.Context, client S3API, bucket string) (map[string]string, error) {
+	resp, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return tagMap(resp.TagSet, func(t s3Types.Tag) (*string, *string) { return t.Key, t.Value }), nil
+}
+
+// loadBalancerTags returns the tags of every load balancer, keyed by ARN.
+func loadBalancerTags(ctx context.Context, client ELBv2API, lbs []elbv2Types.LoadBalancer) (map[string]map[string]string, error) {
+	tags := make(map[string]map[string]string, len(lbs))
+	for start := 0; start < len(lbs); start += describeTagsBatch {
+		end := min(start+describeTagsBatch, len(lbs))
+		arns := make([]string, 0, end-start)
+		for _, lb := range lbs[start:end] {
+			arns = append(arns, aws.ToString(lb.LoadBalancerArn))
+		}
+		resp, err := client.DescribeTags(ctx, &elasticloadbalancingv2.DescribeTagsInput{ResourceArns: arns})
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range resp.TagDescriptions {
+			tags[aws.ToString(desc.ResourceArn)] = tagMap(desc.Tags, func(t elbv2Types.Tag) (*string, *string) { return t.Key, t.Value })
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,118 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbv2Types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+var keep = scanner.TagRules{Exclude: map[string]string{"finops:keep": "true"}}
+
+func ec2Tag(key, value string) ec2Types.Tag {
+	return ec2Types.Tag{Key: aws.String(key), Value: aws.String(value)}
+}
+
+func TestGetUnusedEBSVolumesExcludesTagged(t *testing.T) {
+	client := &fakeEC2{volumes: []ec2Types.Volume{
+		{VolumeId: aws.String("vol-1")},
+		{VolumeId: aws.String("vol-2"), Tags: []ec2Types.Tag{ec2Tag("finops:keep", "true")}},
+		{VolumeId: aws.String("vol-3"), Tags: []ec2Types.Tag{ec2Tag("finops:keep", "false")}},
+	}}
+
+	got, err := Get_unused_ebs_volumes(context.Background(), "us-east-1", WithEC2Client(client), WithTagRules(keep))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"vol-1", "vol-3"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 2 || got.ExcludedInstancesCount != 1 {
+		t.Errorf("total/excluded = %d/%d, want 2/1", got.TotalInstancesCount, got.ExcludedInstancesCount)
+	}
+	if tags := got.Findings[1].Tags; tags["finops:keep"] != "false" {
+		t.Errorf("vol-3 tags = %v, want them on the finding", tags)
+	}
+}
+
+func TestGetUnusedS3BucketsAppliesTagRules(t *testing.T) {
+	client := &fakeS3{
+		buckets: []s3Types.Bucket{
+			{Name: aws.String("dev"), BucketRegion: aws.String("us-east-1")},
+			{Name: aws.String("prod"), BucketRegion: aws.String("us-east-1")},
+			{Name: aws.String("untagged"), BucketRegion: aws.String("us-east-1")},
+		},
+		tags: map[string][]s3Types.Tag{
+			"dev":  {{Key: aws.String("environment"), Value: aws.String("dev")}},
+			"prod": {{Key: aws.String("environment"), Value: aws.String("prod")}},
+		},
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{}}
+	rules := scanner.TagRules{Include: map[string]string{"environment": "dev"}}
+
+	got, err := GetUnusedS3Buckets(context.Background(), "us-east-1", 1, 7,
+		WithS3Client(client), WithCloudWatchClient(cw), WithTagRules(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"dev"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 1 || got.ExcludedInstancesCount != 2 {
+		t.Errorf("total/excluded = %d/%d, want 1/2", got.TotalInstancesCount, got.ExcludedInstancesCount)
+	}
+	if sub := got.Regions["us-east-1"]; sub.TotalInstancesCount != 1 {
+		t.Errorf("Regions = %v, want excluded buckets left out", got.Regions)
+	}
+}
+
+func TestGetUnusedLoadBalancersBatchesDescribeTags(t *testing.T) {
+	client := &fakeELBv2{tags: map[string][]elbv2Types.Tag{}}
+	for i := 0; i < 25; i++ {
+		arn := fmt.Sprintf("%sapp/lb-%d/%d", lbArnPrefix, i, i)
+		client.loadBalancers = append(client.loadBalancers, elbv2Types.LoadBalancer{
+			LoadBalancerArn: aws.String(arn),
+			Type:            elbv2Types.LoadBalancerTypeEnumApplication,
+		})
+		if i%5 == 0 {
+			client.tags[arn] = []elbv2Types.Tag{{Key: aws.String("finops:keep"), Value: aws.String("true")}}
+		}
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{}}
+
+	got, err := GetUnusedLoadBalancers(context.Background(), "us-east-1", 100, 7,
+		WithELBv2Client(client), WithCloudWatchClient(cw), WithTagRules(keep))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.tagCalls != 2 {
+		t.Errorf("DescribeTags called %d times, want 2", client.tagCalls)
+	}
+	if got.TotalInstancesCount != 20 || got.UnusedInstancesCount != 20 || got.ExcludedInstancesCount != 5 {
+		t.Errorf("total/unused/excluded = %d/%d/%d, want 20/20/5",
+			got.TotalInstancesCount, got.UnusedInstancesCount, got.ExcludedInstancesCount)
+	}
+}
+
+func TestNoTagRulesSkipsTagCalls(t *testing.T) {
+	client := &fakeELBv2{loadBalancers: []elbv2Types.LoadBalancer{{LoadBalancerArn: aws.String(lbArnPrefix + "app/a/1")}}}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{}}
+
+	if _, err := GetUnusedLoadBalancers(context.Background(), "us-east-1", 100, 7,
+		WithELBv2Client(client), WithCloudWatchClient(cw)); err != nil {
+		t.Fatal(err)
+	}
+	if client.tagCalls != 0 {
+		t.Errorf("DescribeTags called %d times, want 0 without rules", client.tagCalls)
+	}
+}
@@ -2,31 +2,55 @@ package aws_unused_resources
 
 import (
 	"context"
-	"log"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
-type UnusedResourceMetrics struct {
-	ResourceIDs          []string
-	TotalInstancesCount  int
-	UnusedInstancesCount int
+// UnusedResourceMetrics is the result every detector in this package returns.
+type UnusedResourceMetrics = scanner.Result
+
+// UnusedVolume holds detailed info about an unattached EBS volume.
+type UnusedVolume struct {
+	VolumeID         string            // EBS volume ID
+	VolumeType       string            // gp2, gp3, io1, ...
+	SizeGiB          int32             // provisioned size in GiB
+	Iops             int32             // provisioned IOPS, 0 when not applicable
+	AvailabilityZone string            // AZ the volume lives in
+	State            string            // current state (e.g., available)
+	CreateTime       time.Time         // when the volume was created
+	Tags             map[string]string // tags of the volume
 }
 
-func Get_unused_ebs_volumes(region string) UnusedResourceMetrics {
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(region),
-		// replace the "limited-admin" with the profile of your choice or completely remove that when running in a Lambda Environment
-		// config.WithSharedConfigProfile(profile),
-	)
-	if err != nil {
-		log.Fatalf("Unable to load AWS SDK config: %v", err)
+// finding converts the volume into the shared finding shape.
+func (u UnusedVolume) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.VolumeID,
+		ResourceKind: kindEBS,
+		Tags:         u.Tags,
+		CreatedAt:    u.CreateTime,
+		Attributes: map[string]string{
+			"volume_type":       u.VolumeType,
+			"size_gib":          strconv.Itoa(int(u.SizeGiB)),
+			"iops":              strconv.Itoa(int(u.Iops)),
+			"availability_zone": u.AvailabilityZone,
+			"state":             u.State,
+		},
 	}
+}
 
-	// Create an EC2 service client.
-	svc := ec2.NewFromConfig(cfg)
+// Get_unused_ebs_volumes lists the EBS volumes in a region that are not attached to any instance.
+func Get_unused_ebs_volumes(ctx context.Context, region string, opts ...Option) (UnusedResourceMetrics, error) {
+	// Create an EC2 service client unless one was injected.
+	// Pass WithConfig to use a shared config profile, or leave it out when running in a Lambda Environment
+	o := newOptions(opts)
+	svc, err := o.ec2Client(ctx, region)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
+	}
 
 	// Create a paginator for the DescribeVolumes API call.
 	paginator := ec2.NewDescribeVolumesPaginator(svc, &ec2.DescribeVolumesInput{})
@@ -36,14 +60,22 @@ func Get_unused_ebs_volumes(region string) UnusedResourceMetrics {
 	unusedEBScount := 0
 
 	// Iterate through the pages of results.
+	progress := scanner.ProgressOf(ctx)
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			log.Fatalf("Failed to retrieve page: %v", err)
+			return UnusedResourceMetrics{}, wrapError("DescribeVolumes", region, err)
 		}
 
+		progress.Found(len(page.Volumes))
+		progress.Processed(len(page.Volumes))
 		for _, volume := range page.Volumes {
+			if o.tags.Excludes(ec2Tags(volume.Tags)) {
+				unused_ebs_volumes.ExcludedInstancesCount++
+				continue
+			}
 			totalEBScount += 1
+			unused_ebs_volumes.TotalSizeGiB += float64(aws.ToInt32(volume.Size))
 			// volumeJSON, err := json.Marshal(volume)
 			// if err != nil {
 			// 	log.Fatalf("Failed to marshal volume: %v", err)
@@ -51,8 +83,20 @@ func Get_unused_ebs_volumes(region string) UnusedResourceMetrics {
 
 			if len(volume.Attachments) == 0 {
 				unusedEBScount += 1
+				unused_ebs_volumes.UnusedSizeGiB += float64(aws.ToInt32(volume.Size))
 				volumeID := aws.ToString(volume.VolumeId)
 				unused_ebs_volumes.ResourceIDs = append(unused_ebs_volumes.ResourceIDs, volumeID)
+				found := UnusedVolume{
+					VolumeID:         volumeID,
+					VolumeType:       string(volume.VolumeType),
+					SizeGiB:          aws.ToInt32(volume.Size),
+					Iops:             aws.ToInt32(volume.Iops),
+					AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+					State:            string(volume.State),
+					CreateTime:       aws.ToTime(volume.CreateTime),
+					Tags:             ec2Tags(volume.Tags),
+				}
+				unused_ebs_volumes.Findings = append(unused_ebs_volumes.Findings, found.finding())
 			}
 		}
 	}
@@ -60,5 +104,6 @@ func Get_unused_ebs_volumes(region string) UnusedResourceMetrics {
 	unused_ebs_volumes.TotalInstancesCount = totalEBScount
 	unused_ebs_volumes.UnusedInstancesCount = unusedEBScount
 
-	return unused_ebs_volumes
+	unused_ebs_volumes.InRegion(region)
+	return unused_ebs_volumes, nil
 }
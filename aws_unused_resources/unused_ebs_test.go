@@ -0,0 +1,75 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestGetUnusedEBSVolumesPaginates(t *testing.T) {
+	client := &fakeEC2{
+		pageSize: 2,
+		volumes: []ec2Types.Volume{
+			{VolumeId: aws.String("vol-1"), VolumeType: ec2Types.VolumeTypeGp3, Size: aws.Int32(100), AvailabilityZone: aws.String("us-east-1a")},
+			{VolumeId: aws.String("vol-2"), Size: aws.Int32(50), Attachments: []ec2Types.VolumeAttachment{{InstanceId: aws.String("i-1")}}},
+			{VolumeId: aws.String("vol-3")},
+			{VolumeId: aws.String("vol-4"), Attachments: []ec2Types.VolumeAttachment{{InstanceId: aws.String("i-2")}}},
+			{VolumeId: aws.String("vol-5")},
+		},
+	}
+
+	var progress scanner.Progress
+	got, err := Get_unused_ebs_volumes(scanner.WithProgress(context.Background(), &progress), "us-east-1", WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed, total := progress.Counts(); processed != 5 || total != 5 {
+		t.Errorf("progress = %d/%d, want 5/5", processed, total)
+	}
+	if want := []string{"vol-1", "vol-3", "vol-5"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 5 || got.UnusedInstancesCount != 3 {
+		t.Errorf("counts = %d/%d, want 3/5", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	if got.TotalSizeGiB != 150 || got.UnusedSizeGiB != 100 {
+		t.Errorf("sizes = %v/%v GiB, want 100/150", got.UnusedSizeGiB, got.TotalSizeGiB)
+	}
+	if f := got.Findings[0]; f.Attributes["volume_type"] != "gp3" || f.Attributes["size_gib"] != "100" || f.Attributes["availability_zone"] != "us-east-1a" {
+		t.Errorf("finding = %+v", f)
+	}
+	if client.calls["DescribeVolumes"] != 3 {
+		t.Errorf("DescribeVolumes called %d times, want 3", client.calls["DescribeVolumes"])
+	}
+}
+
+func TestGetUnusedEBSVolumesEmpty(t *testing.T) {
+	got, err := Get_unused_ebs_volumes(context.Background(), "us-east-1", WithEC2Client(&fakeEC2{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TotalInstancesCount != 0 || got.UnusedInstancesCount != 0 || len(got.ResourceIDs) != 0 {
+		t.Errorf("got %+v, want an empty result", got)
+	}
+}
+
+func TestGetUnusedEBSVolumesReturnsTypedError(t *testing.T) {
+	client := &fakeEC2{err: &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not allowed"}}
+
+	_, err := Get_unused_ebs_volumes(context.Background(), "us-east-1", WithEC2Client(client))
+
+	if !errors.Is(err, scanner.ErrPermission) {
+		t.Fatalf("err = %v, want a permission error", err)
+	}
+	var scanErr *scanner.Error
+	if !errors.As(err, &scanErr) || scanErr.Op != "DescribeVolumes" {
+		t.Errorf("err = %#v, want Op DescribeVolumes", err)
+	}
+}
@@ -5,43 +5,61 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
 // UnusedInstance holds detailed info about a low-usage EC2 instance.
 type UnusedInstance struct {
-	InstanceID   string    // EC2 Instance ID
-	InstanceType string    // Instance type (e.g., t3.micro)
-	LaunchTime   time.Time // when the instance was started
-	State        string    // current state (e.g., running)
-	AvgCPU       float64   // average CPU utilization (%) over the period
+	InstanceID   string            // EC2 Instance ID
+	InstanceType string            // Instance type (e.g., t3.micro)
+	LaunchTime   time.Time         // when the instance was started
+	State        string            // current state (e.g., running)
+	AvgCPU       float64           // average CPU utilization (%) over the period
+	Tags         map[string]string // tags of the instance
+}
+
+// finding converts the instance into the shared finding shape.
+func (u UnusedInstance) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.InstanceID,
+		ResourceKind: kindEC2,
+		Tags:         u.Tags,
+		CreatedAt:    u.LaunchTime,
+		Attributes:   map[string]string{"instance_type": u.InstanceType, "state": u.State},
+		Metrics:      map[string]float64{"avg_cpu": u.AvgCPU},
+	}
 }
 
 // GetUnusedEC2Instances retrieves all running EC2 instances in a region,
 // computes their average CPU use over the past 'days', and returns both
-// details and summary metrics for those below 'threshold'.
+// details and summary metrics for those below 'threshold'. Instances whose
+// metrics cannot be read are reported in Skipped instead of the totals.
 func GetUnusedEC2Instances(
 	ctx context.Context,
 	region string,
 	threshold float64,
 	days int,
-) UnusedResourceMetrics {
-	// Load AWS config for specified region
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	opts ...Option,
+) (UnusedResourceMetrics, error) {
+	// Resolve clients for the specified region
+	o := newOptions(opts)
+	ec2Client, err := o.ec2Client(ctx, region)
 	if err != nil {
-		return UnusedResourceMetrics{}
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
+	}
+	cwClient, err := o.cloudwatchClient(ctx, region)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
 	}
-	ec2Client := ec2.NewFromConfig(cfg)
-	cwClient := cloudwatch.NewFromConfig(cfg)
 
 	// Fetch all running instances
 	instances, err := listRunningInstances(ctx, ec2Client)
 	if err != nil {
-		return UnusedResourceMetrics{}
+		return UnusedResourceMetrics{}, wrapError("DescribeInstances", region, err)
 	}
 
 	// Initialize metrics
@@ -51,35 +69,51 @@ func GetUnusedEC2Instances(
 		UnusedInstancesCount: 0,
 	}
 
-	var unused []UnusedInstance
+	progress := scanner.ProgressOf(ctx)
+	progress.Found(len(instances))
 	for _, inst := range instances {
-		avgCPU, err := getAvgCPUEC2(ctx, cwClient, *inst.InstanceId, days)
+		progress.Processed(1)
+		instanceID := aws.ToString(inst.InstanceId)
+		if o.tags.Excludes(ec2Tags(inst.Tags)) {
+			metrics.ExcludedInstancesCount++
+			metrics.TotalInstancesCount--
+			continue
+		}
+		avgCPU, err := getAvgCPUEC2(ctx, cwClient, instanceID, days)
 		if err != nil {
-			// skip on error
+			if ctx.Err() != nil {
+				return UnusedResourceMetrics{}, ctx.Err()
+			}
+			// skip on error and keep the instance out of the KPI
+			metrics.Skipped = append(metrics.Skipped, scanner.Skip(instanceID, wrapError("GetMetricStatistics", instanceID, err)))
+			metrics.TotalInstancesCount--
 			continue
 		}
 		if avgCPU < threshold {
 			// record detailed instance info
-			unused = append(unused, UnusedInstance{
-				InstanceID:   *inst.InstanceId,
+			found := UnusedInstance{
+				InstanceID:   instanceID,
 				InstanceType: string(inst.InstanceType),
-				LaunchTime:   *inst.LaunchTime,
-				State:        string(inst.State.Name),
+				LaunchTime:   aws.ToTime(inst.LaunchTime),
+				State:        instanceState(inst),
 				AvgCPU:       avgCPU,
-			})
+				Tags:         ec2Tags(inst.Tags),
+			}
+			metrics.Findings = append(metrics.Findings, found.finding())
 			// update summary metrics
-			metrics.ResourceIDs = append(metrics.ResourceIDs, *inst.InstanceId)
+			metrics.ResourceIDs = append(metrics.ResourceIDs, instanceID)
 			metrics.UnusedInstancesCount++
 		}
 	}
 
-	return metrics
+	metrics.InRegion(region)
+	return metrics, nil
 }
 
 // listRunningInstances returns all EC2 instances currently in the "running" state.
 func listRunningInstances(
 	ctx context.Context,
-	client *ec2.Client,
+	client EC2API,
 ) ([]ec2Types.Instance, error) {
 	input := &ec2.DescribeInstancesInput{
 		Filters: []ec2Types.Filter{{
@@ -101,10 +135,18 @@ func listRunningInstances(
 	return result, nil
 }
 
+// instanceState returns the state name of an instance, or "" when it is missing.
+func instanceState(inst ec2Types.Instance) string {
+	if inst.State == nil {
+		return ""
+	}
+	return string(inst.State.Name)
+}
+
 // getAvgCPU retrieves the average CPU utilization metric for an EC2 instance over 'days'.
 func getAvgCPUEC2(
 	ctx context.Context,
-	client *cloudwatch.Client,
+	client CloudWatchAPI,
 	instanceID string,
 	days int,
 ) (float64, error) {
@@ -128,12 +170,5 @@ func getAvgCPUEC2(
 	if err != nil {
 		return 0, err
 	}
-	if len(resp.Datapoints) == 0 {
-		return 0, nil
-	}
-	var sum float64
-	for _, dp := range resp.Datapoints {
-		sum += *dp.Average
-	}
-	return sum / float64(len(resp.Datapoints)), nil
+	return averageOf(resp.Datapoints), nil
 }
@@ -0,0 +1,113 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func runningInstance(id string) ec2Types.Instance {
+	return ec2Types.Instance{
+		InstanceId:   aws.String(id),
+		InstanceType: ec2Types.InstanceTypeT3Micro,
+		LaunchTime:   aws.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		State:        &ec2Types.InstanceState{Name: ec2Types.InstanceStateNameRunning},
+	}
+}
+
+func TestGetUnusedEC2Instances(t *testing.T) {
+	stopped := runningInstance("i-stopped")
+	stopped.State = &ec2Types.InstanceState{Name: ec2Types.InstanceStateNameStopped}
+	ec2Client := &fakeEC2{
+		pageSize:  1,
+		instances: []ec2Types.Instance{runningInstance("i-idle"), runningInstance("i-busy"), runningInstance("i-quiet"), stopped},
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{
+		"i-idle": averages(1, 2, 3),
+		"i-busy": averages(40, 60),
+		// i-quiet has no datapoints and counts as 0% CPU
+	}}
+
+	got, err := GetUnusedEC2Instances(context.Background(), "us-east-1", 5, 7,
+		WithEC2Client(ec2Client), WithCloudWatchClient(cw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"i-idle", "i-quiet"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 3 || got.UnusedInstancesCount != 2 {
+		t.Errorf("counts = %d/%d, want 2/3", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	if len(got.Findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(got.Findings))
+	}
+	idle := got.Findings[0]
+	if idle.ResourceKind != "ec2" || idle.Attributes["instance_type"] != "t3.micro" || idle.Metrics["avg_cpu"] != 2 {
+		t.Errorf("finding = %+v", idle)
+	}
+	if !idle.CreatedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v, want the launch time", idle.CreatedAt)
+	}
+	in := cw.inputs[0]
+	if aws.ToString(in.Namespace) != "AWS/EC2" || aws.ToString(in.MetricName) != "CPUUtilization" {
+		t.Errorf("queried %s/%s, want AWS/EC2/CPUUtilization", aws.ToString(in.Namespace), aws.ToString(in.MetricName))
+	}
+	if window := in.EndTime.Sub(*in.StartTime); window < 7*24*time.Hour-time.Hour || window > 7*24*time.Hour+time.Hour {
+		t.Errorf("metric window = %v, want 7 days", window)
+	}
+}
+
+func TestGetUnusedEC2InstancesSkipsMetricErrors(t *testing.T) {
+	ec2Client := &fakeEC2{instances: []ec2Types.Instance{runningInstance("i-1"), runningInstance("i-2")}}
+	cw := &fakeCloudWatch{
+		datapoints: map[string][]cwTypes.Datapoint{"i-2": averages(0.5)},
+		errs:       map[string]error{"i-1": &smithy.GenericAPIError{Code: "Throttling"}},
+	}
+
+	got, err := GetUnusedEC2Instances(context.Background(), "us-east-1", 5, 7,
+		WithEC2Client(ec2Client), WithCloudWatchClient(cw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"i-2"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 1 {
+		t.Errorf("TotalInstancesCount = %d, want 1 (skipped instance excluded)", got.TotalInstancesCount)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].ResourceID != "i-1" || got.Skipped[0].Kind != scanner.KindThrottling {
+		t.Errorf("Skipped = %+v", got.Skipped)
+	}
+}
+
+func TestGetUnusedEC2InstancesStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cw := &fakeCloudWatch{errs: map[string]error{"i-1": context.Canceled}}
+
+	_, err := GetUnusedEC2Instances(ctx, "us-east-1", 5, 7,
+		WithEC2Client(&fakeEC2{instances: []ec2Types.Instance{runningInstance("i-1")}}), WithCloudWatchClient(cw))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestGetUnusedEC2InstancesListError(t *testing.T) {
+	_, err := GetUnusedEC2Instances(context.Background(), "us-east-1", 5, 7,
+		WithEC2Client(&fakeEC2{err: errors.New("boom")}), WithCloudWatchClient(&fakeCloudWatch{}))
+	if scanner.KindOf(err) != scanner.KindUnknown || err == nil {
+		t.Errorf("err = %v, want an unclassified error", err)
+	}
+}
@@ -6,20 +6,35 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2Types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
 // UnusedLoadBalancer holds detailed info about an underutilized load balancer.
 type UnusedLoadBalancer struct {
-	LoadBalancerArn  string  // ARN of the load balancer
-	LoadBalancerName string  // name (e.g., app/my-lb/abc123)
-	Type             string  // APPLICATION | NETWORK
-	Scheme           string  // internet-facing or internal
-	AvgRequestCount  float64 // average daily RequestCount over the period
+	LoadBalancerArn  string            // ARN of the load balancer
+	LoadBalancerName string            // name (e.g., app/my-lb/abc123)
+	Type             string            // APPLICATION | NETWORK
+	Scheme           string            // internet-facing or internal
+	CreatedTime      time.Time         // when the load balancer was created
+	AvgRequestCount  float64           // average daily RequestCount over the period
+	Tags             map[string]string // tags of the load balancer, only read when tag rules are set
+}
+
+// finding converts the load balancer into the shared finding shape.
+func (u UnusedLoadBalancer) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.LoadBalancerArn,
+		ResourceKind: kindLB,
+		Tags:         u.Tags,
+		Name:         u.LoadBalancerName,
+		CreatedAt:    u.CreatedTime,
+		Attributes:   map[string]string{"type": u.Type, "scheme": u.Scheme},
+		Metrics:      map[string]float64{"avg_request_count": u.AvgRequestCount},
+	}
 }
 
 // GetUnusedLoadBalancers lists all ALBs/NLBs, evaluates their average daily RequestCount
@@ -29,17 +44,21 @@ func GetUnusedLoadBalancers(
 	region string,
 	threshold float64, // Number of requests per day
 	days int, // Number of days to look back
+	opts ...Option,
 ) (UnusedResourceMetrics, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	o := newOptions(opts)
+	elbv2Client, err := o.elbv2Client(ctx, region)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
+	}
+	cwClient, err := o.cloudwatchClient(ctx, region)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
 	}
-	elbv2Client := elasticloadbalancingv2.NewFromConfig(cfg)
-	cwClient := cloudwatch.NewFromConfig(cfg)
 
 	lbs, err := listAllLoadBalancers(ctx, elbv2Client)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("DescribeLoadBalancers", region, err)
 	}
 
 	metrics := UnusedResourceMetrics{
@@ -47,10 +66,24 @@ func GetUnusedLoadBalancers(
 		TotalInstancesCount:  len(lbs),
 		UnusedInstancesCount: 0,
 	}
-	var unused []UnusedLoadBalancer
 
+	var tags map[string]map[string]string
+	if !o.tags.Empty() {
+		if tags, err = loadBalancerTags(ctx, elbv2Client, lbs); err != nil {
+			return UnusedResourceMetrics{}, wrapError("DescribeTags", region, err)
+		}
+	}
+
+	progress := scanner.ProgressOf(ctx)
+	progress.Found(len(lbs))
 	for _, lb := range lbs {
+		progress.Processed(1)
 		arn := aws.ToString(lb.LoadBalancerArn)
+		if o.tags.Excludes(tags[arn]) {
+			metrics.ExcludedInstancesCount++
+			metrics.TotalInstancesCount--
+			continue
+		}
 		// extract the identifier for CloudWatch dimension
 		parts := strings.SplitN(arn, ":loadbalancer/", 2)
 		dimVal := ""
@@ -64,28 +97,37 @@ func GetUnusedLoadBalancers(
 		}
 		avgReq, err := getAvgRequestCount(ctx, cwClient, dimVal, namespace, days)
 		if err != nil {
+			if ctx.Err() != nil {
+				return UnusedResourceMetrics{}, ctx.Err()
+			}
+			metrics.Skipped = append(metrics.Skipped, scanner.Skip(arn, wrapError("GetMetricStatistics", arn, err)))
+			metrics.TotalInstancesCount--
 			continue
 		}
 		if avgReq < threshold {
-			unused = append(unused, UnusedLoadBalancer{
+			found := UnusedLoadBalancer{
 				LoadBalancerArn:  arn,
 				LoadBalancerName: aws.ToString(lb.LoadBalancerName),
 				Type:             string(lb.Type),
 				Scheme:           string(lb.Scheme),
+				CreatedTime:      aws.ToTime(lb.CreatedTime),
 				AvgRequestCount:  avgReq,
-			})
+				Tags:             tags[arn],
+			}
+			metrics.Findings = append(metrics.Findings, found.finding())
 			metrics.ResourceIDs = append(metrics.ResourceIDs, arn)
 			metrics.UnusedInstancesCount++
 		}
 	}
 
+	metrics.InRegion(region)
 	return metrics, nil
 }
 
 // listAllLoadBalancers retrieves all ALBs and NLBs in the account for the given region.
 func listAllLoadBalancers(
 	ctx context.Context,
-	client *elasticloadbalancingv2.Client,
+	client ELBv2API,
 ) ([]elbv2Types.LoadBalancer, error) {
 	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(client, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
 	var result []elbv2Types.LoadBalancer
@@ -102,7 +144,7 @@ func listAllLoadBalancers(
 // getAvgRequestCount fetches the average daily RequestCount for a load balancer over 'days'.
 func getAvgRequestCount(
 	ctx context.Context,
-	client *cloudwatch.Client,
+	client CloudWatchAPI,
 	lbName string,
 	namespace string,
 	days int,
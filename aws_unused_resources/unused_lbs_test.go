@@ -0,0 +1,50 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	elbv2Types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+const lbArnPrefix = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/"
+
+func TestGetUnusedLoadBalancers(t *testing.T) {
+	client := &fakeELBv2{
+		pageSize: 1,
+		loadBalancers: []elbv2Types.LoadBalancer{
+			{LoadBalancerArn: aws.String(lbArnPrefix + "app/quiet/1"), Type: elbv2Types.LoadBalancerTypeEnumApplication},
+			{LoadBalancerArn: aws.String(lbArnPrefix + "app/busy/2"), Type: elbv2Types.LoadBalancerTypeEnumApplication},
+			{LoadBalancerArn: aws.String(lbArnPrefix + "net/tcp/3"), Type: elbv2Types.LoadBalancerTypeEnumNetwork},
+		},
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{
+		"app/quiet/1": sums(10, 20),
+		"app/busy/2":  sums(5000),
+		"net/tcp/3":   {{Sum: nil}},
+	}}
+
+	got, err := GetUnusedLoadBalancers(context.Background(), "us-east-1", 100, 7,
+		WithELBv2Client(client), WithCloudWatchClient(cw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{lbArnPrefix + "app/quiet/1", lbArnPrefix + "net/tcp/3"}
+	if !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if f := got.Findings[0]; f.Attributes["type"] != "application" || f.Metrics["avg_request_count"] != 15 {
+		t.Errorf("finding = %+v", f)
+	}
+	namespaces := map[string]string{}
+	for _, in := range cw.inputs {
+		namespaces[aws.ToString(in.Dimensions[0].Value)] = aws.ToString(in.Namespace)
+	}
+	if namespaces["net/tcp/3"] != "AWS/NetworkELB" || namespaces["app/quiet/1"] != "AWS/ApplicationELB" {
+		t.Errorf("namespaces = %v", namespaces)
+	}
+}
@@ -2,24 +2,46 @@ package aws_unused_resources
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
 // UnusedRDS holds detailed info about a low-usage RDS instance.
 type UnusedRDS struct {
-	DBInstanceIdentifier string    // RDS instance identifier
-	DBInstanceClass      string    // Instance class (e.g., db.t3.micro)
-	Engine               string    // Database engine (e.g., mysql)
-	InstanceCreateTime   time.Time // Creation timestamp
-	DBInstanceStatus     string    // Current status (e.g., available)
-	AvgCPU               float64   // Average CPU utilization (%) over the period
+	DBInstanceIdentifier string            // RDS instance identifier
+	DBInstanceClass      string            // Instance class (e.g., db.t3.micro)
+	Engine               string            // Database engine (e.g., mysql)
+	InstanceCreateTime   time.Time         // Creation timestamp
+	DBInstanceStatus     string            // Current status (e.g., available)
+	StorageType          string            // gp2, gp3, io1, standard, ...
+	AllocatedStorage     int32             // Allocated storage in GiB
+	AvgCPU               float64           // Average CPU utilization (%) over the period
+	Tags                 map[string]string // tags of the instance
+}
+
+// finding converts the DB instance into the shared finding shape.
+func (u UnusedRDS) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.DBInstanceIdentifier,
+		ResourceKind: kindRDS,
+		Tags:         u.Tags,
+		CreatedAt:    u.InstanceCreateTime,
+		Attributes: map[string]string{
+			"instance_class": u.DBInstanceClass,
+			"engine":         u.Engine,
+			"status":         u.DBInstanceStatus,
+			"storage_type":   u.StorageType,
+			"storage_gib":    strconv.Itoa(int(u.AllocatedStorage)),
+		},
+		Metrics: map[string]float64{"avg_cpu": u.AvgCPU},
+	}
 }
 
 // GetUnusedRDSInstances retrieves all RDS instances in a region, computes their average CPU
@@ -29,19 +51,23 @@ func GetUnusedRDSInstances(
 	region string, // AWS region
 	threshold float64, // Average CPU utilization (%)
 	days int, // Number of days to consider for CPU usage
+	opts ...Option,
 ) (UnusedResourceMetrics, error) {
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	// Resolve clients for the specified region
+	o := newOptions(opts)
+	rdsClient, err := o.rdsClient(ctx, region)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
+	}
+	cwClient, err := o.cloudwatchClient(ctx, region)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
 	}
-	rdsClient := rds.NewFromConfig(cfg)
-	cwClient := cloudwatch.NewFromConfig(cfg)
 
 	// List all RDS instances
 	instances, err := listAllDBInstances(ctx, rdsClient)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("DescribeDBInstances", region, err)
 	}
 
 	metrics := UnusedResourceMetrics{
@@ -49,13 +75,27 @@ func GetUnusedRDSInstances(
 		TotalInstancesCount:  len(instances),
 		UnusedInstancesCount: 0,
 	}
-	var unused []UnusedRDS
 
+	progress := scanner.ProgressOf(ctx)
+	progress.Found(len(instances))
 	for _, db := range instances {
-		avgCPU, err := getAvgCPURDS(ctx, cwClient, *db.DBInstanceIdentifier, days)
+		progress.Processed(1)
+		dbID := aws.ToString(db.DBInstanceIdentifier)
+		if o.tags.Excludes(rdsTags(db.TagList)) {
+			metrics.ExcludedInstancesCount++
+			metrics.TotalInstancesCount--
+			continue
+		}
+		avgCPU, err := getAvgCPURDS(ctx, cwClient, dbID, days)
 		if err != nil {
+			if ctx.Err() != nil {
+				return UnusedResourceMetrics{}, ctx.Err()
+			}
+			metrics.Skipped = append(metrics.Skipped, scanner.Skip(dbID, wrapError("GetMetricStatistics", dbID, err)))
+			metrics.TotalInstancesCount--
 			continue
 		}
+		metrics.TotalSizeGiB += float64(aws.ToInt32(db.AllocatedStorage))
 		if avgCPU < threshold {
 			// Safe dereference of optional fields
 			id := ""
@@ -79,26 +119,32 @@ func GetUnusedRDSInstances(
 				createTime = *db.InstanceCreateTime
 			}
 
-			unused = append(unused, UnusedRDS{
+			found := UnusedRDS{
 				DBInstanceIdentifier: id,
 				DBInstanceClass:      class,
 				Engine:               engine,
 				InstanceCreateTime:   createTime,
 				DBInstanceStatus:     status,
+				StorageType:          aws.ToString(db.StorageType),
+				AllocatedStorage:     aws.ToInt32(db.AllocatedStorage),
 				AvgCPU:               avgCPU,
-			})
+				Tags:                 rdsTags(db.TagList),
+			}
+			metrics.Findings = append(metrics.Findings, found.finding())
 			metrics.ResourceIDs = append(metrics.ResourceIDs, id)
 			metrics.UnusedInstancesCount++
+			metrics.UnusedSizeGiB += float64(found.AllocatedStorage)
 		}
 	}
 
+	metrics.InRegion(region)
 	return metrics, nil
 }
 
 // listAllDBInstances retrieves all RDS DB instances in the account for the given region.
 func listAllDBInstances(
 	ctx context.Context,
-	client *rds.Client,
+	client RDSAPI,
 ) ([]rdsTypes.DBInstance, error) {
 	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
 	var result []rdsTypes.DBInstance
@@ -115,7 +161,7 @@ func listAllDBInstances(
 // getAvgCPURDS retrieves the average CPU utilization for an RDS instance over 'days'.
 func getAvgCPURDS(
 	ctx context.Context,
-	client *cloudwatch.Client,
+	client CloudWatchAPI,
 	dbIdentifier string,
 	days int,
 ) (float64, error) {
@@ -139,12 +185,5 @@ func getAvgCPURDS(
 	if err != nil {
 		return 0, err
 	}
-	if len(resp.Datapoints) == 0 {
-		return 0, nil
-	}
-	var sum float64
-	for _, dp := range resp.Datapoints {
-		sum += *dp.Average
-	}
-	return sum / float64(len(resp.Datapoints)), nil
+	return averageOf(resp.Datapoints), nil
 }
@@ -0,0 +1,58 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestGetUnusedRDSInstances(t *testing.T) {
+	client := &fakeRDS{
+		pageSize: 1,
+		instances: []rdsTypes.DBInstance{
+			{DBInstanceIdentifier: aws.String("db-idle"), DBInstanceClass: aws.String("db.t3.micro"), Engine: aws.String("mysql")},
+			{DBInstanceIdentifier: aws.String("db-busy")},
+			{DBInstanceIdentifier: aws.String("db-sparse")},
+		},
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{
+		"db-idle":   averages(1),
+		"db-busy":   averages(80),
+		"db-sparse": {{Average: nil}, {Average: aws.Float64(2)}},
+	}}
+
+	got, err := GetUnusedRDSInstances(context.Background(), "us-east-1", 5, 7,
+		WithRDSClient(client), WithCloudWatchClient(cw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"db-idle", "db-sparse"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 3 {
+		t.Errorf("TotalInstancesCount = %d, want 3", got.TotalInstancesCount)
+	}
+	if f := got.Findings[0]; f.Attributes["instance_class"] != "db.t3.micro" || f.Attributes["engine"] != "mysql" || f.Metrics["avg_cpu"] != 1 {
+		t.Errorf("finding = %+v", f)
+	}
+	if f := got.Findings[1]; f.Metrics["avg_cpu"] != 2 {
+		t.Errorf("avg_cpu = %v, want 2 (missing datapoints ignored)", f.Metrics["avg_cpu"])
+	}
+	if ns := aws.ToString(cw.inputs[0].Namespace); ns != "AWS/RDS" {
+		t.Errorf("Namespace = %s, want AWS/RDS", ns)
+	}
+}
+
+func TestGetUnusedRDSInstancesListError(t *testing.T) {
+	_, err := GetUnusedRDSInstances(context.Background(), "us-east-1", 5, 7,
+		WithRDSClient(&fakeRDS{err: errors.New("denied")}), WithCloudWatchClient(&fakeCloudWatch{}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -6,64 +6,140 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
 // UnusedBucket holds detailed info about a low-usage S3 bucket.
 type UnusedBucket struct {
-	BucketName     string    // S3 bucket name
-	CreationDate   time.Time // when the bucket was created
-	AvgObjectCount float64   // average number of objects over the period
+	BucketName     string            // S3 bucket name
+	Region         string            // region the bucket lives in
+	CreationDate   time.Time         // when the bucket was created
+	AvgObjectCount float64           // average number of objects over the period
+	Tags           map[string]string // tags of the bucket, only read when tag rules are set
+}
+
+// finding converts the bucket into the shared finding shape.
+func (u UnusedBucket) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.BucketName,
+		ResourceKind: kindS3,
+		Tags:         u.Tags,
+		Name:         u.BucketName,
+		Region:       u.Region,
+		CreatedAt:    u.CreationDate,
+		Metrics:      map[string]float64{"avg_object_count": u.AvgObjectCount},
+	}
 }
 
 // GetUnusedS3Buckets lists all S3 buckets, computes their average object count over 'days',
-// and returns detailed and summary metrics for those below 'threshold'.
+// and returns detailed and summary metrics for those below 'threshold'. Buckets are global,
+// so 'region' only selects the endpoint; subtotals are keyed by each bucket's own region.
+// S3 storage metrics only exist in the bucket's region, so CloudWatch is asked there;
+// so are bucket tags, which S3 only serves from the bucket's region.
 func GetUnusedS3Buckets(
 	ctx context.Context,
 	region string,
 	threshold float64,
 	days int,
+	opts ...Option,
 ) (UnusedResourceMetrics, error) {
-	// Load AWS config
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	// Resolve clients for the specified region
+	o := newOptions(opts)
+	s3Client, err := o.s3Client(ctx, region)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
 	}
-	s3Client := s3.NewFromConfig(cfg)
-	cwClient := cloudwatch.NewFromConfig(cfg)
+	s3Clients := map[string]S3API{region: s3Client}
+	cwClients := map[string]CloudWatchAPI{}
 
 	// List all buckets
 	buckets, err := listAllBuckets(ctx, s3Client)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("ListBuckets", region, err)
 	}
 
 	metrics := UnusedResourceMetrics{
 		ResourceIDs:          make([]string, 0, len(buckets)),
 		TotalInstancesCount:  len(buckets),
 		UnusedInstancesCount: 0,
+		Regions:              map[string]scanner.Subtotal{},
 	}
-	var unused []UnusedBucket
 
+	progress := scanner.ProgressOf(ctx)
+	progress.Found(len(buckets))
 	for _, b := range buckets {
-		avgCount, err := getAvgObjectCount(ctx, cwClient, *b.Name, days)
+		progress.Processed(1)
+		name := aws.ToString(b.Name)
+		bucketRegion := aws.ToString(b.BucketRegion)
+		if bucketRegion == "" {
+			bucketRegion = region
+		}
+		var tags map[string]string
+		if !o.tags.Empty() {
+			tagsClient, ok := s3Clients[bucketRegion]
+			if !ok {
+				if tagsClient, err = o.s3Client(ctx, bucketRegion); err != nil {
+					return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", bucketRegion, err)
+				}
+				s3Clients[bucketRegion] = tagsClient
+			}
+			if tags, err = bucketTags(ctx, tagsClient, name); err != nil {
+				if ctx.Err() != nil {
+					return UnusedResourceMetrics{}, ctx.Err()
+				}
+				skipped := scanner.Skip(name, wrapError("GetBucketTagging", name, err))
+				skipped.Region = bucketRegion
+				metrics.Skipped = append(metrics.Skipped, skipped)
+				metrics.TotalInstancesCount--
+				continue
+			}
+			if o.tags.Excludes(tags) {
+				metrics.ExcludedInstancesCount++
+				metrics.TotalInstancesCount--
+				continue
+			}
+		}
+		cwClient, ok := cwClients[bucketRegion]
+		if !ok {
+			if cwClient, err = o.cloudwatchClient(ctx, bucketRegion); err != nil {
+				return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", bucketRegion, err)
+			}
+			cwClients[bucketRegion] = cwClient
+		}
+		avgCount, err := getAvgObjectCount(ctx, cwClient, name, days)
 		if err != nil {
-			// skip on error
+			if ctx.Err() != nil {
+				return UnusedResourceMetrics{}, ctx.Err()
+			}
+			// skip on error and keep the bucket out of the KPI
+			skipped := scanner.Skip(name, wrapError("GetMetricStatistics", name, err))
+			skipped.Region = bucketRegion
+			metrics.Skipped = append(metrics.Skipped, skipped)
+			metrics.TotalInstancesCount--
 			continue
 		}
 		if avgCount < threshold {
-			unused = append(unused, UnusedBucket{
-				BucketName:     *b.Name,
-				CreationDate:   *b.CreationDate,
+			found := UnusedBucket{
+				BucketName:     name,
+				Region:         bucketRegion,
+				CreationDate:   aws.ToTime(b.CreationDate),
 				AvgObjectCount: avgCount,
-			})
-			metrics.ResourceIDs = append(metrics.ResourceIDs, *b.Name)
+				Tags:           tags,
+			}
+			metrics.Findings = append(metrics.Findings, found.finding())
+			metrics.ResourceIDs = append(metrics.ResourceIDs, name)
 			metrics.UnusedInstancesCount++
 		}
+		sub := metrics.Regions[bucketRegion]
+		sub.TotalInstancesCount++
+		if avgCount < threshold {
+			sub.UnusedInstancesCount++
+		}
+		metrics.Regions[bucketRegion] = sub
 	}
 
 	return metrics, nil
@@ -72,19 +148,24 @@ func GetUnusedS3Buckets(
 // listAllBuckets retrieves all S3 buckets in the account.
 func listAllBuckets(
 	ctx context.Context,
-	client *s3.Client,
+	client S3API,
 ) ([]s3Types.Bucket, error) {
-	resp, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		return nil, err
+	paginator := s3.NewListBucketsPaginator(client, &s3.ListBucketsInput{})
+	var result []s3Types.Bucket
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page.Buckets...)
 	}
-	return resp.Buckets, nil
+	return result, nil
 }
 
 // getAvgObjectCount fetches the average NumberOfObjects metric for a bucket over 'days'.
 func getAvgObjectCount(
 	ctx context.Context,
-	client *cloudwatch.Client,
+	client CloudWatchAPI,
 	bucketName string,
 	days int,
 ) (float64, error) {
@@ -108,12 +189,5 @@ func getAvgObjectCount(
 	if err != nil {
 		return 0, err
 	}
-	if len(resp.Datapoints) == 0 {
-		return 0, nil
-	}
-	var sum float64
-	for _, dp := range resp.Datapoints {
-		sum += *dp.Average
-	}
-	return sum / float64(len(resp.Datapoints)), nil
+	return averageOf(resp.Datapoints), nil
 }
@@ -0,0 +1,99 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestGetUnusedS3BucketsPaginates(t *testing.T) {
+	client := &fakeS3{
+		pageSize: 2,
+		buckets: []s3Types.Bucket{
+			{Name: aws.String("empty")},
+			{Name: aws.String("full")},
+			{Name: aws.String("new")},
+		},
+	}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{
+		"empty": averages(0),
+		"full":  averages(1000, 1200),
+	}}
+
+	got, err := GetUnusedS3Buckets(context.Background(), "us-east-1", 1, 7,
+		WithS3Client(client), WithCloudWatchClient(cw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"empty", "new"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 3 {
+		t.Errorf("TotalInstancesCount = %d, want 3", got.TotalInstancesCount)
+	}
+	if f := got.Findings[0]; f.ResourceKind != "s3" || f.Name != "empty" {
+		t.Errorf("finding = %+v", f)
+	}
+	dims := cw.inputs[0].Dimensions
+	if len(dims) != 2 || aws.ToString(dims[1].Value) != "AllStorageTypes" {
+		t.Errorf("Dimensions = %+v, want BucketName and StorageType=AllStorageTypes", dims)
+	}
+}
+
+func TestGetUnusedS3BucketsAsksTheBucketRegion(t *testing.T) {
+	client := &fakeS3{buckets: []s3Types.Bucket{
+		{Name: aws.String("local"), BucketRegion: aws.String("us-east-1")},
+		{Name: aws.String("remote"), BucketRegion: aws.String("eu-west-1")},
+	}}
+	// Each region's CloudWatch only has the metrics of its own buckets.
+	east := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{"local": averages(500)}}
+	west := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{"remote": averages(800)}}
+
+	got, err := GetUnusedS3Buckets(context.Background(), "us-east-1", 1, 7, WithS3Client(client),
+		WithRegionalCloudWatchClient("us-east-1", east), WithRegionalCloudWatchClient("eu-west-1", west))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.UnusedInstancesCount != 0 || got.TotalInstancesCount != 2 {
+		t.Errorf("unused %d of %d, want 0 of 2: %v", got.UnusedInstancesCount, got.TotalInstancesCount, got.ResourceIDs)
+	}
+	if len(east.inputs) != 1 || len(west.inputs) != 1 {
+		t.Errorf("CloudWatch calls: %d in us-east-1, %d in eu-west-1, want 1 each", len(east.inputs), len(west.inputs))
+	}
+	if sub := got.Regions["eu-west-1"]; sub.TotalInstancesCount != 1 {
+		t.Errorf("Regions = %v", got.Regions)
+	}
+}
+
+func TestGetUnusedS3BucketsReadsTagsInTheBucketRegion(t *testing.T) {
+	buckets := []s3Types.Bucket{
+		{Name: aws.String("local"), BucketRegion: aws.String("us-east-1")},
+		{Name: aws.String("remote"), BucketRegion: aws.String("eu-west-1")},
+	}
+	tags := map[string][]s3Types.Tag{"remote": {{Key: aws.String("finops:keep"), Value: aws.String("true")}}}
+	east := &fakeS3{buckets: buckets, tags: tags, region: "us-east-1"}
+	west := &fakeS3{buckets: buckets, tags: tags, region: "eu-west-1"}
+	cw := &fakeCloudWatch{datapoints: map[string][]cwTypes.Datapoint{"local": averages(0)}}
+
+	got, err := GetUnusedS3Buckets(context.Background(), "us-east-1", 1, 7, WithCloudWatchClient(cw),
+		WithRegionalS3Client("us-east-1", east), WithRegionalS3Client("eu-west-1", west),
+		WithTagRules(scanner.TagRules{Exclude: map[string]string{"finops:keep": "true"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Skipped) != 0 || got.ExcludedInstancesCount != 1 || got.TotalInstancesCount != 1 {
+		t.Errorf("skipped %v, excluded %d, total %d; want remote excluded and local checked", got.Skipped, got.ExcludedInstancesCount, got.TotalInstancesCount)
+	}
+	if !slices.Equal(got.ResourceIDs, []string{"local"}) {
+		t.Errorf("unused = %v, want [local]", got.ResourceIDs)
+	}
+}
@@ -2,19 +2,32 @@ package aws_unused_resources
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
 )
 
 // UnusedVpc holds detailed info about a low-usage VPC.
 type UnusedVpc struct {
-	VpcID         string // VPC ID
-	CidrBlock     string // Primary CIDR block
-	IsDefault     bool   // Whether this is the default VPC
-	InstanceCount int    // Number of running EC2 instances in this VPC
+	VpcID         string            // VPC ID
+	CidrBlock     string            // Primary CIDR block
+	IsDefault     bool              // Whether this is the default VPC
+	InstanceCount int               // Number of running EC2 instances in this VPC
+	Tags          map[string]string // tags of the VPC
+}
+
+// finding converts the VPC into the shared finding shape.
+func (u UnusedVpc) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.VpcID,
+		ResourceKind: kindVPC,
+		Tags:         u.Tags,
+		Attributes:   map[string]string{"cidr_block": u.CidrBlock, "is_default": strconv.FormatBool(u.IsDefault)},
+		Metrics:      map[string]float64{"instance_count": float64(u.InstanceCount)},
+	}
 }
 
 // GetUnusedVPCs lists all VPCs in the specified region, counts running EC2 instances
@@ -23,18 +36,19 @@ func GetUnusedVPCs(
 	ctx context.Context,
 	region string,
 	threshold int,
+	opts ...Option,
 ) (UnusedResourceMetrics, error) {
-	// Load AWS config
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	// Resolve the EC2 client for the specified region
+	o := newOptions(opts)
+	ec2Client, err := o.ec2Client(ctx, region)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("LoadDefaultConfig", region, err)
 	}
-	ec2Client := ec2.NewFromConfig(cfg)
 
 	// Retrieve all VPCs
 	vpcs, err := listAllVPCs(ctx, ec2Client)
 	if err != nil {
-		return UnusedResourceMetrics{}, err
+		return UnusedResourceMetrics{}, wrapError("DescribeVpcs", region, err)
 	}
 
 	// Prepare metrics
@@ -44,11 +58,24 @@ func GetUnusedVPCs(
 		UnusedInstancesCount: 0,
 	}
 
-	var unused []UnusedVpc
+	progress := scanner.ProgressOf(ctx)
+	progress.Found(len(vpcs))
 	for _, v := range vpcs {
-		count, err := countInstancesInVPC(ctx, ec2Client, *v.VpcId)
+		progress.Processed(1)
+		vpcID := aws.ToString(v.VpcId)
+		if o.tags.Excludes(ec2Tags(v.Tags)) {
+			metrics.ExcludedInstancesCount++
+			metrics.TotalInstancesCount--
+			continue
+		}
+		count, err := countInstancesInVPC(ctx, ec2Client, vpcID)
 		if err != nil {
-			// Skip this VPC on error
+			if ctx.Err() != nil {
+				return UnusedResourceMetrics{}, ctx.Err()
+			}
+			// Skip this VPC on error and keep it out of the KPI
+			metrics.Skipped = append(metrics.Skipped, scanner.Skip(vpcID, wrapError("DescribeInstances", vpcID, err)))
+			metrics.TotalInstancesCount--
 			continue
 		}
 
@@ -63,36 +90,44 @@ func GetUnusedVPCs(
 			if len(v.CidrBlockAssociationSet) > 0 && v.CidrBlockAssociationSet[0].CidrBlock != nil {
 				cidr = *v.CidrBlockAssociationSet[0].CidrBlock
 			}
-			unused = append(unused, UnusedVpc{
-				VpcID:         *v.VpcId,
+			found := UnusedVpc{
+				VpcID:         vpcID,
 				CidrBlock:     cidr,
 				IsDefault:     isDefault,
 				InstanceCount: count,
-			})
-			metrics.ResourceIDs = append(metrics.ResourceIDs, *v.VpcId)
+				Tags:          ec2Tags(v.Tags),
+			}
+			metrics.Findings = append(metrics.Findings, found.finding())
+			metrics.ResourceIDs = append(metrics.ResourceIDs, vpcID)
 			metrics.UnusedInstancesCount++
 		}
 	}
 
+	metrics.InRegion(region)
 	return metrics, nil
 }
 
 // listAllVPCs returns all VPCs in the AWS account for the given region.
 func listAllVPCs(
 	ctx context.Context,
-	client *ec2.Client,
+	client EC2API,
 ) ([]ec2Types.Vpc, error) {
-	resp, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
-	if err != nil {
-		return nil, err
+	paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	var result []ec2Types.Vpc
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page.Vpcs...)
 	}
-	return resp.Vpcs, nil
+	return result, nil
 }
 
 // countInstancesInVPC returns the number of running EC2 instances in the specified VPC.
 func countInstancesInVPC(
 	ctx context.Context,
-	client *ec2.Client,
+	client EC2API,
 	vpcId string,
 ) (int, error) {
 	input := &ec2.DescribeInstancesInput{
@@ -0,0 +1,45 @@
+package aws_unused_resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestGetUnusedVPCs(t *testing.T) {
+	inVPC := func(id, vpc string) ec2Types.Instance {
+		inst := runningInstance(id)
+		inst.VpcId = aws.String(vpc)
+		return inst
+	}
+	client := &fakeEC2{
+		pageSize: 1,
+		vpcs: []ec2Types.Vpc{
+			{VpcId: aws.String("vpc-empty"), IsDefault: aws.Bool(true)},
+			{VpcId: aws.String("vpc-used")},
+			{VpcId: aws.String("vpc-one")},
+		},
+		instances: []ec2Types.Instance{inVPC("i-1", "vpc-used"), inVPC("i-2", "vpc-used"), inVPC("i-3", "vpc-one")},
+	}
+
+	got, err := GetUnusedVPCs(context.Background(), "us-east-1", 1, WithEC2Client(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"vpc-empty", "vpc-one"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if f := got.Findings[0]; f.Attributes["is_default"] != "true" || f.Metrics["instance_count"] != 0 {
+		t.Errorf("finding = %+v", f)
+	}
+	if f := got.Findings[1]; f.Metrics["instance_count"] != 1 {
+		t.Errorf("instance_count = %v, want 1", f.Metrics["instance_count"])
+	}
+	if got.TotalInstancesCount != 3 {
+		t.Errorf("TotalInstancesCount = %d, want 3", got.TotalInstancesCount)
+	}
+}
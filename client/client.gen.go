@@ -0,0 +1,3090 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for JobStatus.
+const (
+	JobStatusCancelled  JobStatus = "cancelled"
+	JobStatusCancelling JobStatus = "cancelling"
+	JobStatusFailed     JobStatus = "failed"
+	JobStatusQueued     JobStatus = "queued"
+	JobStatusRunning    JobStatus = "running"
+	JobStatusSucceeded  JobStatus = "succeeded"
+)
+
+// Defines values for Kind.
+const (
+	Auth       Kind = "auth"
+	Conflict   Kind = "conflict"
+	Invalid    Kind = "invalid"
+	NotFound   Kind = "not_found"
+	Permission Kind = "permission"
+	Throttling Kind = "throttling"
+	Unknown    Kind = "unknown"
+)
+
+// Defines values for PlanAction.
+const (
+	ReleaseAddresses      PlanAction = "release-addresses"
+	SnapshotDeleteVolumes PlanAction = "snapshot-delete-volumes"
+	StopInstances         PlanAction = "stop-instances"
+)
+
+// Defines values for PlanStatus.
+const (
+	PlanStatusApproved  PlanStatus = "approved"
+	PlanStatusExecuted  PlanStatus = "executed"
+	PlanStatusExecuting PlanStatus = "executing"
+	PlanStatusFailed    PlanStatus = "failed"
+	PlanStatusPending   PlanStatus = "pending"
+	PlanStatusRejected  PlanStatus = "rejected"
+)
+
+// Defines values for Provider.
+const (
+	Aws Provider = "aws"
+	Gcp Provider = "gcp"
+)
+
+// Defines values for ScannerScope.
+const (
+	Global   ScannerScope = "global"
+	Regional ScannerScope = "regional"
+	Zonal    ScannerScope = "zonal"
+)
+
+// Defines values for ScheduledRunStatus.
+const (
+	ScheduledRunStatusFailed    ScheduledRunStatus = "failed"
+	ScheduledRunStatusRunning   ScheduledRunStatus = "running"
+	ScheduledRunStatusSkipped   ScheduledRunStatus = "skipped"
+	ScheduledRunStatusSucceeded ScheduledRunStatus = "succeeded"
+)
+
+// Defines values for ListFindingsParamsSort.
+const (
+	Age       ListFindingsParamsSort = "age"
+	Cost      ListFindingsParamsSort = "cost"
+	MinusAge  ListFindingsParamsSort = "-age"
+	MinusCost ListFindingsParamsSort = "-cost"
+)
+
+// Decision defines model for Decision.
+type Decision struct {
+	Comment *string `json:"comment,omitempty"`
+	User    string  `json:"user"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody defines model for ErrorBody.
+type ErrorBody struct {
+	// Kind Why a request or a cloud API call failed
+	Kind    Kind   `json:"kind"`
+	Message string `json:"message"`
+
+	// Params The problem with each bad parameter
+	Params *map[string]string `json:"params,omitempty"`
+
+	// Status The HTTP status
+	Status int `json:"status"`
+}
+
+// Finding defines model for Finding.
+type Finding struct {
+	Account      *string            `json:"account,omitempty"`
+	AccountAlias *string            `json:"account_alias,omitempty"`
+	Attributes   *map[string]string `json:"attributes,omitempty"`
+
+	// CreatedAt Zero when the cloud API does not report it
+	CreatedAt      time.Time           `json:"created_at"`
+	Metrics        *map[string]float64 `json:"metrics,omitempty"`
+	MonthlyCostUsd float64             `json:"monthly_cost_usd"`
+	Name           *string             `json:"name,omitempty"`
+	Project        *string             `json:"project,omitempty"`
+
+	// Region Region or zone of the resource
+	Region       *string            `json:"region,omitempty"`
+	ResourceId   string             `json:"resource_id"`
+	ResourceKind string             `json:"resource_kind"`
+	Tags         *map[string]string `json:"tags,omitempty"`
+}
+
+// FindingPage defines model for FindingPage.
+type FindingPage struct {
+	Findings []Listed `json:"findings"`
+
+	// NextCursor Absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Total Findings matching the query across all pages
+	Total int `json:"total"`
+}
+
+// Job defines model for Job.
+type Job struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	Detector   string     `json:"detector"`
+	Error      *string    `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Id         string     `json:"id"`
+
+	// Kind Why a request or a cloud API call failed
+	Kind       *Kind      `json:"kind,omitempty"`
+	Parameters Parameters `json:"parameters"`
+	Progress   Progress   `json:"progress"`
+	Result     *Result    `json:"result,omitempty"`
+	Scope      Scope      `json:"scope"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	Status     JobStatus  `json:"status"`
+}
+
+// JobStatus defines model for Job.Status.
+type JobStatus string
+
+// JobList defines model for JobList.
+type JobList struct {
+	Scans []Job `json:"scans"`
+}
+
+// KPI defines model for KPI.
+type KPI struct {
+	ExcludedCount int `json:"excluded_count"`
+
+	// MonthlyCostUsd Estimated monthly waste in USD
+	MonthlyCostUsd float64 `json:"monthly_cost_usd"`
+	Percentage     float64 `json:"percentage"`
+
+	// SizePercentage Unused share of the storage, when the resources have a size
+	SizePercentage *float64 `json:"size_percentage,omitempty"`
+	TotalCount     int      `json:"total_count"`
+	TotalSizeGib   *float64 `json:"total_size_gib,omitempty"`
+	UnusedCount    int      `json:"unused_count"`
+	UnusedSizeGib  *float64 `json:"unused_size_gib,omitempty"`
+}
+
+// Kind Why a request or a cloud API call failed
+type Kind string
+
+// Listed defines model for Listed.
+type Listed struct {
+	Account      *string            `json:"account,omitempty"`
+	AccountAlias *string            `json:"account_alias,omitempty"`
+	Attributes   *map[string]string `json:"attributes,omitempty"`
+
+	// CreatedAt Zero when the cloud API does not report it
+	CreatedAt      time.Time           `json:"created_at"`
+	Detector       string              `json:"detector"`
+	Metrics        *map[string]float64 `json:"metrics,omitempty"`
+	MonthlyCostUsd float64             `json:"monthly_cost_usd"`
+	Name           *string             `json:"name,omitempty"`
+	Project        *string             `json:"project,omitempty"`
+	Provider       Provider            `json:"provider"`
+
+	// Region Region or zone of the resource
+	Region       *string            `json:"region,omitempty"`
+	ResourceId   string             `json:"resource_id"`
+	ResourceKind string             `json:"resource_kind"`
+	RunId        string             `json:"run_id"`
+	ScannedAt    time.Time          `json:"scanned_at"`
+	Tags         *map[string]string `json:"tags,omitempty"`
+}
+
+// Parameters defines model for Parameters.
+type Parameters struct {
+	Days      *int     `json:"days,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+}
+
+// PlainError defines model for PlainError.
+type PlainError struct {
+	Error string `json:"error"`
+
+	// Kind Why a request or a cloud API call failed
+	Kind Kind `json:"kind"`
+}
+
+// Plan defines model for Plan.
+type Plan struct {
+	Action PlanAction `json:"action"`
+
+	// Comment Why it was approved or rejected
+	Comment   *string    `json:"comment,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+
+	// DecidedBy Who approved or rejected the plan
+	DecidedBy  *string    `json:"decided_by,omitempty"`
+	Detector   string     `json:"detector"`
+	Error      *string    `json:"error,omitempty"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+	Id         string     `json:"id"`
+
+	// Preview Dry run output when the plan was created
+	Preview     interface{} `json:"preview,omitempty"`
+	Reason      *string     `json:"reason,omitempty"`
+	RequestedBy string      `json:"requested_by"`
+
+	// Result Actions taken when the plan was executed
+	Result interface{} `json:"result,omitempty"`
+
+	// Role Role assumed in the accounts of the targets
+	Role    *string    `json:"role,omitempty"`
+	Status  PlanStatus `json:"status"`
+	Targets []Finding  `json:"targets"`
+}
+
+// PlanAction defines model for Plan.Action.
+type PlanAction string
+
+// PlanStatus defines model for Plan.Status.
+type PlanStatus string
+
+// PlanRequest defines model for PlanRequest.
+type PlanRequest struct {
+	// Detector A detector whose findings can be remediated: aws/ebs, aws/ec2 or gcp/ips
+	Detector    string  `json:"detector"`
+	Reason      *string `json:"reason,omitempty"`
+	RequestedBy string  `json:"requested_by"`
+
+	// ResourceIds Findings to remediate; empty means all of them
+	ResourceIds *[]string `json:"resource_ids,omitempty"`
+
+	// Scope Account, project, region or zone to scan, one the config scans. The config's role, parent and tag rules always apply; sending others is invalid
+	Scope *Scope `json:"scope,omitempty"`
+}
+
+// Progress defines model for Progress.
+type Progress struct {
+	Processed int `json:"processed"`
+
+	// Total Resources found so far
+	Total int `json:"total"`
+}
+
+// ProjectFilter defines model for ProjectFilter.
+type ProjectFilter struct {
+	Exclude *[]string          `json:"exclude,omitempty"`
+	Include *[]string          `json:"include,omitempty"`
+	Labels  *map[string]string `json:"labels,omitempty"`
+}
+
+// Provider defines model for Provider.
+type Provider string
+
+// ResourceKPI defines model for ResourceKPI.
+type ResourceKPI struct {
+	Detector      string `json:"detector"`
+	ExcludedCount int    `json:"excluded_count"`
+
+	// MonthlyCostUsd Estimated monthly waste in USD
+	MonthlyCostUsd float64   `json:"monthly_cost_usd"`
+	Percentage     float64   `json:"percentage"`
+	Provider       Provider  `json:"provider"`
+	ResourceKind   string    `json:"resource_kind"`
+	RunId          string    `json:"run_id"`
+	ScannedAt      time.Time `json:"scanned_at"`
+
+	// SizePercentage Unused share of the storage, when the resources have a size
+	SizePercentage *float64 `json:"size_percentage,omitempty"`
+	TotalCount     int      `json:"total_count"`
+	TotalSizeGib   *float64 `json:"total_size_gib,omitempty"`
+	UnusedCount    int      `json:"unused_count"`
+	UnusedSizeGib  *float64 `json:"unused_size_gib,omitempty"`
+}
+
+// Result defines model for Result.
+type Result struct {
+	Accounts       *map[string]Subtotal `json:"accounts,omitempty"`
+	ExcludedCount  int                  `json:"excluded_count"`
+	Findings       []Finding            `json:"findings"`
+	MonthlyCostUsd float64              `json:"monthly_cost_usd"`
+	Projects       *map[string]Subtotal `json:"projects,omitempty"`
+	Regions        *map[string]Subtotal `json:"regions,omitempty"`
+	ResourceIds    []string             `json:"resource_ids"`
+	Skipped        *[]Skipped           `json:"skipped,omitempty"`
+	TotalCount     int                  `json:"total_count"`
+	TotalSizeGib   *float64             `json:"total_size_gib,omitempty"`
+	UnusedCount    int                  `json:"unused_count"`
+	UnusedSizeGib  *float64             `json:"unused_size_gib,omitempty"`
+}
+
+// ScanRequest A detector and the query parameters of GET /v1/{provider}/{resource}
+type ScanRequest struct {
+	Account *string `json:"account,omitempty"`
+	Days    *int    `json:"days,omitempty"`
+
+	// Detector e.g. aws/ebs
+	Detector  string   `json:"detector"`
+	Project   *string  `json:"project,omitempty"`
+	Region    *string  `json:"region,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Zone      *string  `json:"zone,omitempty"`
+}
+
+// ScanResult defines model for ScanResult.
+type ScanResult struct {
+	Accounts       map[string]Subtotal `json:"accounts"`
+	Detector       string              `json:"detector"`
+	ExcludedCount  int                 `json:"excluded_count"`
+	Findings       []Finding           `json:"findings"`
+	MonthlyCostUsd float64             `json:"monthly_cost_usd"`
+	Parameters     Parameters          `json:"parameters"`
+
+	// Percentage Unused share of the resources, 0 when there are none
+	Percentage  float64             `json:"percentage"`
+	Projects    map[string]Subtotal `json:"projects"`
+	Regions     map[string]Subtotal `json:"regions"`
+	ResourceIds []string            `json:"resource_ids"`
+	ScannedAt   time.Time           `json:"scanned_at"`
+	Scope       Scope               `json:"scope"`
+	Skipped     []Skipped           `json:"skipped"`
+	TotalCount  int                 `json:"total_count"`
+	UnusedCount int                 `json:"unused_count"`
+}
+
+// Scanner defines model for Scanner.
+type Scanner struct {
+	Defaults Parameters `json:"defaults"`
+
+	// Name e.g. aws/ebs
+	Name         string   `json:"name"`
+	Provider     Provider `json:"provider"`
+	ResourceKind string   `json:"resource_kind"`
+
+	// Scope Location the detector needs
+	Scope ScannerScope `json:"scope"`
+}
+
+// ScannerScope Location the detector needs
+type ScannerScope string
+
+// Schedule defines model for Schedule.
+type Schedule struct {
+	Detector string    `json:"detector"`
+	NextRun  time.Time `json:"next_run"`
+
+	// Schedule Cron expression or @every duration
+	Schedule string `json:"schedule"`
+}
+
+// ScheduledRun defines model for ScheduledRun.
+type ScheduledRun struct {
+	Detector        string     `json:"detector"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	Error           *string    `json:"error,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+
+	// Kind Why a request or a cloud API call failed
+	Kind *Kind `json:"kind,omitempty"`
+
+	// RunId The stored run, once succeeded
+	RunId       *string            `json:"run_id,omitempty"`
+	StartedAt   time.Time          `json:"started_at"`
+	Status      ScheduledRunStatus `json:"status"`
+	TotalCount  int                `json:"total_count"`
+	UnusedCount int                `json:"unused_count"`
+}
+
+// ScheduledRunStatus defines model for ScheduledRun.Status.
+type ScheduledRunStatus string
+
+// ScheduledScans defines model for ScheduledScans.
+type ScheduledScans struct {
+	Runs      []ScheduledRun `json:"runs"`
+	Schedules []Schedule     `json:"schedules"`
+}
+
+// Scope defines model for Scope.
+type Scope struct {
+	Account  *string       `json:"account,omitempty"`
+	Parent   *string       `json:"parent,omitempty"`
+	Project  *string       `json:"project,omitempty"`
+	Projects ProjectFilter `json:"projects"`
+	Region   *string       `json:"region,omitempty"`
+	Role     *string       `json:"role,omitempty"`
+	Tags     TagRules      `json:"tags"`
+	Zone     *string       `json:"zone,omitempty"`
+}
+
+// Skipped defines model for Skipped.
+type Skipped struct {
+	Account *string `json:"account,omitempty"`
+
+	// Kind Why a request or a cloud API call failed
+	Kind       Kind    `json:"kind"`
+	Project    *string `json:"project,omitempty"`
+	Reason     string  `json:"reason"`
+	Region     *string `json:"region,omitempty"`
+	ResourceId string  `json:"resource_id"`
+}
+
+// Subtotal defines model for Subtotal.
+type Subtotal struct {
+	TotalCount  int `json:"total_count"`
+	UnusedCount int `json:"unused_count"`
+}
+
+// Summary defines model for Summary.
+type Summary struct {
+	// Missing Detectors not run yet, left out of the KPIs
+	Missing   *[]string      `json:"missing,omitempty"`
+	Overall   KPI            `json:"overall"`
+	Providers map[string]KPI `json:"providers"`
+	Resources []ResourceKPI  `json:"resources"`
+}
+
+// TagRules defines model for TagRules.
+type TagRules struct {
+	// Exclude Tags any one of which excludes a resource
+	Exclude *map[string]string `json:"exclude,omitempty"`
+
+	// Include Tags a resource must all carry; an empty value matches any value
+	Include *map[string]string `json:"include,omitempty"`
+}
+
+// ListScheduledScansParams defines parameters for ListScheduledScans.
+type ListScheduledScansParams struct {
+	// Detector Only the scans of this detector
+	Detector *string `form:"detector,omitempty" json:"detector,omitempty"`
+
+	// Limit At most this many scans
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListFindingsParams defines parameters for ListFindings.
+type ListFindingsParams struct {
+	Provider *[]Provider `form:"provider,omitempty" json:"provider,omitempty"`
+	Detector *[]string   `form:"detector,omitempty" json:"detector,omitempty"`
+
+	// Account Account IDs or aliases
+	Account *[]string `form:"account,omitempty" json:"account,omitempty"`
+	Project *[]string `form:"project,omitempty" json:"project,omitempty"`
+
+	// Region Regions, matching their zones too, or zones
+	Region *[]string `form:"region,omitempty" json:"region,omitempty"`
+
+	// Kind Resource kinds, e.g. ebs
+	Kind *[]string `form:"kind,omitempty" json:"kind,omitempty"`
+
+	// Tag key or key=value; findings must carry every tag. S3 buckets and load balancers carry tags only when tag rules are configured
+	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// MinAgeDays Only resources created at least this many days ago
+	MinAgeDays *float64 `form:"min_age_days,omitempty" json:"min_age_days,omitempty"`
+
+	// MaxAgeDays Only resources created at most this many days ago
+	MaxAgeDays *float64 `form:"max_age_days,omitempty" json:"max_age_days,omitempty"`
+
+	// MinCost Estimated monthly cost in USD, at least
+	MinCost *float64 `form:"min_cost,omitempty" json:"min_cost,omitempty"`
+
+	// MaxCost Estimated monthly cost in USD, at most
+	MaxCost *float64 `form:"max_cost,omitempty" json:"max_cost,omitempty"`
+
+	// Sort -cost, the default, cost, -age (oldest first) or age
+	Sort *ListFindingsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Page size, 100 by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor next_cursor of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListFindingsParamsSort defines parameters for ListFindings.
+type ListFindingsParamsSort string
+
+// ScanParams defines parameters for Scan.
+type ScanParams struct {
+	// Region Regional detectors: a region or all, the default
+	Region *string `form:"region,omitempty" json:"region,omitempty"`
+
+	// Zone Zonal GCP detectors: a zone or all, the default
+	Zone *string `form:"zone,omitempty" json:"zone,omitempty"`
+
+	// Project GCP detectors, required: the project ID
+	Project *string `form:"project,omitempty" json:"project,omitempty"`
+
+	// Account AWS detectors: a 12 digit account ID or all; the credentials' own by default
+	Account *string `form:"account,omitempty" json:"account,omitempty"`
+
+	// Threshold Metric value below which a resource is unused; the config's or the detector's default otherwise
+	Threshold *float64 `form:"threshold,omitempty" json:"threshold,omitempty"`
+
+	// Days Look-back window for metrics; the config's or the detector's default otherwise
+	Days *int `form:"days,omitempty" json:"days,omitempty"`
+}
+
+// CreatePlanJSONRequestBody defines body for CreatePlan for application/json ContentType.
+type CreatePlanJSONRequestBody = PlanRequest
+
+// ApprovePlanJSONRequestBody defines body for ApprovePlan for application/json ContentType.
+type ApprovePlanJSONRequestBody = Decision
+
+// RejectPlanJSONRequestBody defines body for RejectPlan for application/json ContentType.
+type RejectPlanJSONRequestBody = Decision
+
+// SubmitScanJSONRequestBody defines body for SubmitScan for application/json ContentType.
+type SubmitScanJSONRequestBody = ScanRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// Healthcheck request
+	Healthcheck(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPI request
+	GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListPlans request
+	ListPlans(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreatePlanWithBody request with any body
+	CreatePlanWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreatePlan(ctx context.Context, body CreatePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetPlan request
+	GetPlan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ApprovePlanWithBody request with any body
+	ApprovePlanWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	ApprovePlan(ctx context.Context, id string, body ApprovePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ExecutePlan request
+	ExecutePlan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RejectPlanWithBody request with any body
+	RejectPlanWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	RejectPlan(ctx context.Context, id string, body RejectPlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListScanners request
+	ListScanners(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListScheduledScans request
+	ListScheduledScans(ctx context.Context, params *ListScheduledScansParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListFindings request
+	ListFindings(ctx context.Context, params *ListFindingsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetKPI request
+	GetKPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListScans request
+	ListScans(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SubmitScanWithBody request with any body
+	SubmitScanWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SubmitScan(ctx context.Context, body SubmitScanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelScan request
+	CancelScan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetScan request
+	GetScan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Scan request
+	Scan(ctx context.Context, provider Provider, resource string, params *ScanParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) Healthcheck(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHealthcheckRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPIRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListPlans(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListPlansRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreatePlanWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreatePlanRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreatePlan(ctx context.Context, body CreatePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreatePlanRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetPlan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetPlanRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ApprovePlanWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewApprovePlanRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ApprovePlan(ctx context.Context, id string, body ApprovePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewApprovePlanRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ExecutePlan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewExecutePlanRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RejectPlanWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRejectPlanRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RejectPlan(ctx context.Context, id string, body RejectPlanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRejectPlanRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListScanners(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListScannersRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListScheduledScans(ctx context.Context, params *ListScheduledScansParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListScheduledScansRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListFindings(ctx context.Context, params *ListFindingsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListFindingsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetKPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetKPIRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListScans(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListScansRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) SubmitScanWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSubmitScanRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) SubmitScan(ctx context.Context, body SubmitScanJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSubmitScanRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelScan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelScanRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetScan(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetScanRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Scan(ctx context.Context, provider Provider, resource string, params *ScanParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScanRequest(c.Server, provider, resource, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewHealthcheckRequest generates requests for Healthcheck
+func NewHealthcheckRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/healthcheck")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPIRequest generates requests for GetOpenAPI
+func NewGetOpenAPIRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/openapi.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListPlansRequest generates requests for ListPlans
+func NewListPlansRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreatePlanRequest calls the generic CreatePlan builder with application/json body
+func NewCreatePlanRequest(server string, body CreatePlanJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreatePlanRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewCreatePlanRequestWithBody generates requests for CreatePlan with any type of body
+func NewCreatePlanRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetPlanRequest generates requests for GetPlan
+func NewGetPlanRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewApprovePlanRequest calls the generic ApprovePlan builder with application/json body
+func NewApprovePlanRequest(server string, id string, body ApprovePlanJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewApprovePlanRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewApprovePlanRequestWithBody generates requests for ApprovePlan with any type of body
+func NewApprovePlanRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans/%s/approve", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewExecutePlanRequest generates requests for ExecutePlan
+func NewExecutePlanRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans/%s/execute", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewRejectPlanRequest calls the generic RejectPlan builder with application/json body
+func NewRejectPlanRequest(server string, id string, body RejectPlanJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewRejectPlanRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewRejectPlanRequestWithBody generates requests for RejectPlan with any type of body
+func NewRejectPlanRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/plans/%s/reject", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewListScannersRequest generates requests for ListScanners
+func NewListScannersRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/scanners")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListScheduledScansRequest generates requests for ListScheduledScans
+func NewListScheduledScansRequest(server string, params *ListScheduledScansParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/scans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Detector != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "detector", runtime.ParamLocationQuery, *params.Detector); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListFindingsRequest generates requests for ListFindings
+func NewListFindingsRequest(server string, params *ListFindingsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/findings")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Provider != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "provider", runtime.ParamLocationQuery, *params.Provider); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Detector != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "detector", runtime.ParamLocationQuery, *params.Detector); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Account != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "account", runtime.ParamLocationQuery, *params.Account); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Project != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "project", runtime.ParamLocationQuery, *params.Project); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Region != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "region", runtime.ParamLocationQuery, *params.Region); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Kind != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "kind", runtime.ParamLocationQuery, *params.Kind); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Tag != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "tag", runtime.ParamLocationQuery, *params.Tag); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.MinAgeDays != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "min_age_days", runtime.ParamLocationQuery, *params.MinAgeDays); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.MaxAgeDays != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "max_age_days", runtime.ParamLocationQuery, *params.MaxAgeDays); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.MinCost != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "min_cost", runtime.ParamLocationQuery, *params.MinCost); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.MaxCost != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "max_cost", runtime.ParamLocationQuery, *params.MaxCost); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Sort != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sort", runtime.ParamLocationQuery, *params.Sort); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetKPIRequest generates requests for GetKPI
+func NewGetKPIRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/kpi")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListScansRequest generates requests for ListScans
+func NewListScansRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/scans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSubmitScanRequest calls the generic SubmitScan builder with application/json body
+func NewSubmitScanRequest(server string, body SubmitScanJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSubmitScanRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewSubmitScanRequestWithBody generates requests for SubmitScan with any type of body
+func NewSubmitScanRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/scans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewCancelScanRequest generates requests for CancelScan
+func NewCancelScanRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/scans/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetScanRequest generates requests for GetScan
+func NewGetScanRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/scans/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewScanRequest generates requests for Scan
+func NewScanRequest(server string, provider Provider, resource string, params *ScanParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "provider", runtime.ParamLocationPath, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "resource", runtime.ParamLocationPath, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Region != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "region", runtime.ParamLocationQuery, *params.Region); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Zone != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "zone", runtime.ParamLocationQuery, *params.Zone); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Project != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "project", runtime.ParamLocationQuery, *params.Project); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Account != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "account", runtime.ParamLocationQuery, *params.Account); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Threshold != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "threshold", runtime.ParamLocationQuery, *params.Threshold); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Days != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "days", runtime.ParamLocationQuery, *params.Days); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// HealthcheckWithResponse request
+	HealthcheckWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthcheckResponse, error)
+
+	// GetOpenAPIWithResponse request
+	GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error)
+
+	// ListPlansWithResponse request
+	ListPlansWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListPlansResponse, error)
+
+	// CreatePlanWithBodyWithResponse request with any body
+	CreatePlanWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreatePlanResponse, error)
+
+	CreatePlanWithResponse(ctx context.Context, body CreatePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*CreatePlanResponse, error)
+
+	// GetPlanWithResponse request
+	GetPlanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetPlanResponse, error)
+
+	// ApprovePlanWithBodyWithResponse request with any body
+	ApprovePlanWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ApprovePlanResponse, error)
+
+	ApprovePlanWithResponse(ctx context.Context, id string, body ApprovePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*ApprovePlanResponse, error)
+
+	// ExecutePlanWithResponse request
+	ExecutePlanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ExecutePlanResponse, error)
+
+	// RejectPlanWithBodyWithResponse request with any body
+	RejectPlanWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RejectPlanResponse, error)
+
+	RejectPlanWithResponse(ctx context.Context, id string, body RejectPlanJSONRequestBody, reqEditors ...RequestEditorFn) (*RejectPlanResponse, error)
+
+	// ListScannersWithResponse request
+	ListScannersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListScannersResponse, error)
+
+	// ListScheduledScansWithResponse request
+	ListScheduledScansWithResponse(ctx context.Context, params *ListScheduledScansParams, reqEditors ...RequestEditorFn) (*ListScheduledScansResponse, error)
+
+	// ListFindingsWithResponse request
+	ListFindingsWithResponse(ctx context.Context, params *ListFindingsParams, reqEditors ...RequestEditorFn) (*ListFindingsResponse, error)
+
+	// GetKPIWithResponse request
+	GetKPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetKPIResponse, error)
+
+	// ListScansWithResponse request
+	ListScansWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListScansResponse, error)
+
+	// SubmitScanWithBodyWithResponse request with any body
+	SubmitScanWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SubmitScanResponse, error)
+
+	SubmitScanWithResponse(ctx context.Context, body SubmitScanJSONRequestBody, reqEditors ...RequestEditorFn) (*SubmitScanResponse, error)
+
+	// CancelScanWithResponse request
+	CancelScanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelScanResponse, error)
+
+	// GetScanWithResponse request
+	GetScanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetScanResponse, error)
+
+	// ScanWithResponse request
+	ScanWithResponse(ctx context.Context, provider Provider, resource string, params *ScanParams, reqEditors ...RequestEditorFn) (*ScanResponse, error)
+}
+
+type HealthcheckResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Status Healthcheck200Status `json:"status"`
+	}
+}
+type Healthcheck200Status string
+
+// Status returns HTTPResponse.Status
+func (r HealthcheckResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HealthcheckResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetOpenAPIResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPIResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPIResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListPlansResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r ListPlansResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListPlansResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreatePlanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r CreatePlanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreatePlanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetPlanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r GetPlanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetPlanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ApprovePlanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r ApprovePlanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ApprovePlanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ExecutePlanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r ExecutePlanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ExecutePlanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RejectPlanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Plan
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r RejectPlanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RejectPlanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListScannersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Scanner
+}
+
+// Status returns HTTPResponse.Status
+func (r ListScannersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListScannersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListScheduledScansResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ScheduledScans
+	JSONDefault  *PlainError
+}
+
+// Status returns HTTPResponse.Status
+func (r ListScheduledScansResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListScheduledScansResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListFindingsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *FindingPage
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListFindingsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListFindingsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetKPIResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Summary
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetKPIResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetKPIResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListScansResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *JobList
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListScansResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListScansResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SubmitScanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Job
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SubmitScanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SubmitScanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelScanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Job
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelScanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelScanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetScanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Job
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetScanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetScanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScanResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ScanResult
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScanResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScanResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// HealthcheckWithResponse request returning *HealthcheckResponse
+func (c *ClientWithResponses) HealthcheckWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthcheckResponse, error) {
+	rsp, err := c.Healthcheck(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHealthcheckResponse(rsp)
+}
+
+// GetOpenAPIWithResponse request returning *GetOpenAPIResponse
+func (c *ClientWithResponses) GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error) {
+	rsp, err := c.GetOpenAPI(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPIResponse(rsp)
+}
+
+// ListPlansWithResponse request returning *ListPlansResponse
+func (c *ClientWithResponses) ListPlansWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListPlansResponse, error) {
+	rsp, err := c.ListPlans(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListPlansResponse(rsp)
+}
+
+// CreatePlanWithBodyWithResponse request with arbitrary body returning *CreatePlanResponse
+func (c *ClientWithResponses) CreatePlanWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreatePlanResponse, error) {
+	rsp, err := c.CreatePlanWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreatePlanResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreatePlanWithResponse(ctx context.Context, body CreatePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*CreatePlanResponse, error) {
+	rsp, err := c.CreatePlan(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreatePlanResponse(rsp)
+}
+
+// GetPlanWithResponse request returning *GetPlanResponse
+func (c *ClientWithResponses) GetPlanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetPlanResponse, error) {
+	rsp, err := c.GetPlan(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetPlanResponse(rsp)
+}
+
+// ApprovePlanWithBodyWithResponse request with arbitrary body returning *ApprovePlanResponse
+func (c *ClientWithResponses) ApprovePlanWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ApprovePlanResponse, error) {
+	rsp, err := c.ApprovePlanWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseApprovePlanResponse(rsp)
+}
+
+func (c *ClientWithResponses) ApprovePlanWithResponse(ctx context.Context, id string, body ApprovePlanJSONRequestBody, reqEditors ...RequestEditorFn) (*ApprovePlanResponse, error) {
+	rsp, err := c.ApprovePlan(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseApprovePlanResponse(rsp)
+}
+
+// ExecutePlanWithResponse request returning *ExecutePlanResponse
+func (c *ClientWithResponses) ExecutePlanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ExecutePlanResponse, error) {
+	rsp, err := c.ExecutePlan(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExecutePlanResponse(rsp)
+}
+
+// RejectPlanWithBodyWithResponse request with arbitrary body returning *RejectPlanResponse
+func (c *ClientWithResponses) RejectPlanWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*RejectPlanResponse, error) {
+	rsp, err := c.RejectPlanWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRejectPlanResponse(rsp)
+}
+
+func (c *ClientWithResponses) RejectPlanWithResponse(ctx context.Context, id string, body RejectPlanJSONRequestBody, reqEditors ...RequestEditorFn) (*RejectPlanResponse, error) {
+	rsp, err := c.RejectPlan(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRejectPlanResponse(rsp)
+}
+
+// ListScannersWithResponse request returning *ListScannersResponse
+func (c *ClientWithResponses) ListScannersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListScannersResponse, error) {
+	rsp, err := c.ListScanners(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListScannersResponse(rsp)
+}
+
+// ListScheduledScansWithResponse request returning *ListScheduledScansResponse
+func (c *ClientWithResponses) ListScheduledScansWithResponse(ctx context.Context, params *ListScheduledScansParams, reqEditors ...RequestEditorFn) (*ListScheduledScansResponse, error) {
+	rsp, err := c.ListScheduledScans(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListScheduledScansResponse(rsp)
+}
+
+// ListFindingsWithResponse request returning *ListFindingsResponse
+func (c *ClientWithResponses) ListFindingsWithResponse(ctx context.Context, params *ListFindingsParams, reqEditors ...RequestEditorFn) (*ListFindingsResponse, error) {
+	rsp, err := c.ListFindings(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListFindingsResponse(rsp)
+}
+
+// GetKPIWithResponse request returning *GetKPIResponse
+func (c *ClientWithResponses) GetKPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetKPIResponse, error) {
+	rsp, err := c.GetKPI(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetKPIResponse(rsp)
+}
+
+// ListScansWithResponse request returning *ListScansResponse
+func (c *ClientWithResponses) ListScansWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListScansResponse, error) {
+	rsp, err := c.ListScans(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListScansResponse(rsp)
+}
+
+// SubmitScanWithBodyWithResponse request with arbitrary body returning *SubmitScanResponse
+func (c *ClientWithResponses) SubmitScanWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SubmitScanResponse, error) {
+	rsp, err := c.SubmitScanWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSubmitScanResponse(rsp)
+}
+
+func (c *ClientWithResponses) SubmitScanWithResponse(ctx context.Context, body SubmitScanJSONRequestBody, reqEditors ...RequestEditorFn) (*SubmitScanResponse, error) {
+	rsp, err := c.SubmitScan(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSubmitScanResponse(rsp)
+}
+
+// CancelScanWithResponse request returning *CancelScanResponse
+func (c *ClientWithResponses) CancelScanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*CancelScanResponse, error) {
+	rsp, err := c.CancelScan(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelScanResponse(rsp)
+}
+
+// GetScanWithResponse request returning *GetScanResponse
+func (c *ClientWithResponses) GetScanWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetScanResponse, error) {
+	rsp, err := c.GetScan(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetScanResponse(rsp)
+}
+
+// ScanWithResponse request returning *ScanResponse
+func (c *ClientWithResponses) ScanWithResponse(ctx context.Context, provider Provider, resource string, params *ScanParams, reqEditors ...RequestEditorFn) (*ScanResponse, error) {
+	rsp, err := c.Scan(ctx, provider, resource, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScanResponse(rsp)
+}
+
+// ParseHealthcheckResponse parses an HTTP response from a HealthcheckWithResponse call
+func ParseHealthcheckResponse(rsp *http.Response) (*HealthcheckResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HealthcheckResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Status Healthcheck200Status `json:"status"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPIResponse parses an HTTP response from a GetOpenAPIWithResponse call
+func ParseGetOpenAPIResponse(rsp *http.Response) (*GetOpenAPIResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPIResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListPlansResponse parses an HTTP response from a ListPlansWithResponse call
+func ParseListPlansResponse(rsp *http.Response) (*ListPlansResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListPlansResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreatePlanResponse parses an HTTP response from a CreatePlanWithResponse call
+func ParseCreatePlanResponse(rsp *http.Response) (*CreatePlanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreatePlanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetPlanResponse parses an HTTP response from a GetPlanWithResponse call
+func ParseGetPlanResponse(rsp *http.Response) (*GetPlanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetPlanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseApprovePlanResponse parses an HTTP response from a ApprovePlanWithResponse call
+func ParseApprovePlanResponse(rsp *http.Response) (*ApprovePlanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ApprovePlanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseExecutePlanResponse parses an HTTP response from a ExecutePlanWithResponse call
+func ParseExecutePlanResponse(rsp *http.Response) (*ExecutePlanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ExecutePlanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRejectPlanResponse parses an HTTP response from a RejectPlanWithResponse call
+func ParseRejectPlanResponse(rsp *http.Response) (*RejectPlanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RejectPlanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Plan
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListScannersResponse parses an HTTP response from a ListScannersWithResponse call
+func ParseListScannersResponse(rsp *http.Response) (*ListScannersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListScannersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Scanner
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListScheduledScansResponse parses an HTTP response from a ListScheduledScansWithResponse call
+func ParseListScheduledScansResponse(rsp *http.Response) (*ListScheduledScansResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListScheduledScansResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ScheduledScans
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest PlainError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListFindingsResponse parses an HTTP response from a ListFindingsWithResponse call
+func ParseListFindingsResponse(rsp *http.Response) (*ListFindingsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListFindingsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest FindingPage
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetKPIResponse parses an HTTP response from a GetKPIWithResponse call
+func ParseGetKPIResponse(rsp *http.Response) (*GetKPIResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetKPIResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Summary
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListScansResponse parses an HTTP response from a ListScansWithResponse call
+func ParseListScansResponse(rsp *http.Response) (*ListScansResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListScansResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest JobList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSubmitScanResponse parses an HTTP response from a SubmitScanWithResponse call
+func ParseSubmitScanResponse(rsp *http.Response) (*SubmitScanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SubmitScanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelScanResponse parses an HTTP response from a CancelScanWithResponse call
+func ParseCancelScanResponse(rsp *http.Response) (*CancelScanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelScanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetScanResponse parses an HTTP response from a GetScanWithResponse call
+func ParseGetScanResponse(rsp *http.Response) (*GetScanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetScanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScanResponse parses an HTTP response from a ScanWithResponse call
+func ParseScanResponse(rsp *http.Response) (*ScanResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScanResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ScanResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
@@ -0,0 +1,12 @@
+// Package client is a Go client of the API of the server, generated
+// from ../openapi.json:
+//
+//	c, err := client.NewClientWithResponses("http://localhost:9090")
+//	kpi, err := c.GetKPIWithResponse(ctx)
+//	fmt.Println(kpi.JSON200.Overall.Percentage)
+//
+// Errors the server answers with are in the JSONDefault field of the
+// responses. Run go generate after changing openapi.json.
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml ../openapi.json
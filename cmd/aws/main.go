@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	_ "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+func main() {
+	region := flag.String("region", "us-east-1", "AWS region to scan, or \"all\" for every enabled region")
+	account := flag.String("account", "", "account ID to scan via -role, or \"all\" for every organization member; empty uses the default credentials")
+	role := flag.String("role", "", "role assumed in member accounts (default OrganizationAccountAccessRole)")
+	includeTags := flag.String("include-tags", "", "comma separated key=value tags a resource must carry to be scanned; a bare key matches any value")
+	excludeTags := flag.String("exclude-tags", "", "comma separated key=value tags that leave a resource out of the scan, e.g. finops:keep=true")
+	catalogPath := flag.String("pricing", "", "pricing catalog file; empty uses the catalog built into the binary")
+	only := flag.String("detectors", "aws/ec2", "comma separated detectors to run, or \"all\"")
+	configPath := flag.String("config", "", "scan configuration file (YAML or JSON) to run the aws section of, instead of the other flags")
+	flag.Parse()
+
+	cfg, err := config.FromFlags(*configPath, scanner.AWS, *only, func(cfg *config.Config) {
+		cfg.Pricing = *catalogPath
+		cfg.AWS = &config.AWS{Role: *role, Regions: []string{*region}}
+		cfg.Tags = scanner.TagRules{Include: config.ParseTags(*includeTags), Exclude: config.ParseTags(*excludeTags)}
+		if *account != "" {
+			cfg.AWS.Accounts = []string{*account}
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	catalog, err := pricing.Open(cfg.Pricing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, scan := range cfg.Scans() {
+		d := scan.Detector
+		if d.Provider != scanner.AWS {
+			continue
+		}
+		result, err := d.New(scan.Scope, scan.Params).Scan(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		catalog.Apply(d.Provider, &result)
+		fmt.Printf("\n%s unused IDs %s\nTotal Count %d\nUnused Count: %d\nExcluded Count: %d\nEstimated monthly waste: $%.2f\n",
+			d.Name,
+			result.ResourceIDs,
+			result.TotalInstancesCount,
+			result.UnusedInstancesCount,
+			result.ExcludedInstancesCount,
+			result.MonthlyCost,
+		)
+		for _, f := range result.Findings {
+			fmt.Printf("  %s %v %v $%.2f/month\n", f.ResourceID, f.Attributes, f.Metrics, f.MonthlyCost)
+		}
+		for account, sub := range result.Accounts {
+			fmt.Printf("  account %s: %d/%d unused\n", account, sub.UnusedInstancesCount, sub.TotalInstancesCount)
+		}
+		for region, sub := range result.Regions {
+			fmt.Printf("  %s: %d/%d unused\n", region, sub.UnusedInstancesCount, sub.TotalInstancesCount)
+		}
+		for _, skipped := range result.Skipped {
+			fmt.Printf("  skipped %s (%s): %s\n", skipped.ResourceID, skipped.Kind, skipped.Reason)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Command collect runs every configured scan once and stores the results
+// in the configured store for the API server to serve. It is what the cron
+// job runs.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	_ "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	_ "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+func main() {
+	configPath := flag.String("config", "", "scan configuration file (YAML or JSON); empty scans the built-in default into "+config.DefaultSQLite)
+	createTable := flag.Bool("create-table", false, "create the DynamoDB table first if it does not exist")
+	flag.Parse()
+
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	catalog, err := pricing.Open(cfg.Pricing)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s, err := store.Open(ctx, cfg.Store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
+	if table, ok := s.(*store.DynamoDB); ok && *createTable {
+		if err := table.CreateTable(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	runs, err := store.Collect(ctx, s, cfg.Scans(), catalog)
+	for _, run := range runs {
+		fmt.Printf("%-10s run %s: %d of %d unused, %.2f USD/month\n", run.Detector, run.ID,
+			run.Result.UnusedInstancesCount, run.Result.TotalInstancesCount, run.Result.MonthlyCost)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	_ "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+func main() {
+	project := flag.String("project", "finops-accelerator", "GCP project to scan")
+	parent := flag.String("parent", "", "organization or folder whose projects are scanned instead of -project, e.g. organizations/123")
+	include := flag.String("include", "", "comma separated glob patterns of project IDs to scan under -parent")
+	exclude := flag.String("exclude", "", "comma separated glob patterns of project IDs to skip under -parent")
+	labels := flag.String("labels", "", "comma separated key=value labels projects under -parent must carry; a bare key matches any value")
+	region := flag.String("region", scanner.AllRegions, "GCP region for regional detectors, or \"all\"")
+	includeLabels := flag.String("include-labels", "", "comma separated key=value labels a resource must carry to be scanned; a bare key matches any value")
+	excludeLabels := flag.String("exclude-labels", "", "comma separated key=value labels that leave a resource out of the scan, e.g. finops-keep=true")
+	zone := flag.String("zone", scanner.AllZones, "GCP zone for zonal detectors, or \"all\"")
+	catalogPath := flag.String("pricing", "", "pricing catalog file; empty uses the catalog built into the binary")
+	only := flag.String("detectors", "all", "comma separated detectors to run, or \"all\"")
+	configPath := flag.String("config", "", "scan configuration file (YAML or JSON) to run the gcp section of, instead of the other flags")
+	flag.Parse()
+
+	cfg, err := config.FromFlags(*configPath, scanner.GCP, *only, func(cfg *config.Config) {
+		cfg.Pricing = *catalogPath
+		cfg.GCP = &config.GCP{Regions: []string{*region}, Zones: []string{*zone}}
+		cfg.Tags = scanner.TagRules{Include: config.ParseTags(*includeLabels), Exclude: config.ParseTags(*excludeLabels)}
+		if *parent != "" {
+			cfg.GCP.Parent = *parent
+			cfg.GCP.Include = config.SplitList(*include)
+			cfg.GCP.Exclude = config.SplitList(*exclude)
+			cfg.GCP.Labels = config.ParseTags(*labels)
+		} else {
+			cfg.GCP.Projects = []string{*project}
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	catalog, err := pricing.Open(cfg.Pricing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, scan := range cfg.Scans() {
+		d := scan.Detector
+		if d.Provider != scanner.GCP {
+			continue
+		}
+		result, err := d.New(scan.Scope, scan.Params).Scan(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		catalog.Apply(d.Provider, &result)
+		fmt.Printf("%s unused names %s\nTotal Count %d\nUnused Count: %d\nExcluded Count: %d\nEstimated monthly waste: $%.2f\n",
+			d.Name,
+			result.ResourceIDs,
+			result.TotalInstancesCount,
+			result.UnusedInstancesCount,
+			result.ExcludedInstancesCount,
+			result.MonthlyCost,
+		)
+		for _, f := range result.Findings {
+			fmt.Printf("  %s %v %v $%.2f/month\n", f.ResourceID, f.Attributes, f.Metrics, f.MonthlyCost)
+		}
+		for project, sub := range result.Projects {
+			fmt.Printf("  project %s: %d/%d unused\n", project, sub.UnusedInstancesCount, sub.TotalInstancesCount)
+		}
+		for location, sub := range result.Regions {
+			fmt.Printf("  %s: %d/%d unused\n", location, sub.UnusedInstancesCount, sub.TotalInstancesCount)
+		}
+		for _, skipped := range result.Skipped {
+			fmt.Printf("  skipped %s (%s): %s\n", skipped.ResourceID, skipped.Kind, skipped.Reason)
+		}
+	}
+}
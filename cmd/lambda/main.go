@@ -0,0 +1,40 @@
+// Command lambda is the AWS Lambda function that scans on an EventBridge
+// schedule, or when invoked with {"detectors": [...], "regions": [...]},
+// and stores the runs in the store of the config named by $CONFIG_FILE.
+// Build it for the provided.al2023 runtime with
+//
+//	GOOS=linux GOARCH=arm64 go build -tags lambda.norpc -o bootstrap ./cmd/lambda
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	awslambda "github.com/aws/aws-lambda-go/lambda"
+	_ "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	_ "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/lambda"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+func main() {
+	cfg := config.Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		var err error
+		if cfg, err = config.Load(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+	catalog, err := pricing.Open(cfg.Pricing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := store.Open(context.Background(), cfg.Store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	awslambda.Start(lambda.NewHandler(cfg, s, catalog).Handle)
+}
@@ -0,0 +1,57 @@
+// Command pricing refreshes the offline pricing catalog from the AWS Price
+// List and GCP Cloud Billing Catalog APIs.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	unused_gcp_resources "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+func main() {
+	out := flag.String("out", "pricing.json", "catalog file to write")
+	in := flag.String("in", "", "catalog file to start from; empty starts from the catalog built into the binary")
+	awsRegions := flag.String("aws-regions", "us-east-1", "comma separated AWS regions to price, or \"all\" for every enabled region; empty skips AWS")
+	gcp := flag.Bool("gcp", true, "refresh GCP prices from the Cloud Billing Catalog")
+	flag.Parse()
+
+	ctx := context.Background()
+	catalog, err := pricing.Open(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *awsRegions != "" {
+		regions := strings.Split(*awsRegions, ",")
+		if *awsRegions == "all" {
+			if regions, err = aws_unused_resources.EnabledRegions(ctx); err != nil {
+				log.Fatal(err)
+			}
+		}
+		prices, err := aws_unused_resources.PriceList(ctx, regions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		catalog.Update(prices)
+		fmt.Printf("AWS: %d prices for %d regions\n", len(prices), len(regions))
+	}
+	if *gcp {
+		prices, err := unused_gcp_resources.BillingCatalog(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		catalog.Update(prices)
+		fmt.Printf("GCP: %d prices\n", len(prices))
+	}
+
+	if err := catalog.Save(*out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("wrote %s\n", *out)
+}
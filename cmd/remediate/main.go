@@ -0,0 +1,241 @@
+// Command remediate cleans up unused resources found by the detectors. The
+// ebs, ips and ec2-stop commands print their plan, checked with a dry run,
+// and change nothing: deleting, releasing and stopping only happen by
+// executing a plan approved through the API server's POST /plans, with the
+// execute command. ec2-start, which undoes ec2-stop, applies when given
+// -execute.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	aws_unused_resources "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	unused_gcp_resources "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/remediation"
+)
+
+const usage = `usage: remediate <command> [flags]
+
+commands:
+  ebs        plan snapshotting unattached EBS volumes, then deleting them
+  ips        plan releasing reserved GCP addresses nothing uses
+  ec2-stop   plan stopping or hibernating idle EC2 instances, tagging who, when and why
+  ec2-start  start instances ec2-stop stopped and remove its tags
+  execute    execute a plan approved through the API server
+
+Run "remediate <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "ebs":
+		err = remediateEBS(os.Args[2:])
+	case "ips":
+		err = releaseIPs(os.Args[2:])
+	case "ec2-stop":
+		err = stopEC2(os.Args[2:])
+	case "ec2-start":
+		err = startEC2(os.Args[2:])
+	case "execute":
+		err = executePlan(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// remediateEBS prints the plan for snapshotting and deleting the volumes
+// named by -volumes.
+func remediateEBS(args []string) error {
+	fs := flag.NewFlagSet("ebs", flag.ExitOnError)
+	region := fs.String("region", "us-east-1", "AWS region of the volumes")
+	volumes := fs.String("volumes", "", "comma separated IDs of the unattached volumes to delete")
+	account := fs.String("account", "", "account ID of the volumes, reached via -role; empty uses the default credentials")
+	role := fs.String("role", "", "role assumed in -account (default OrganizationAccountAccessRole)")
+	fs.Parse(args)
+
+	ids := config.SplitList(*volumes)
+	if len(ids) == 0 {
+		return fmt.Errorf("ebs: -volumes is required")
+	}
+	ctx := context.Background()
+	opts, err := aws_unused_resources.AccountOptions(ctx, *account, *role)
+	if err != nil {
+		return err
+	}
+
+	plan, err := aws_unused_resources.SnapshotAndDeleteVolumes(ctx, *region, ids, true, opts...)
+	if err != nil {
+		return fmt.Errorf("dry run failed, nothing was changed: %w", err)
+	}
+	fmt.Println("Plan (dry run passed):")
+	for _, a := range plan {
+		fmt.Printf("  snapshot then delete %s (%s, %d GiB %s)\n", a.VolumeID, a.AvailabilityZone, a.SizeGiB, a.VolumeType)
+	}
+	fmt.Println(dryRunOnly)
+	return nil
+}
+
+// releaseIPs prints the plan for releasing the addresses named by
+// -addresses.
+func releaseIPs(args []string) error {
+	fs := flag.NewFlagSet("ips", flag.ExitOnError)
+	project := fs.String("project", "finops-accelerator", "GCP project of the addresses")
+	addresses := fs.String("addresses", "", "comma separated location/name of the addresses to release, e.g. us-central1/ip-1,global/ip-2")
+	fs.Parse(args)
+
+	refs := config.SplitList(*addresses)
+	if len(refs) == 0 {
+		return fmt.Errorf("ips: -addresses is required")
+	}
+	ctx := context.Background()
+
+	plan, err := unused_gcp_resources.ReleaseAddresses(ctx, *project, refs, true, nil)
+	if err != nil {
+		return fmt.Errorf("dry run failed, nothing was changed: %w", err)
+	}
+	fmt.Println("Plan (dry run passed):")
+	for _, a := range plan {
+		fmt.Printf("  release %s/%s %s (%s) labels %v\n", a.Location, a.Name, a.Address, a.AddressType, a.Labels)
+	}
+	fmt.Println(dryRunOnly)
+	return nil
+}
+
+// stopEC2 prints the plan for stopping the instances named by -instances.
+func stopEC2(args []string) error {
+	fs := flag.NewFlagSet("ec2-stop", flag.ExitOnError)
+	region := fs.String("region", "us-east-1", "AWS region of the instances")
+	instances := fs.String("instances", "", "comma separated IDs of the idle instances to stop")
+	account := fs.String("account", "", "account ID of the instances, reached via -role; empty uses the default credentials")
+	role := fs.String("role", "", "role assumed in -account (default OrganizationAccountAccessRole)")
+	by := fs.String("by", os.Getenv("USER"), "who is stopping the instances, recorded in the finops:stopped-by tag")
+	reason := fs.String("reason", "idle: average CPU below threshold", "why, recorded in the finops:stopped-reason tag")
+	fs.Parse(args)
+
+	ids := config.SplitList(*instances)
+	if len(ids) == 0 {
+		return fmt.Errorf("ec2-stop: -instances is required")
+	}
+	ctx := context.Background()
+	opts, err := aws_unused_resources.AccountOptions(ctx, *account, *role)
+	if err != nil {
+		return err
+	}
+	req := aws_unused_resources.StopRequest{InstanceIDs: ids, By: *by, Reason: *reason, DryRun: true}
+
+	plan, err := aws_unused_resources.StopInstances(ctx, *region, req, opts...)
+	if err != nil {
+		return fmt.Errorf("dry run failed, nothing was changed: %w", err)
+	}
+	printInstanceActions("Plan (dry run passed):", "stop", plan)
+	fmt.Println(dryRunOnly)
+	return nil
+}
+
+// dryRunOnly ends the output of the commands that only plan.
+const dryRunOnly = "Dry run only; to apply, create a plan with POST /plans, have it approved, then run remediate execute -plan <id>."
+
+// executePlan executes the approved plan named by -plan from the plans of
+// the API server's config, recording its outcome there.
+func executePlan(args []string) error {
+	fs := flag.NewFlagSet("execute", flag.ExitOnError)
+	configPath := fs.String("config", "", "the API server's configuration file, for where plans and the audit log are kept; empty uses the built-in default")
+	id := fs.String("plan", "", "ID of the approved plan to execute")
+	fs.Parse(args)
+
+	if *id == "" {
+		return fmt.Errorf("execute: -plan is required")
+	}
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			return err
+		}
+	}
+	plans, err := remediation.NewFileStore(cfg.Plans())
+	if err != nil {
+		return err
+	}
+	audit, err := os.OpenFile(cfg.AuditLog(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer audit.Close()
+
+	plan, err := remediation.NewService(plans, remediation.Executors(audit)).Execute(context.Background(), *id)
+	if plan.ID == "" {
+		return err
+	}
+	fmt.Printf("Plan %s %s (%s of %d targets):\n", plan.ID, plan.Status, plan.Action, len(plan.Targets))
+	var result any
+	if json.Unmarshal(plan.Result, &result) == nil {
+		out, _ := json.MarshalIndent(result, "  ", "  ")
+		fmt.Printf("  %s\n", out)
+	}
+	return err
+}
+
+// startEC2 starts the instances ec2-stop stopped.
+func startEC2(args []string) error {
+	fs := flag.NewFlagSet("ec2-start", flag.ExitOnError)
+	region := fs.String("region", "us-east-1", "AWS region of the instances")
+	instances := fs.String("instances", "", "comma separated IDs of the instances to start; empty starts every instance ec2-stop stopped in the region")
+	account := fs.String("account", "", "account ID of the instances, reached via -role; empty uses the default credentials")
+	role := fs.String("role", "", "role assumed in -account (default OrganizationAccountAccessRole)")
+	execute := fs.Bool("execute", false, "start the instances after printing the plan; without it only the dry run runs")
+	fs.Parse(args)
+
+	ids := config.SplitList(*instances)
+	ctx := context.Background()
+	opts, err := aws_unused_resources.AccountOptions(ctx, *account, *role)
+	if err != nil {
+		return err
+	}
+
+	plan, err := aws_unused_resources.StartInstances(ctx, *region, ids, true, opts...)
+	if err != nil {
+		return fmt.Errorf("dry run failed, nothing was changed: %w", err)
+	}
+	printInstanceActions("Plan (dry run passed):", "start", plan)
+	if !*execute {
+		fmt.Println("Dry run only; rerun with -execute to apply.")
+		return nil
+	}
+	actions, err := aws_unused_resources.StartInstances(ctx, *region, ids, false, opts...)
+	printInstanceActions("Result:", "started", actions)
+	return err
+}
+
+// printInstanceActions prints one line per instance under title.
+func printInstanceActions(title, verb string, actions []aws_unused_resources.InstanceAction) {
+	fmt.Println(title)
+	for _, a := range actions {
+		switch {
+		case a.Skipped != "":
+			fmt.Printf("  skip %s: %s\n", a.InstanceID, a.Skipped)
+		case a.Error != "":
+			fmt.Printf("  failed %s: %s\n", a.InstanceID, a.Error)
+		case a.Hibernate:
+			fmt.Printf("  %s %s (%s, hibernate)\n", verb, a.InstanceID, a.InstanceType)
+		default:
+			fmt.Printf("  %s %s (%s)\n", verb, a.InstanceID, a.InstanceType)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+// Package config loads the declarative scan configuration shared by the
+// CLIs and the API server: which providers, accounts, projects and
+// locations to scan, which detectors to run and how to tune them.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAddr is where the API server listens when the config does not say.
+const DefaultAddr = ":9090"
+
+// DefaultAuditLog is where the API server audits remediations when the config does not say.
+const DefaultAuditLog = "audit.jsonl"
+
+// DefaultPlans is where the API server keeps remediation plans when the config does not say.
+const DefaultPlans = "plans"
+
+// DefaultSQLite is the database scan runs are kept in when the config names no store.
+const DefaultSQLite = "unused.db"
+
+// Config is the whole scan configuration. A provider section that is absent
+// is not scanned.
+type Config struct {
+	Server  Server `yaml:"server" json:"server"`
+	Store   Store  `yaml:"store" json:"store"`
+	Pricing string `yaml:"pricing" json:"pricing"` // pricing catalog file; empty uses the embedded catalog
+	AWS     *AWS   `yaml:"aws" json:"aws"`
+	GCP     *GCP   `yaml:"gcp" json:"gcp"`
+
+	// Schedule runs detectors periodically inside the API server.
+	Schedule Schedule `yaml:"schedule" json:"schedule"`
+
+	// Tags include or exclude resources of every detector by their AWS
+	// tags or GCP labels, e.g. exclude finops:keep=true.
+	Tags scanner.TagRules `yaml:"tags" json:"tags"`
+
+	// Detectors to run, by name, with their thresholds and look-back
+	// windows; zero values keep the detector's defaults. When empty, every
+	// detector of the configured providers runs with its defaults.
+	Detectors map[string]scanner.Parameters `yaml:"detectors" json:"detectors"`
+}
+
+// Server configures the API server.
+type Server struct {
+	Addr     string `yaml:"addr" json:"addr"`           // listen address, DefaultAddr when empty
+	AuditLog string `yaml:"audit_log" json:"audit_log"` // file remediations are audited to, DefaultAuditLog when empty
+	Plans    string `yaml:"plans" json:"plans"`         // directory remediation plans are kept in, DefaultPlans when empty
+}
+
+// Store configures where scan runs are kept: one of SQLite, Postgres or
+// DynamoDB. When none is set runs are kept in SQLite at DefaultSQLite.
+type Store struct {
+	SQLite   *SQLite   `yaml:"sqlite" json:"sqlite"`
+	Postgres *Postgres `yaml:"postgres" json:"postgres"`
+	DynamoDB *DynamoDB `yaml:"dynamodb" json:"dynamodb"`
+}
+
+// SQLite is an embedded SQLite database file.
+type SQLite struct {
+	Path string `yaml:"path" json:"path"` // DefaultSQLite when empty
+}
+
+// Postgres is a PostgreSQL database.
+type Postgres struct {
+	URL string `yaml:"url" json:"url"` // e.g. postgres://finops@localhost:5432/unused?sslmode=disable
+}
+
+// DynamoDB is a DynamoDB table scan runs are kept in.
+type DynamoDB struct {
+	Table    string `yaml:"table" json:"table"`
+	Region   string `yaml:"region" json:"region"`     // region of the table; empty uses the default credential chain's
+	Endpoint string `yaml:"endpoint" json:"endpoint"` // e.g. http://localhost:8000 for DynamoDB Local
+}
+
+// Schedule gives the cron expression each detector is scanned on, in UTC,
+// e.g. "0 */6 * * *" or "@daily".
+type Schedule struct {
+	Default   string            `yaml:"default" json:"default"`     // for detectors without their own; empty schedules none
+	Detectors map[string]string `yaml:"detectors" json:"detectors"` // by detector name; "" leaves one unscheduled
+}
+
+// For returns the cron expression detector is scanned on, "" when none.
+func (s Schedule) For(detector string) string {
+	if spec, ok := s.Detectors[detector]; ok {
+		return spec
+	}
+	return s.Default
+}
+
+// AWS selects the accounts and regions to scan.
+type AWS struct {
+	Accounts []string `yaml:"accounts" json:"accounts"` // account IDs or "all"; empty scans with the default credentials
+	Role     string   `yaml:"role" json:"role"`         // role assumed in member accounts
+	Regions  []string `yaml:"regions" json:"regions"`   // regions or "all"; empty means "all"
+}
+
+// GCP selects the projects and locations to scan.
+type GCP struct {
+	Projects []string          `yaml:"projects" json:"projects"` // project IDs, when Parent is not set
+	Parent   string            `yaml:"parent" json:"parent"`     // organization or folder whose projects are scanned
+	Include  []string          `yaml:"include" json:"include"`   // glob patterns of project IDs under Parent
+	Exclude  []string          `yaml:"exclude" json:"exclude"`   // glob patterns of project IDs under Parent
+	Labels   map[string]string `yaml:"labels" json:"labels"`     // labels projects under Parent must carry
+	Regions  []string          `yaml:"regions" json:"regions"`   // regions or "all" for regional detectors; empty means "all"
+	Zones    []string          `yaml:"zones" json:"zones"`       // zones or "all" for zonal detectors; empty means "all"
+}
+
+// Scan is one detector bound to one scope, as the config asks for it.
+type Scan struct {
+	Detector scanner.Detector
+	Scope    scanner.Scope
+	Params   scanner.Parameters
+}
+
+// Default returns the config used when none is given: unused EBS volumes in
+// us-east-1 and unused disks and IPs across the finops-accelerator project.
+func Default() *Config {
+	return &Config{
+		AWS: &AWS{Regions: []string{"us-east-1"}},
+		GCP: &GCP{Projects: []string{"finops-accelerator"}},
+		Detectors: map[string]scanner.Parameters{
+			"aws/ebs":   {},
+			"gcp/disks": {},
+			"gcp/ips":   {},
+		},
+	}
+}
+
+// Select returns a config, without provider sections yet, that runs the
+// detectors of provider p named in a comma separated list, or all of them
+// for "all". It lets the CLIs turn their flags into a config.
+func Select(p scanner.Provider, names string) (*Config, error) {
+	selected, err := scanner.Select(p, names)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{Detectors: map[string]scanner.Parameters{}}
+	for _, d := range selected {
+		cfg.Detectors[d.Name] = scanner.Parameters{}
+	}
+	return cfg, nil
+}
+
+// Load reads and validates the config file at path. Files ending in .json
+// are decoded as JSON, anything else as YAML; unknown fields are errors.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates a config.
+func Parse(data []byte, isJSON bool) (*Config, error) {
+	cfg := &Config{}
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return nil, err
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+var (
+	accountID = regexp.MustCompile(`^[0-9]{12}$`)
+	parentRef = regexp.MustCompile(`^(organizations|folders)/[0-9]+$`)
+)
+
+// Validate reports every problem with the config at once, each prefixed
+// with the field it concerns.
+func (c *Config) Validate() error {
+	var errs []error
+	problem := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.AWS == nil && c.GCP == nil {
+		problem("config", "no provider configured, add an aws or gcp section")
+	}
+	if c.AWS != nil {
+		for i, account := range c.AWS.Accounts {
+			if account != scanner.AllAccounts && !accountID.MatchString(account) {
+				problem(fmt.Sprintf("aws.accounts[%d]", i), "%q is not a 12 digit account ID or %q", account, scanner.AllAccounts)
+			}
+		}
+		checkLocations(problem, "aws.regions", c.AWS.Regions, scanner.AllRegions)
+	}
+	if c.GCP != nil {
+		switch {
+		case c.GCP.Parent == "" && len(c.GCP.Projects) == 0:
+			problem("gcp", "set projects or parent")
+		case c.GCP.Parent != "" && len(c.GCP.Projects) > 0:
+			problem("gcp", "set projects or parent, not both")
+		case c.GCP.Parent != "" && !parentRef.MatchString(c.GCP.Parent):
+			problem("gcp.parent", "%q is not organizations/ID or folders/ID", c.GCP.Parent)
+		}
+		if c.GCP.Parent == "" && (len(c.GCP.Include) > 0 || len(c.GCP.Exclude) > 0 || len(c.GCP.Labels) > 0) {
+			problem("gcp", "include, exclude and labels only apply with parent")
+		}
+		for i, project := range c.GCP.Projects {
+			if project == "" {
+				problem(fmt.Sprintf("gcp.projects[%d]", i), "empty project ID")
+			}
+		}
+		for field, patterns := range map[string][]string{"gcp.include": c.GCP.Include, "gcp.exclude": c.GCP.Exclude} {
+			for i, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					problem(fmt.Sprintf("%s[%d]", field, i), "bad pattern %q", pattern)
+				}
+			}
+		}
+		checkLocations(problem, "gcp.regions", c.GCP.Regions, scanner.AllRegions)
+		checkLocations(problem, "gcp.zones", c.GCP.Zones, scanner.AllZones)
+	}
+
+	if n := countSet(c.Store.SQLite != nil, c.Store.Postgres != nil, c.Store.DynamoDB != nil); n > 1 {
+		problem("store", "set one of sqlite, postgres and dynamodb, not %d", n)
+	}
+	if c.Store.Postgres != nil && c.Store.Postgres.URL == "" {
+		problem("store.postgres.url", "required")
+	}
+	if c.Store.DynamoDB != nil && c.Store.DynamoDB.Table == "" {
+		problem("store.dynamodb.table", "required")
+	}
+
+	if c.Schedule.Default != "" {
+		if _, err := cron.ParseStandard(c.Schedule.Default); err != nil {
+			problem("schedule.default", "%v", err)
+		}
+	}
+	running := map[string]bool{}
+	for _, d := range c.detectors() {
+		running[d.Name] = true
+	}
+	for _, name := range sortedNames(c.Schedule.Detectors) {
+		field := "schedule.detectors." + name
+		if !running[name] {
+			problem(field, "detector is not run by this config")
+		}
+		if spec := c.Schedule.Detectors[name]; spec != "" {
+			if _, err := cron.ParseStandard(spec); err != nil {
+				problem(field, "%v", err)
+			}
+		}
+	}
+
+	for field, tags := range map[string]map[string]string{"tags.include": c.Tags.Include, "tags.exclude": c.Tags.Exclude} {
+		for key := range tags {
+			if key == "" {
+				problem(field, "empty tag key")
+			}
+		}
+	}
+
+	for _, name := range sortedNames(c.Detectors) {
+		field := "detectors." + name
+		d, ok := scanner.Lookup(name)
+		if !ok {
+			problem(field, "unknown detector, see GET /scanners")
+			continue
+		}
+		if !c.hasProvider(d.Provider) {
+			problem(field, "no %s section configured", d.Provider)
+		}
+		params := c.Detectors[name]
+		if params.Threshold < 0 {
+			problem(field+".threshold", "must not be negative")
+		}
+		if params.Days < 0 {
+			problem(field+".days", "must not be negative")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkLocations rejects empty entries and "all" mixed with named locations.
+func checkLocations(problem func(field, format string, args ...any), field string, locations []string, all string) {
+	for i, location := range locations {
+		if location == "" {
+			problem(fmt.Sprintf("%s[%d]", field, i), "empty location")
+		}
+		if location == all && len(locations) > 1 {
+			problem(field, "%q cannot be combined with other locations", all)
+		}
+	}
+}
+
+// Addr returns the listen address of the API server.
+func (c *Config) Addr() string {
+	if c.Server.Addr == "" {
+		return DefaultAddr
+	}
+	return c.Server.Addr
+}
+
+// AuditLog returns the file the API server audits remediations to.
+func (c *Config) AuditLog() string {
+	if c.Server.AuditLog == "" {
+		return DefaultAuditLog
+	}
+	return c.Server.AuditLog
+}
+
+// Plans returns the directory the API server keeps remediation plans in.
+func (c *Config) Plans() string {
+	if c.Server.Plans == "" {
+		return DefaultPlans
+	}
+	return c.Server.Plans
+}
+
+// Scans expands the config into one scan per detector and scope, ordered by
+// detector name.
+func (c *Config) Scans() []Scan {
+	var scans []Scan
+	for _, d := range c.detectors() {
+		params := c.Detectors[d.Name]
+		for _, scope := range c.scopes(d) {
+			scans = append(scans, Scan{Detector: d, Scope: scope, Params: params})
+		}
+	}
+	return scans
+}
+
+// detectors returns the detectors the config runs.
+func (c *Config) detectors() []scanner.Detector {
+	if len(c.Detectors) == 0 {
+		var all []scanner.Detector
+		for _, d := range scanner.All() {
+			if c.hasProvider(d.Provider) {
+				all = append(all, d)
+			}
+		}
+		return all
+	}
+	var selected []scanner.Detector
+	for _, name := range sortedNames(c.Detectors) {
+		if d, ok := scanner.Lookup(name); ok {
+			selected = append(selected, d)
+		}
+	}
+	return selected
+}
+
+// scopes returns the scopes detector d runs in.
+func (c *Config) scopes(d scanner.Detector) []scanner.Scope {
+	var scopes []scanner.Scope
+	switch d.Provider {
+	case scanner.AWS:
+		accounts := orDefault(c.AWS.Accounts, "")
+		regions := orDefault(c.AWS.Regions, scanner.AllRegions)
+		if d.Scope == scanner.Global {
+			regions = []string{scanner.AllRegions}
+		}
+		for _, account := range accounts {
+			for _, region := range regions {
+				scopes = append(scopes, scanner.Scope{Account: account, Role: c.AWS.Role, Region: region, Tags: c.Tags})
+			}
+		}
+	case scanner.GCP:
+		projects := c.GCP.Projects
+		if c.GCP.Parent != "" {
+			projects = []string{""}
+		}
+		locations := []string{""}
+		switch d.Scope {
+		case scanner.Regional:
+			locations = orDefault(c.GCP.Regions, scanner.AllRegions)
+		case scanner.Zonal:
+			locations = orDefault(c.GCP.Zones, scanner.AllZones)
+		}
+		for _, project := range projects {
+			for _, location := range locations {
+				scope := scanner.Scope{
+					Project:  project,
+					Parent:   c.GCP.Parent,
+					Projects: scanner.ProjectFilter{Include: c.GCP.Include, Exclude: c.GCP.Exclude, Labels: c.GCP.Labels},
+					Tags:     c.Tags,
+				}
+				if d.Scope == scanner.Zonal {
+					scope.Zone = location
+				} else {
+					scope.Region = location
+				}
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+func (c *Config) hasProvider(p scanner.Provider) bool {
+	return (p == scanner.AWS && c.AWS != nil) || (p == scanner.GCP && c.GCP != nil)
+}
+
+func countSet(set ...bool) int {
+	n := 0
+	for _, ok := range set {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+func orDefault(values []string, fallback string) []string {
+	if len(values) == 0 {
+		return []string{fallback}
+	}
+	return values
+}
+
+func sortedNames[V any](detectors map[string]V) []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
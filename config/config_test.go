@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	_ "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestExampleConfigLoads(t *testing.T) {
+	cfg, err := Load("../config.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Detectors["aws/lb"]; got != (scanner.Parameters{Threshold: 100, Days: 7}) {
+		t.Errorf("aws/lb = %+v", got)
+	}
+	if cfg.Addr() != ":9090" {
+		t.Errorf("Addr = %q", cfg.Addr())
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	os.WriteFile(path, []byte(`{"gcp": {"projects": ["p1"]}, "detectors": {"gcp/ips": {"days": 3}}}`), 0o644)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Detectors["gcp/ips"].Days != 3 {
+		t.Errorf("detectors = %+v", cfg.Detectors)
+	}
+
+	os.WriteFile(path, []byte(`{"gcp": {"projects": ["p1"], "project": "p2"}}`), 0o644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "project") {
+		t.Errorf("unknown field: err = %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	_, err := Parse([]byte(`
+aws:
+  accounts: ["1234", "all"]
+  regions: ["all", "us-east-1"]
+gcp:
+  parent: projects/finops
+  include: ["[finops"]
+schedule:
+  default: "every hour"
+  detectors: {aws/ebs: "@daily"}
+store:
+  postgres: {}
+  dynamodb: {region: us-east-1}
+detectors:
+  aws/ec2: {threshold: -1}
+  aws/nat: {}
+`), false)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		`aws.accounts[0]: "1234" is not a 12 digit account ID`,
+		`aws.regions: "all" cannot be combined`,
+		`gcp.parent: "projects/finops" is not organizations/ID or folders/ID`,
+		`gcp.include[0]: bad pattern`,
+		`store: set one of sqlite, postgres and dynamodb, not 2`,
+		`schedule.default: `,
+		`schedule.detectors.aws/ebs: detector is not run by this config`,
+		`store.postgres.url: required`,
+		`store.dynamodb.table: required`,
+		`detectors.aws/ec2.threshold: must not be negative`,
+		`detectors.aws/nat: unknown detector`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error is missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateRequiresProviderSection(t *testing.T) {
+	_, err := Parse([]byte("gcp: {projects: [p1]}\ndetectors: {aws/ebs: {}}\n"), false)
+	if err == nil || !strings.Contains(err.Error(), "detectors.aws/ebs: no aws section configured") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := Parse(nil, false); err == nil {
+		t.Error("empty config should not validate")
+	}
+}
+
+func TestScans(t *testing.T) {
+	cfg, err := Parse([]byte(`
+aws:
+  accounts: ["111111111111", "222222222222"]
+  role: Audit
+  regions: [us-east-1, eu-west-1]
+gcp:
+  projects: [p1]
+  zones: [us-central1-a]
+detectors:
+  aws/ec2: {threshold: 2}
+  aws/s3: {}
+  gcp/disks: {}
+  gcp/ips: {}
+`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range cfg.Scans() {
+		got = append(got, s.Detector.Name+" "+s.Scope.Account+s.Scope.Project+" "+s.Scope.Region+s.Scope.Zone)
+	}
+	want := []string{
+		"aws/ec2 111111111111 us-east-1",
+		"aws/ec2 111111111111 eu-west-1",
+		"aws/ec2 222222222222 us-east-1",
+		"aws/ec2 222222222222 eu-west-1",
+		"aws/s3 111111111111 all",
+		"aws/s3 222222222222 all",
+		"gcp/disks p1 us-central1-a",
+		"gcp/ips p1 all",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scans =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if s := cfg.Scans()[0]; s.Params.Threshold != 2 || s.Scope.Role != "Audit" {
+		t.Errorf("first scan = %+v", s)
+	}
+}
+
+func TestScansRunEveryDetectorByDefault(t *testing.T) {
+	cfg, err := Parse([]byte("gcp: {parent: folders/42, labels: {env: prod}}\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scans := cfg.Scans()
+	if len(scans) != len(scanner.ByProvider(scanner.GCP)) {
+		t.Fatalf("scans = %+v", scans)
+	}
+	for _, s := range scans {
+		if s.Scope.Parent != "folders/42" || s.Scope.Projects.Labels["env"] != "prod" {
+			t.Errorf("scope = %+v", s.Scope)
+		}
+	}
+}
+
+func TestScansCarryTagRules(t *testing.T) {
+	cfg, err := Parse([]byte(`
+aws: {regions: [us-east-1]}
+gcp: {projects: [p1]}
+tags:
+  include: {environment: dev}
+  exclude: {"finops:keep": "true"}
+`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := scanner.TagRules{
+		Include: map[string]string{"environment": "dev"},
+		Exclude: map[string]string{"finops:keep": "true"},
+	}
+	for _, s := range cfg.Scans() {
+		if !reflect.DeepEqual(s.Scope.Tags, want) {
+			t.Errorf("%s: Tags = %+v, want %+v", s.Detector.Name, s.Scope.Tags, want)
+		}
+	}
+
+	if _, err := Parse([]byte("aws: {}\ntags: {exclude: {\"\": x}}\n"), false); err == nil || !strings.Contains(err.Error(), "tags.exclude") {
+		t.Errorf("empty key: err = %v", err)
+	}
+}
+
+func TestScheduleFor(t *testing.T) {
+	cfg, err := Parse([]byte(`
+aws: {}
+schedule:
+  default: "0 */6 * * *"
+  detectors: {aws/ec2: "@daily", aws/s3: ""}
+`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for detector, want := range map[string]string{"aws/ebs": "0 */6 * * *", "aws/ec2": "@daily", "aws/s3": ""} {
+		if got := cfg.Schedule.For(detector); got != want {
+			t.Errorf("For(%s) = %q, want %q", detector, got, want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// FromFlags loads the config file at path or, without one, builds a config
+// for the CLIs from their flags: the detectors of provider p named in a
+// comma separated list, with fromFlags filling in the provider section.
+func FromFlags(path string, p scanner.Provider, detectors string, fromFlags func(*Config)) (*Config, error) {
+	if path != "" {
+		return Load(path)
+	}
+	cfg, err := Select(p, detectors)
+	if err != nil {
+		return nil, err
+	}
+	fromFlags(cfg)
+	return cfg, cfg.Validate()
+}
+
+// SplitList splits a comma separated flag value, dropping empty entries.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseTags parses a "key=value,key" flag value into tag or label rules; a
+// bare key matches any value.
+func ParseTags(value string) map[string]string {
+	tags := map[string]string{}
+	for _, item := range SplitList(value) {
+		key, val, _ := strings.Cut(item, "=")
+		tags[key] = val
+	}
+	return tags
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" env=prod, finops:keep ,,team=a=b")
+	want := map[string]string{"env": "prod", "finops:keep": "", "team": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags = %v, want %v", got, want)
+	}
+	if got := SplitList(" , "); got != nil {
+		t.Errorf("SplitList of nothing = %q", got)
+	}
+}
+
+func TestFromFlags(t *testing.T) {
+	cfg, err := FromFlags("", scanner.AWS, "aws/ebs", func(cfg *Config) {
+		cfg.AWS = &AWS{Regions: []string{"eu-west-1"}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Detectors) != 1 || cfg.AWS.Regions[0] != "eu-west-1" {
+		t.Errorf("config = %+v", cfg)
+	}
+	// Flags that leave the config invalid are reported like a bad file.
+	if _, err := FromFlags("", scanner.AWS, "aws/ebs", func(cfg *Config) {}); err == nil {
+		t.Error("config without an aws section: no error")
+	}
+	if _, err := FromFlags("", scanner.AWS, "aws/nat", func(cfg *Config) {}); err == nil {
+		t.Error("unknown detector: no error")
+	}
+}
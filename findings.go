@@ -0,0 +1,141 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// findings returns a page of the findings of the latest stored run of
+// every configured detector, filtered, sorted and paged by the query:
+//
+//	provider, detector, account, project, region, kind  any of the values, repeated or comma separated
+//	tag           key or key=value, repeated; findings must carry every tag
+//	min_age_days  only resources created at least this many days ago
+//	max_age_days  only resources created at most this many days ago
+//	min_cost      estimated monthly cost in USD, at least
+//	max_cost      likewise, at most
+//	sort          -cost (default), cost, -age (oldest first) or age
+//	limit         page size, 100 by default, at most 1000
+//	cursor        next_cursor of the previous page
+//
+// Regions match the zones in them too. Age filters leave out resources
+// whose creation time is unknown. S3 buckets and load balancers carry no
+// tags, so tag filters leave them out, unless tag rules are configured.
+func (a v1API) findings(c *gin.Context) {
+	query := c.Request.URL.Query()
+	bad := paramError{}
+	list := func(name string) []string {
+		var values []string
+		for _, v := range query[name] {
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					values = append(values, s)
+				}
+			}
+		}
+		return values
+	}
+	number := func(name string) *float64 {
+		s := query.Get(name)
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+			bad[name] = "not a non-negative number"
+			return nil
+		}
+		return &n
+	}
+
+	q := store.FindingQuery{
+		Accounts: list("account"),
+		Projects: list("project"),
+		Regions:  list("region"),
+		Kinds:    list("kind"),
+		MinCost:  number("min_cost"),
+		MaxCost:  number("max_cost"),
+		Cursor:   query.Get("cursor"),
+	}
+	q.Detectors = a.findingDetectors(bad, list("provider"), list("detector"))
+	for _, tag := range query["tag"] {
+		key, value, _ := strings.Cut(tag, "=")
+		if key == "" {
+			bad["tag"] = strconv.Quote(tag) + " is not key or key=value"
+			continue
+		}
+		if q.Tags == nil {
+			q.Tags = map[string]string{}
+		}
+		q.Tags[key] = value
+	}
+	now := time.Now()
+	if days := number("min_age_days"); days != nil {
+		q.CreatedBefore = now.Add(-time.Duration(*days * float64(24*time.Hour)))
+	}
+	if days := number("max_age_days"); days != nil {
+		q.CreatedAfter = now.Add(-time.Duration(*days * float64(24*time.Hour)))
+	}
+	var err error
+	if q.Order, err = store.ParseOrder(query.Get("sort")); err != nil {
+		bad["sort"] = "not cost, -cost, age or -age"
+	}
+	if s := query.Get("limit"); s != "" {
+		if q.Limit, err = strconv.Atoi(s); err != nil || q.Limit < 1 || q.Limit > store.MaxPageSize {
+			bad["limit"] = "not a whole number from 1 to " + strconv.Itoa(store.MaxPageSize)
+		}
+	}
+	for name := range query {
+		if !findingParams[name] {
+			bad[name] = "not a parameter of /v1/findings"
+		}
+	}
+	if len(bad) > 0 {
+		abortV1(c, &scanner.Error{Kind: scanner.KindInvalid, Err: bad})
+		return
+	}
+
+	page, err := store.Findings(c.Request.Context(), a.runs, q)
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// findingParams are the query parameters of GET /v1/findings.
+var findingParams = map[string]bool{
+	"provider": true, "detector": true, "account": true, "project": true, "region": true, "kind": true, "tag": true,
+	"min_age_days": true, "max_age_days": true, "min_cost": true, "max_cost": true, "sort": true, "limit": true, "cursor": true,
+}
+
+// findingDetectors returns the configured detectors of any of providers
+// and named in detectors, every one when both are empty.
+func (a v1API) findingDetectors(bad paramError, providers, detectors []string) []string {
+	for _, p := range providers {
+		if p != string(scanner.AWS) && p != string(scanner.GCP) {
+			bad["provider"] = strconv.Quote(p) + " is not aws or gcp"
+		}
+	}
+	for _, name := range detectors {
+		if _, ok := scanner.Lookup(name); !ok {
+			bad["detector"] = "unknown detector " + strconv.Quote(name) + ", see GET /scanners"
+		}
+	}
+	var names []string
+	for _, scans := range store.ByDetector(a.cfg.Scans()) {
+		d := scans[0].Detector
+		if (len(providers) == 0 || slices.Contains(providers, string(d.Provider))) && (len(detectors) == 0 || slices.Contains(detectors, d.Name)) {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
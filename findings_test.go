@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+func TestV1Findings(t *testing.T) {
+	r, runs := newV1(t)
+	run := store.Run{ID: "run-1", Detector: "aws/fake", StartedAt: time.Now(), FinishedAt: time.Now()}
+	for i := range 5 {
+		run.Result.Findings = append(run.Result.Findings, scanner.Finding{
+			ResourceID:  fmt.Sprintf("res-%d", i),
+			Region:      "us-east-1",
+			MonthlyCost: float64(i),
+			CreatedAt:   time.Now().AddDate(0, 0, -10*i),
+			Tags:        map[string]string{"team": []string{"data", "web"}[i%2]},
+		})
+	}
+	if err := runs.PutRun(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+
+	var page store.FindingPage
+	if code := get(t, r, "/v1/findings?provider=aws&tag=team=data&min_age_days=15&limit=1", &page); code != http.StatusOK {
+		t.Fatalf("%d %+v", code, page)
+	}
+	// res-2 and res-4 are data's, 20 and 40 days old; the dearer comes first.
+	if len(page.Findings) != 1 || page.Findings[0].ResourceID != "res-4" || page.Total != 2 || page.NextCursor == "" {
+		t.Fatalf("page = %+v", page)
+	}
+	if f := page.Findings[0]; f.Detector != "aws/fake" || f.Provider != scanner.AWS || f.RunID != "run-1" {
+		t.Errorf("finding = %+v", f)
+	}
+	cursor := page.NextCursor
+	page = store.FindingPage{}
+	get(t, r, "/v1/findings?provider=aws&tag=team=data&min_age_days=15&limit=1&cursor="+cursor, &page)
+	if len(page.Findings) != 1 || page.Findings[0].ResourceID != "res-2" || page.NextCursor != "" {
+		t.Errorf("second page = %+v", page)
+	}
+
+	get(t, r, "/v1/findings?provider=gcp", &page)
+	if len(page.Findings) != 0 || page.Findings == nil {
+		t.Errorf("gcp page = %+v, want no findings", page)
+	}
+
+	var body apiError
+	code := get(t, r, "/v1/findings?provider=azure&sort=name&limit=5000&min_cost=-1&tag==x&size=3", &body)
+	if code != http.StatusBadRequest || len(body.Error.Params) != 6 {
+		t.Errorf("%d %+v, want six bad parameters", code, body)
+	}
+	if code := get(t, r, "/v1/findings?cursor=nonsense", &body); code != http.StatusBadRequest || body.Error.Kind != scanner.KindInvalid {
+		t.Errorf("%d %+v, want a bad cursor rejected", code, body)
+	}
+}
@@ -0,0 +1,84 @@
+package unused_gcp_resources
+
+import (
+	"path"
+	"sort"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// globalLocation is the location recorded for global resources.
+const globalLocation = "global"
+
+// location turns an aggregated list key such as "zones/us-central1-a" or
+// "regions/us-central1" into the bare zone or region name.
+func location(key string) string {
+	return path.Base(key)
+}
+
+// sortedKeys returns the keys of an aggregated list page in order, so
+// findings come out in a stable order.
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scopeWarning reports the warning an aggregated list attached to a location
+// as a skipped entry. Empty locations carry NO_RESULTS_ON_PAGE, which is not
+// a problem and is ignored.
+func scopeWarning(loc string, warning *computepb.Warning) (scanner.Skipped, bool) {
+	if warning == nil || warning.GetCode() == computepb.Warning_NO_RESULTS_ON_PAGE.String() {
+		return scanner.Skipped{}, false
+	}
+	return scanner.Skipped{
+		ResourceID: loc,
+		Region:     loc,
+		Kind:       scanner.KindUnknown,
+		Reason:     warning.GetCode() + ": " + warning.GetMessage(),
+	}, true
+}
+
+// unreachable reports the locations a partial-success aggregated list could
+// not reach as skipped entries.
+func unreachable(locations []string) []scanner.Skipped {
+	var skipped []scanner.Skipped
+	for _, key := range locations {
+		loc := location(key)
+		skipped = append(skipped, scanner.Skipped{
+			ResourceID: loc,
+			Region:     loc,
+			Kind:       scanner.KindUnknown,
+			Reason:     "unreachable",
+		})
+	}
+	return skipped
+}
+
+// locationCounts tallies resources per zone or region while paging through
+// an aggregated list.
+type locationCounts map[string]scanner.Subtotal
+
+func (c locationCounts) add(loc string, unused bool) {
+	sub := c[loc]
+	sub.TotalInstancesCount++
+	if unused {
+		sub.UnusedInstancesCount++
+	}
+	c[loc] = sub
+}
+
+// apply sets the result's counts and per-location subtotals from c.
+func (c locationCounts) apply(r *UnusedResourceMetrics) {
+	for _, sub := range c {
+		r.TotalInstancesCount += sub.TotalInstancesCount
+		r.UnusedInstancesCount += sub.UnusedInstancesCount
+	}
+	if len(c) > 0 {
+		r.Regions = map[string]scanner.Subtotal(c)
+	}
+}
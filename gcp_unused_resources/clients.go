@@ -0,0 +1,169 @@
+package unused_gcp_resources
+
+import (
+	"context"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/api/iterator"
+)
+
+// pageSize is the number of items requested per page from the Compute API.
+const pageSize = 500
+
+// DisksAPI lists persistent disks one page at a time.
+type DisksAPI interface {
+	ListDisks(ctx context.Context, req *computepb.ListDisksRequest) (*computepb.DiskList, error)
+	AggregatedListDisks(ctx context.Context, req *computepb.AggregatedListDisksRequest) (*computepb.DiskAggregatedList, error)
+}
+
+// AddressesAPI lists reserved addresses one page at a time, and looks up and
+// releases single addresses. The Delete methods return once the operation is done.
+type AddressesAPI interface {
+	ListAddresses(ctx context.Context, req *computepb.ListAddressesRequest) (*computepb.AddressList, error)
+	AggregatedListAddresses(ctx context.Context, req *computepb.AggregatedListAddressesRequest) (*computepb.AddressAggregatedList, error)
+	GetAddress(ctx context.Context, req *computepb.GetAddressRequest) (*computepb.Address, error)
+	GetGlobalAddress(ctx context.Context, req *computepb.GetGlobalAddressRequest) (*computepb.Address, error)
+	DeleteAddress(ctx context.Context, req *computepb.DeleteAddressRequest) error
+	DeleteGlobalAddress(ctx context.Context, req *computepb.DeleteGlobalAddressRequest) error
+}
+
+// Option overrides how a detector reaches GCP. Clients that are not
+// provided are built from Application Default Credentials.
+type Option func(*options)
+
+type options struct {
+	disks     DisksAPI
+	addresses AddressesAPI
+	projects  ProjectsAPI
+	folders   FoldersAPI
+	catalog   CatalogAPI
+
+	labels scanner.TagRules
+}
+
+// WithDisksClient uses client to list disks.
+func WithDisksClient(client DisksAPI) Option { return func(o *options) { o.disks = client } }
+
+// WithTagRules leaves out resources whose labels the rules exclude.
+func WithTagRules(rules scanner.TagRules) Option { return func(o *options) { o.labels = rules } }
+
+// WithAddressesClient uses client to list addresses.
+func WithAddressesClient(client AddressesAPI) Option {
+	return func(o *options) { o.addresses = client }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// disksClient returns the disks client and a function releasing it.
+func (o *options) disksClient(ctx context.Context) (DisksAPI, func(), error) {
+	if o.disks != nil {
+		return o.disks, func() {}, nil
+	}
+	client, err := compute.NewDisksRESTClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restDisks{client}, func() { client.Close() }, nil
+}
+
+// addressesClient returns the addresses client and a function releasing it.
+func (o *options) addressesClient(ctx context.Context) (AddressesAPI, func(), error) {
+	if o.addresses != nil {
+		return o.addresses, func() {}, nil
+	}
+	regional, err := compute.NewAddressesRESTClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	global, err := compute.NewGlobalAddressesRESTClient(ctx)
+	if err != nil {
+		regional.Close()
+		return nil, nil, err
+	}
+	return restAddresses{regional, global}, func() { regional.Close(); global.Close() }, nil
+}
+
+// restDisks adapts the generated REST client to DisksAPI.
+type restDisks struct{ client *compute.DisksClient }
+
+func (r restDisks) ListDisks(ctx context.Context, req *computepb.ListDisksRequest) (*computepb.DiskList, error) {
+	var items []*computepb.Disk
+	next, err := iterator.NewPager(r.client.List(ctx, req), pageSize, req.GetPageToken()).NextPage(&items)
+	if err != nil {
+		return nil, err
+	}
+	return &computepb.DiskList{Items: items, NextPageToken: &next}, nil
+}
+
+func (r restDisks) AggregatedListDisks(ctx context.Context, req *computepb.AggregatedListDisksRequest) (*computepb.DiskAggregatedList, error) {
+	var pairs []compute.DisksScopedListPair
+	next, err := iterator.NewPager(r.client.AggregatedList(ctx, req), pageSize, req.GetPageToken()).NextPage(&pairs)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*computepb.DisksScopedList, len(pairs))
+	for _, pair := range pairs {
+		items[pair.Key] = pair.Value
+	}
+	return &computepb.DiskAggregatedList{Items: items, NextPageToken: &next}, nil
+}
+
+// restAddresses adapts the generated regional and global REST clients to AddressesAPI.
+type restAddresses struct {
+	client *compute.AddressesClient
+	global *compute.GlobalAddressesClient
+}
+
+func (r restAddresses) ListAddresses(ctx context.Context, req *computepb.ListAddressesRequest) (*computepb.AddressList, error) {
+	var items []*computepb.Address
+	next, err := iterator.NewPager(r.client.List(ctx, req), pageSize, req.GetPageToken()).NextPage(&items)
+	if err != nil {
+		return nil, err
+	}
+	return &computepb.AddressList{Items: items, NextPageToken: &next}, nil
+}
+
+func (r restAddresses) AggregatedListAddresses(ctx context.Context, req *computepb.AggregatedListAddressesRequest) (*computepb.AddressAggregatedList, error) {
+	var pairs []compute.AddressesScopedListPair
+	next, err := iterator.NewPager(r.client.AggregatedList(ctx, req), pageSize, req.GetPageToken()).NextPage(&pairs)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*computepb.AddressesScopedList, len(pairs))
+	for _, pair := range pairs {
+		items[pair.Key] = pair.Value
+	}
+	return &computepb.AddressAggregatedList{Items: items, NextPageToken: &next}, nil
+}
+
+func (r restAddresses) GetAddress(ctx context.Context, req *computepb.GetAddressRequest) (*computepb.Address, error) {
+	return r.client.Get(ctx, req)
+}
+
+func (r restAddresses) GetGlobalAddress(ctx context.Context, req *computepb.GetGlobalAddressRequest) (*computepb.Address, error) {
+	return r.global.Get(ctx, req)
+}
+
+func (r restAddresses) DeleteAddress(ctx context.Context, req *computepb.DeleteAddressRequest) error {
+	op, err := r.client.Delete(ctx, req)
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
+
+func (r restAddresses) DeleteGlobalAddress(ctx context.Context, req *computepb.DeleteGlobalAddressRequest) error {
+	op, err := r.global.Delete(ctx, req)
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
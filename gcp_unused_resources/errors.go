@@ -0,0 +1,63 @@
+package unused_gcp_resources
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// wrapError classifies a Compute API error into a *scanner.Error.
+func wrapError(op, resource string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *scanner.Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	return &scanner.Error{Kind: classify(err), Op: op, Resource: resource, Err: err}
+}
+
+// classify maps a googleapi or gRPC error to a scanner.Kind.
+func classify(err error) scanner.Kind {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+				return scanner.KindThrottling
+			}
+		}
+		switch apiErr.Code {
+		case http.StatusUnauthorized:
+			return scanner.KindAuth
+		case http.StatusForbidden:
+			return scanner.KindPermission
+		case http.StatusNotFound:
+			return scanner.KindNotFound
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return scanner.KindConflict
+		case http.StatusTooManyRequests:
+			return scanner.KindThrottling
+		}
+		return scanner.KindUnknown
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unauthenticated:
+			return scanner.KindAuth
+		case codes.PermissionDenied:
+			return scanner.KindPermission
+		case codes.NotFound:
+			return scanner.KindNotFound
+		case codes.FailedPrecondition, codes.AlreadyExists:
+			return scanner.KindConflict
+		case codes.ResourceExhausted:
+			return scanner.KindThrottling
+		}
+	}
+	return scanner.KindUnknown
+}
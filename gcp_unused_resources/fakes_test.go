@@ -0,0 +1,168 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"cloud.google.com/go/billing/apiv1/billingpb"
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/protobuf/proto"
+)
+
+// pageOf returns the page of items starting at token and the token of the
+// next page ("" on the last page).
+func pageOf[T any](items []T, token string, size int) ([]T, string) {
+	start := 0
+	if token != "" {
+		start, _ = strconv.Atoi(token)
+	}
+	if size <= 0 {
+		size = len(items)
+	}
+	end := start + size
+	if end >= len(items) {
+		return items[start:], ""
+	}
+	return items[start:end], strconv.Itoa(end)
+}
+
+// fakeDisks serves disks from memory, keyed by zone. Aggregated list calls
+// return the pages in aggregated one after another.
+type fakeDisks struct {
+	pageSize   int
+	disks      map[string][]*computepb.Disk
+	aggregated []*computepb.DiskAggregatedList
+	err        error
+	requests   []*computepb.ListDisksRequest
+}
+
+func (f *fakeDisks) ListDisks(ctx context.Context, req *computepb.ListDisksRequest) (*computepb.DiskList, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.disks[req.GetZone()], req.GetPageToken(), f.pageSize)
+	return &computepb.DiskList{Items: items, NextPageToken: &next}, nil
+}
+
+func (f *fakeDisks) AggregatedListDisks(ctx context.Context, req *computepb.AggregatedListDisksRequest) (*computepb.DiskAggregatedList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	pages, next := pageOf(f.aggregated, req.GetPageToken(), 1)
+	if len(pages) == 0 {
+		return &computepb.DiskAggregatedList{}, nil
+	}
+	page := proto.Clone(pages[0]).(*computepb.DiskAggregatedList)
+	page.NextPageToken = &next
+	return page, nil
+}
+
+// fakeAddresses serves addresses from memory, keyed by region. Aggregated
+// list calls return the pages in aggregated one after another, which like
+// the real API carry global addresses under the "global" scope; Get and
+// Delete serve global addresses from the "global" key.
+type fakeAddresses struct {
+	pageSize   int
+	addresses  map[string][]*computepb.Address
+	aggregated []*computepb.AddressAggregatedList
+	err        error
+	deleteErr  error    // returned by the Delete methods
+	released   []string // "location/name" of every address released
+}
+
+func (f *fakeAddresses) ListAddresses(ctx context.Context, req *computepb.ListAddressesRequest) (*computepb.AddressList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	items, next := pageOf(f.addresses[req.GetRegion()], req.GetPageToken(), f.pageSize)
+	return &computepb.AddressList{Items: items, NextPageToken: &next}, nil
+}
+
+func (f *fakeAddresses) AggregatedListAddresses(ctx context.Context, req *computepb.AggregatedListAddressesRequest) (*computepb.AddressAggregatedList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	pages, next := pageOf(f.aggregated, req.GetPageToken(), 1)
+	if len(pages) == 0 {
+		return &computepb.AddressAggregatedList{}, nil
+	}
+	page := proto.Clone(pages[0]).(*computepb.AddressAggregatedList)
+	page.NextPageToken = &next
+	return page, nil
+}
+
+// get finds an address by location and name the way the Get methods do.
+func (f *fakeAddresses) get(location, name string) (*computepb.Address, error) {
+	for _, a := range f.addresses[location] {
+		if a.GetName() == name {
+			return a, nil
+		}
+	}
+	return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "The resource '" + name + "' was not found"}
+}
+
+func (f *fakeAddresses) GetAddress(ctx context.Context, req *computepb.GetAddressRequest) (*computepb.Address, error) {
+	return f.get(req.GetRegion(), req.GetAddress())
+}
+
+func (f *fakeAddresses) GetGlobalAddress(ctx context.Context, req *computepb.GetGlobalAddressRequest) (*computepb.Address, error) {
+	return f.get(globalLocation, req.GetAddress())
+}
+
+func (f *fakeAddresses) DeleteAddress(ctx context.Context, req *computepb.DeleteAddressRequest) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.released = append(f.released, req.GetRegion()+"/"+req.GetAddress())
+	return nil
+}
+
+func (f *fakeAddresses) DeleteGlobalAddress(ctx context.Context, req *computepb.DeleteGlobalAddressRequest) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.released = append(f.released, globalLocation+"/"+req.GetAddress())
+	return nil
+}
+
+func disk(name string, users ...string) *computepb.Disk {
+	return &computepb.Disk{Name: &name, Users: users}
+}
+
+func address(name string, users ...string) *computepb.Address {
+	return &computepb.Address{Name: &name, Users: users}
+}
+
+// fakeResourceManager serves projects and folders from memory, keyed by parent.
+type fakeResourceManager struct {
+	projects map[string][]*resourcemanagerpb.Project
+	folders  map[string][]*resourcemanagerpb.Folder
+}
+
+func (f *fakeResourceManager) ListProjects(ctx context.Context, req *resourcemanagerpb.ListProjectsRequest) (*resourcemanagerpb.ListProjectsResponse, error) {
+	items, next := pageOf(f.projects[req.GetParent()], req.GetPageToken(), 1)
+	return &resourcemanagerpb.ListProjectsResponse{Projects: items, NextPageToken: next}, nil
+}
+
+func (f *fakeResourceManager) ListFolders(ctx context.Context, req *resourcemanagerpb.ListFoldersRequest) (*resourcemanagerpb.ListFoldersResponse, error) {
+	items, next := pageOf(f.folders[req.GetParent()], req.GetPageToken(), 1)
+	return &resourcemanagerpb.ListFoldersResponse{Folders: items, NextPageToken: next}, nil
+}
+
+func project(id string, labels map[string]string) *resourcemanagerpb.Project {
+	return &resourcemanagerpb.Project{ProjectId: id, DisplayName: id, State: resourcemanagerpb.Project_ACTIVE, Labels: labels}
+}
+
+// fakeCatalog serves Cloud Billing SKUs from memory.
+type fakeCatalog struct {
+	skus []*billingpb.Sku
+}
+
+func (f *fakeCatalog) ListSkus(ctx context.Context, req *billingpb.ListSkusRequest) (*billingpb.ListSkusResponse, error) {
+	items, next := pageOf(f.skus, req.GetPageToken(), 1)
+	return &billingpb.ListSkusResponse{Skus: items, NextPageToken: next}, nil
+}
@@ -0,0 +1,137 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"strings"
+
+	billing "cloud.google.com/go/billing/apiv1"
+	"cloud.google.com/go/billing/apiv1/billingpb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"google.golang.org/api/iterator"
+)
+
+// computeEngineService is the Cloud Billing Catalog name of Compute Engine.
+const computeEngineService = "services/6F81-5844-456A"
+
+// CatalogAPI lists Cloud Billing Catalog SKUs one page at a time.
+type CatalogAPI interface {
+	ListSkus(ctx context.Context, req *billingpb.ListSkusRequest) (*billingpb.ListSkusResponse, error)
+}
+
+// WithCatalogClient uses client to query the Cloud Billing Catalog.
+func WithCatalogClient(client CatalogAPI) Option { return func(o *options) { o.catalog = client } }
+
+// skuPrefixes maps the description prefix of on-demand Compute Engine SKUs
+// to the catalog service and SKU they price. Regional persistent disks have
+// their own "Regional ..." SKUs, which are not matched.
+var skuPrefixes = []struct {
+	prefix  string
+	service string
+	sku     string
+}{
+	{"Storage PD Capacity", "pd", "pd-standard"},
+	{"Balanced PD Capacity", "pd", "pd-balanced"},
+	{"SSD backed PD Capacity", "pd", "pd-ssd"},
+	{"Extreme PD Capacity", "pd", "pd-extreme"},
+	{"Static Ip Charge", "ip", "external"},
+}
+
+// catalogUnits maps Cloud Billing usage units to the catalog's.
+var catalogUnits = map[string]string{
+	"GiBy.mo": pricing.UnitGBMonth,
+	"h":       pricing.UnitHour,
+}
+
+// BillingCatalog fetches on-demand list prices for persistent disks and
+// static IPs from the Cloud Billing Catalog API, one price per service
+// region, in the form the pricing catalog stores them.
+func BillingCatalog(ctx context.Context, opts ...Option) ([]pricing.Price, error) {
+	client, closeClient, err := newOptions(opts).catalogClient(ctx)
+	if err != nil {
+		return nil, wrapError("NewCloudCatalogRESTClient", computeEngineService, err)
+	}
+	defer closeClient()
+
+	var prices []pricing.Price
+	req := &billingpb.ListSkusRequest{Parent: computeEngineService, CurrencyCode: "USD"}
+	for {
+		page, err := client.ListSkus(ctx, req)
+		if err != nil {
+			return nil, wrapError("skus.list", computeEngineService, err)
+		}
+		for _, sku := range page.GetSkus() {
+			prices = append(prices, skuPrices(sku)...)
+		}
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.GetNextPageToken()
+	}
+	return prices, nil
+}
+
+// skuPrices returns the catalog prices of a Compute Engine SKU, or nil when
+// the SKU is not one the detectors' findings are priced with.
+func skuPrices(sku *billingpb.Sku) []pricing.Price {
+	if sku.GetCategory().GetUsageType() != "OnDemand" || len(sku.GetPricingInfo()) == 0 {
+		return nil
+	}
+	var service, name string
+	for _, p := range skuPrefixes {
+		if strings.HasPrefix(sku.GetDescription(), p.prefix) {
+			service, name = p.service, p.sku
+			break
+		}
+	}
+	expr := sku.GetPricingInfo()[0].GetPricingExpression()
+	unit, ok := catalogUnits[expr.GetUsageUnit()]
+	if service == "" || !ok {
+		return nil
+	}
+
+	// Free tiers come first; the last tier is what sustained usage costs.
+	rates := expr.GetTieredRates()
+	if len(rates) == 0 {
+		return nil
+	}
+	money := rates[len(rates)-1].GetUnitPrice()
+	usd := float64(money.GetUnits()) + float64(money.GetNanos())/1e9
+
+	var prices []pricing.Price
+	for _, region := range sku.GetServiceRegions() {
+		prices = append(prices, pricing.Price{
+			Provider: scanner.GCP,
+			Service:  service,
+			Region:   region,
+			SKU:      name,
+			Unit:     unit,
+			USD:      usd,
+		})
+	}
+	return prices
+}
+
+// catalogClient returns the Cloud Billing Catalog client and a function releasing it.
+func (o *options) catalogClient(ctx context.Context) (CatalogAPI, func(), error) {
+	if o.catalog != nil {
+		return o.catalog, func() {}, nil
+	}
+	client, err := billing.NewCloudCatalogRESTClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restCatalog{client}, func() { client.Close() }, nil
+}
+
+// restCatalog adapts the generated REST client to CatalogAPI.
+type restCatalog struct{ client *billing.CloudCatalogClient }
+
+func (r restCatalog) ListSkus(ctx context.Context, req *billingpb.ListSkusRequest) (*billingpb.ListSkusResponse, error) {
+	var items []*billingpb.Sku
+	next, err := iterator.NewPager(r.client.ListSkus(ctx, req), pageSize, req.GetPageToken()).NextPage(&items)
+	if err != nil {
+		return nil, err
+	}
+	return &billingpb.ListSkusResponse{Skus: items, NextPageToken: next}, nil
+}
@@ -0,0 +1,57 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/billing/apiv1/billingpb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+// sku builds an on-demand Compute Engine SKU with one price per tier, in nanos.
+func sku(description, unit string, regions []string, tierNanos ...int32) *billingpb.Sku {
+	var rates []*billingpb.PricingExpression_TierRate
+	for i, nanos := range tierNanos {
+		rates = append(rates, &billingpb.PricingExpression_TierRate{
+			StartUsageAmount: float64(i),
+			UnitPrice:        &money.Money{CurrencyCode: "USD", Nanos: nanos},
+		})
+	}
+	return &billingpb.Sku{
+		Description:    description,
+		Category:       &billingpb.Category{UsageType: "OnDemand"},
+		ServiceRegions: regions,
+		PricingInfo: []*billingpb.PricingInfo{{
+			PricingExpression: &billingpb.PricingExpression{UsageUnit: unit, TieredRates: rates},
+		}},
+	}
+}
+
+func TestBillingCatalog(t *testing.T) {
+	preemptible := sku("Preemptible SSD backed PD Capacity", "GiBy.mo", []string{"us-central1"}, 170_000_000)
+	preemptible.Category.UsageType = "Preemptible"
+	client := &fakeCatalog{skus: []*billingpb.Sku{
+		sku("Balanced PD Capacity in Belgium", "GiBy.mo", []string{"europe-west1"}, 110_000_000),
+		sku("Regional Balanced PD Capacity", "GiBy.mo", []string{"us-central1"}, 200_000_000),
+		sku("Static Ip Charge", "h", []string{"us-central1", "us-east1"}, 0, 10_000_000),
+		sku("N1 Predefined Instance Core", "h", []string{"us-central1"}, 31_611_000),
+		preemptible,
+	}}
+
+	got, err := BillingCatalog(context.Background(), WithCatalogClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []pricing.Price{
+		{Provider: scanner.GCP, Service: "pd", Region: "europe-west1", SKU: "pd-balanced", Unit: pricing.UnitGBMonth, USD: 0.11},
+		{Provider: scanner.GCP, Service: "ip", Region: "us-central1", SKU: "external", Unit: pricing.UnitHour, USD: 0.01},
+		{Provider: scanner.GCP, Service: "ip", Region: "us-east1", SKU: "external", Unit: pricing.UnitHour, USD: 0.01},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prices = %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package unused_gcp_resources
+
+import (
+	"context"
+
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/api/iterator"
+)
+
+// DefaultProjectWorkers bounds how many projects are scanned at once.
+const DefaultProjectWorkers = 4
+
+// Project is a GCP project to scan.
+type Project struct {
+	ID     string            // project ID, e.g. "finops-accelerator"
+	Name   string            // display name
+	Labels map[string]string // project labels
+}
+
+// ProjectsAPI lists the projects directly beneath an organization or folder, one page at a time.
+type ProjectsAPI interface {
+	ListProjects(ctx context.Context, req *resourcemanagerpb.ListProjectsRequest) (*resourcemanagerpb.ListProjectsResponse, error)
+}
+
+// FoldersAPI lists the folders directly beneath an organization or folder, one page at a time.
+type FoldersAPI interface {
+	ListFolders(ctx context.Context, req *resourcemanagerpb.ListFoldersRequest) (*resourcemanagerpb.ListFoldersResponse, error)
+}
+
+// WithProjectsClient uses client to list projects.
+func WithProjectsClient(client ProjectsAPI) Option { return func(o *options) { o.projects = client } }
+
+// WithFoldersClient uses client to list folders.
+func WithFoldersClient(client FoldersAPI) Option { return func(o *options) { o.folders = client } }
+
+// ProjectScanFunc scans one project.
+type ProjectScanFunc func(ctx context.Context, projectID string) (UnusedResourceMetrics, error)
+
+// ListProjects returns the active projects anywhere beneath parent, an
+// organization ("organizations/123") or folder ("folders/456"), that pass
+// filter. Nested folders are walked depth first.
+func ListProjects(ctx context.Context, parent string, filter scanner.ProjectFilter, opts ...Option) ([]Project, error) {
+	o := newOptions(opts)
+	projects, closeProjects, err := o.projectsClient(ctx)
+	if err != nil {
+		return nil, wrapError("NewProjectsRESTClient", parent, err)
+	}
+	defer closeProjects()
+	folders, closeFolders, err := o.foldersClient(ctx)
+	if err != nil {
+		return nil, wrapError("NewFoldersRESTClient", parent, err)
+	}
+	defer closeFolders()
+
+	var found []Project
+	pending := []string{parent}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		req := &resourcemanagerpb.ListProjectsRequest{Parent: current}
+		for {
+			page, err := projects.ListProjects(ctx, req)
+			if err != nil {
+				return nil, wrapError("projects.list", current, err)
+			}
+			for _, p := range page.GetProjects() {
+				if p.GetState() != resourcemanagerpb.Project_ACTIVE || !filter.Match(p.GetProjectId(), p.GetLabels()) {
+					continue
+				}
+				found = append(found, Project{ID: p.GetProjectId(), Name: p.GetDisplayName(), Labels: p.GetLabels()})
+			}
+			if page.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = page.GetNextPageToken()
+		}
+
+		folderReq := &resourcemanagerpb.ListFoldersRequest{Parent: current}
+		for {
+			page, err := folders.ListFolders(ctx, folderReq)
+			if err != nil {
+				return nil, wrapError("folders.list", current, err)
+			}
+			for _, f := range page.GetFolders() {
+				if f.GetState() == resourcemanagerpb.Folder_ACTIVE {
+					pending = append(pending, f.GetName())
+				}
+			}
+			if page.GetNextPageToken() == "" {
+				break
+			}
+			folderReq.PageToken = page.GetNextPageToken()
+		}
+	}
+	return found, nil
+}
+
+// ScanProjects runs scan for every project using at most 'workers'
+// goroutines and merges the results with the project stamped on every
+// finding and per-project subtotals; the merged counts are the org-wide
+// rollup. Projects that fail are reported in Skipped; ScanProjects only
+// returns an error when the context is cancelled or every project fails.
+func ScanProjects(ctx context.Context, projects []Project, workers int, scan ProjectScanFunc) (UnusedResourceMetrics, error) {
+	if workers <= 0 {
+		workers = DefaultProjectWorkers
+	}
+	results, errs := scanner.FanOut(ctx, len(projects), workers, func(ctx context.Context, i int) (UnusedResourceMetrics, error) {
+		return scan(ctx, projects[i].ID)
+	})
+
+	if err := ctx.Err(); err != nil {
+		return UnusedResourceMetrics{}, wrapError("ScanProjects", "", err)
+	}
+
+	merged := UnusedResourceMetrics{}
+	failed := 0
+	var firstErr error
+	for i, project := range projects {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			skipped := scanner.Skip(project.ID, errs[i])
+			skipped.Project = project.ID
+			merged.Skipped = append(merged.Skipped, skipped)
+			continue
+		}
+		results[i].InProject(project.ID)
+		merged.Merge(results[i])
+	}
+	if len(projects) > 0 && failed == len(projects) {
+		return UnusedResourceMetrics{}, firstErr
+	}
+	return merged, nil
+}
+
+// projectsClient returns the projects client and a function releasing it.
+func (o *options) projectsClient(ctx context.Context) (ProjectsAPI, func(), error) {
+	if o.projects != nil {
+		return o.projects, func() {}, nil
+	}
+	client, err := resourcemanager.NewProjectsRESTClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restProjects{client}, func() { client.Close() }, nil
+}
+
+// foldersClient returns the folders client and a function releasing it.
+func (o *options) foldersClient(ctx context.Context) (FoldersAPI, func(), error) {
+	if o.folders != nil {
+		return o.folders, func() {}, nil
+	}
+	client, err := resourcemanager.NewFoldersRESTClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restFolders{client}, func() { client.Close() }, nil
+}
+
+// restProjects adapts the generated REST client to ProjectsAPI.
+type restProjects struct {
+	client *resourcemanager.ProjectsClient
+}
+
+func (r restProjects) ListProjects(ctx context.Context, req *resourcemanagerpb.ListProjectsRequest) (*resourcemanagerpb.ListProjectsResponse, error) {
+	var items []*resourcemanagerpb.Project
+	next, err := iterator.NewPager(r.client.ListProjects(ctx, req), pageSize, req.GetPageToken()).NextPage(&items)
+	if err != nil {
+		return nil, err
+	}
+	return &resourcemanagerpb.ListProjectsResponse{Projects: items, NextPageToken: next}, nil
+}
+
+// restFolders adapts the generated REST client to FoldersAPI.
+type restFolders struct {
+	client *resourcemanager.FoldersClient
+}
+
+func (r restFolders) ListFolders(ctx context.Context, req *resourcemanagerpb.ListFoldersRequest) (*resourcemanagerpb.ListFoldersResponse, error) {
+	var items []*resourcemanagerpb.Folder
+	next, err := iterator.NewPager(r.client.ListFolders(ctx, req), pageSize, req.GetPageToken()).NextPage(&items)
+	if err != nil {
+		return nil, err
+	}
+	return &resourcemanagerpb.ListFoldersResponse{Folders: items, NextPageToken: next}, nil
+}
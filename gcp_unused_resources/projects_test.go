@@ -0,0 +1,126 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestListProjectsWalksFolders(t *testing.T) {
+	deleted := project("finops-old", nil)
+	deleted.State = resourcemanagerpb.Project_DELETE_REQUESTED
+	rm := &fakeResourceManager{
+		projects: map[string][]*resourcemanagerpb.Project{
+			"organizations/1": {project("finops-prod", map[string]string{"env": "prod"}), deleted},
+			"folders/10":      {project("finops-dev", map[string]string{"env": "dev"}), project("sandbox-1", nil)},
+			"folders/11":      {project("finops-stage", map[string]string{"env": "prod"})},
+		},
+		folders: map[string][]*resourcemanagerpb.Folder{
+			"organizations/1": {{Name: "folders/10", State: resourcemanagerpb.Folder_ACTIVE}},
+			"folders/10":      {{Name: "folders/11", State: resourcemanagerpb.Folder_ACTIVE}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter scanner.ProjectFilter
+		want   []string
+	}{
+		{"all", scanner.ProjectFilter{}, []string{"finops-prod", "finops-dev", "sandbox-1", "finops-stage"}},
+		{"patterns", scanner.ProjectFilter{Include: []string{"finops-*"}, Exclude: []string{"*-dev"}}, []string{"finops-prod", "finops-stage"}},
+		{"labels", scanner.ProjectFilter{Labels: map[string]string{"env": "prod"}}, []string{"finops-prod", "finops-stage"}},
+	}
+	for _, tt := range tests {
+		projects, err := ListProjects(context.Background(), "organizations/1", tt.filter, WithProjectsClient(rm), WithFoldersClient(rm))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range projects {
+			got = append(got, p.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: projects = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanProjectsRollsUp(t *testing.T) {
+	projects := []Project{{ID: "finops-prod"}, {ID: "finops-dev"}, {ID: "finops-denied"}}
+	scan := func(ctx context.Context, projectID string) (UnusedResourceMetrics, error) {
+		switch projectID {
+		case "finops-prod":
+			return UnusedResourceMetrics{ResourceIDs: []string{"pd-1"}, TotalInstancesCount: 3, UnusedInstancesCount: 1, Findings: []scanner.Finding{{ResourceID: "pd-1"}}}, nil
+		case "finops-dev":
+			return UnusedResourceMetrics{ResourceIDs: []string{"pd-2", "pd-3"}, TotalInstancesCount: 2, UnusedInstancesCount: 2, Findings: []scanner.Finding{{ResourceID: "pd-2"}, {ResourceID: "pd-3"}}}, nil
+		default:
+			return UnusedResourceMetrics{}, &scanner.Error{Kind: scanner.KindPermission, Op: "disks.aggregatedList"}
+		}
+	}
+
+	got, err := ScanProjects(context.Background(), projects, 2, scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.TotalInstancesCount != 5 || got.UnusedInstancesCount != 3 {
+		t.Errorf("counts = %d/%d, want 3/5", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	wantProjects := map[string]scanner.Subtotal{
+		"finops-prod": {TotalInstancesCount: 3, UnusedInstancesCount: 1},
+		"finops-dev":  {TotalInstancesCount: 2, UnusedInstancesCount: 2},
+	}
+	if !reflect.DeepEqual(got.Projects, wantProjects) {
+		t.Errorf("Projects = %v, want %v", got.Projects, wantProjects)
+	}
+	if got.Findings[2].Project != "finops-dev" {
+		t.Errorf("finding project = %q, want finops-dev", got.Findings[2].Project)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].Project != "finops-denied" || got.Skipped[0].Kind != scanner.KindPermission {
+		t.Errorf("Skipped = %+v", got.Skipped)
+	}
+}
+
+func TestScanProjectsFailsWhenEveryProjectFails(t *testing.T) {
+	denied := &scanner.Error{Kind: scanner.KindPermission}
+	_, err := ScanProjects(context.Background(), []Project{{ID: "a"}, {ID: "b"}}, 0, func(ctx context.Context, projectID string) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{}, denied
+	})
+	if !errors.Is(err, scanner.ErrPermission) {
+		t.Errorf("err = %v, want a permission error", err)
+	}
+}
+
+func TestProjectScopedStampsASingleProject(t *testing.T) {
+	scan := projectScoped(func(ctx context.Context, projectID string, scope scanner.Scope) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{ResourceIDs: []string{"ip-1"}, TotalInstancesCount: 1, UnusedInstancesCount: 1, Findings: []scanner.Finding{{ResourceID: "ip-1"}}}, nil
+	})
+
+	got, err := scan(context.Background(), scanner.Scope{Project: "finops-prod"}, scanner.Parameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Findings[0].Project != "finops-prod" {
+		t.Errorf("finding project = %q, want finops-prod", got.Findings[0].Project)
+	}
+	if got.Projects["finops-prod"] != (scanner.Subtotal{TotalInstancesCount: 1, UnusedInstancesCount: 1}) {
+		t.Errorf("Projects = %v", got.Projects)
+	}
+}
+
+func TestScanProjectsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ScanProjects(ctx, []Project{{ID: "a"}}, 0, func(ctx context.Context, projectID string) (UnusedResourceMetrics, error) {
+		return UnusedResourceMetrics{}, nil
+	})
+	var e *scanner.Error
+	if !errors.As(err, &e) || e.Op != "ScanProjects" || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %#v, want a scanner.Error from ScanProjects wrapping context.Canceled", err)
+	}
+}
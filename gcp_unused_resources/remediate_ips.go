@@ -0,0 +1,150 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// auditReleaseAddress is the Action of audit records written before an
+// address is released.
+const auditReleaseAddress = "release-address"
+
+// AddressAction is the plan for, and then the outcome of, releasing one
+// reserved address.
+type AddressAction struct {
+	Project     string            `json:"project"`
+	Location    string            `json:"location"` // region, or "global"
+	Name        string            `json:"name"`
+	Address     string            `json:"address"` // the reserved IP
+	AddressType string            `json:"address_type"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Released    bool              `json:"released"`
+	Error       string            `json:"error,omitempty"` // why the address was kept
+}
+
+// AuditRecord is one line of the audit log, written before a resource is
+// changed so it can be recreated from the log alone.
+type AuditRecord struct {
+	Time        time.Time         `json:"time"`
+	Action      string            `json:"action"`
+	Project     string            `json:"project"`
+	Location    string            `json:"location"`
+	Name        string            `json:"name"`
+	Address     string            `json:"address,omitempty"`
+	AddressType string            `json:"address_type,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ParseAddressRef splits "location/name", the location and name of an
+// unused IP finding, e.g. "us-central1/ip-1" or "global/ip-2".
+func ParseAddressRef(ref string) (location, name string, err error) {
+	location, name, ok := strings.Cut(ref, "/")
+	if !ok || location == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("address %q is not location/name, e.g. us-central1/ip-1 or global/ip-1", ref)
+	}
+	return location, name, nil
+}
+
+// ReleaseAddresses releases the reserved addresses named by refs, each
+// "location/name" as accepted by ParseAddressRef. It always plans first: every
+// address must exist and have no users, otherwise nothing is released. With
+// dryRun set it returns the plan without changing anything.
+//
+// Before each release a JSON AuditRecord with the IP and labels is written to
+// audit; an address whose record cannot be written is not released. A failed
+// release is recorded on its action and the returned error joins every failure.
+func ReleaseAddresses(ctx context.Context, projectID string, refs []string, dryRun bool, audit io.Writer, opts ...Option) ([]AddressAction, error) {
+	if !dryRun && audit == nil {
+		return nil, errors.New("an audit log is required to release addresses")
+	}
+	client, closeClient, err := newOptions(opts).addressesClient(ctx)
+	if err != nil {
+		return nil, wrapError("NewAddressesRESTClient", projectID, err)
+	}
+	defer closeClient()
+
+	actions, err := planAddressRelease(ctx, client, projectID, refs)
+	if err != nil || dryRun {
+		return actions, err
+	}
+
+	enc := json.NewEncoder(audit)
+	var errs []error
+	for i := range actions {
+		if err := releaseAddress(ctx, client, enc, &actions[i]); err != nil {
+			actions[i].Error = err.Error()
+			errs = append(errs, err)
+		}
+	}
+	return actions, errors.Join(errs...)
+}
+
+// planAddressRelease looks up every address and refuses any still in use.
+func planAddressRelease(ctx context.Context, client AddressesAPI, projectID string, refs []string) ([]AddressAction, error) {
+	actions := make([]AddressAction, 0, len(refs))
+	for _, ref := range refs {
+		loc, name, err := ParseAddressRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		var address *computepb.Address
+		if loc == globalLocation {
+			address, err = client.GetGlobalAddress(ctx, &computepb.GetGlobalAddressRequest{Project: projectID, Address: name})
+		} else {
+			address, err = client.GetAddress(ctx, &computepb.GetAddressRequest{Project: projectID, Region: loc, Address: name})
+		}
+		if err != nil {
+			return nil, wrapError("addresses.get", projectID+"/"+ref, err)
+		}
+		if len(address.GetUsers()) > 0 {
+			return nil, &scanner.Error{Kind: scanner.KindConflict, Op: "addresses.get", Resource: projectID + "/" + ref,
+				Err: fmt.Errorf("address is in use by %s", strings.Join(address.GetUsers(), ", "))}
+		}
+		actions = append(actions, AddressAction{
+			Project:     projectID,
+			Location:    loc,
+			Name:        name,
+			Address:     address.GetAddress(),
+			AddressType: address.GetAddressType(),
+			Labels:      address.GetLabels(),
+		})
+	}
+	return actions, nil
+}
+
+// releaseAddress writes the audit record for action and then releases it.
+func releaseAddress(ctx context.Context, client AddressesAPI, audit *json.Encoder, action *AddressAction) error {
+	resource := action.Project + "/" + action.Location + "/" + action.Name
+	err := audit.Encode(AuditRecord{
+		Time:        time.Now().UTC(),
+		Action:      auditReleaseAddress,
+		Project:     action.Project,
+		Location:    action.Location,
+		Name:        action.Name,
+		Address:     action.Address,
+		AddressType: action.AddressType,
+		Labels:      action.Labels,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: writing audit record: %w", resource, err)
+	}
+
+	if action.Location == globalLocation {
+		err = client.DeleteGlobalAddress(ctx, &computepb.DeleteGlobalAddressRequest{Project: action.Project, Address: action.Name})
+	} else {
+		err = client.DeleteAddress(ctx, &computepb.DeleteAddressRequest{Project: action.Project, Region: action.Location, Address: action.Name})
+	}
+	if err != nil {
+		return wrapError("addresses.delete", resource, err)
+	}
+	action.Released = true
+	return nil
+}
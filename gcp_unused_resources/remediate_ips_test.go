@@ -0,0 +1,128 @@
+package unused_gcp_resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/protobuf/proto"
+)
+
+func reserved(name, ip string, labels map[string]string, users ...string) *computepb.Address {
+	a := address(name, users...)
+	a.Address = proto.String(ip)
+	a.AddressType = proto.String("EXTERNAL")
+	a.Labels = labels
+	return a
+}
+
+func releaseFixture() *fakeAddresses {
+	return &fakeAddresses{addresses: map[string][]*computepb.Address{
+		"us-central1": {
+			reserved("ip-1", "203.0.113.1", map[string]string{"team": "web"}),
+			reserved("ip-used", "203.0.113.2", nil, "forwardingRules/fr-1"),
+		},
+		"global": {reserved("ip-global", "198.51.100.1", nil)},
+	}}
+}
+
+func TestReleaseAddressesDryRun(t *testing.T) {
+	client := releaseFixture()
+
+	plan, err := ReleaseAddresses(context.Background(), "finops-accelerator",
+		[]string{"us-central1/ip-1", "global/ip-global"}, true, nil, WithAddressesClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan) != 2 || plan[0].Address != "203.0.113.1" || plan[0].Labels["team"] != "web" || plan[1].Location != "global" {
+		t.Errorf("plan = %+v", plan)
+	}
+	if len(client.released) != 0 {
+		t.Errorf("dry run released %v", client.released)
+	}
+}
+
+func TestReleaseAddressesAuditsBeforeRelease(t *testing.T) {
+	client := releaseFixture()
+	var audit bytes.Buffer
+
+	got, err := ReleaseAddresses(context.Background(), "finops-accelerator",
+		[]string{"us-central1/ip-1", "global/ip-global"}, false, &audit, WithAddressesClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"us-central1/ip-1", "global/ip-global"}; !reflect.DeepEqual(client.released, want) {
+		t.Errorf("released = %v, want %v", client.released, want)
+	}
+	if !got[0].Released || !got[1].Released {
+		t.Errorf("actions = %+v", got)
+	}
+	var records []AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		var r AuditRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 || records[0].Address != "203.0.113.1" || records[0].Labels["team"] != "web" ||
+		records[0].Action != "release-address" || records[0].Time.IsZero() {
+		t.Errorf("audit records = %+v", records)
+	}
+}
+
+func TestReleaseAddressesFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		refs      []string
+		deleteErr error
+		want      error
+		released  bool // whether an audit record may have been written
+	}{
+		{"in use", []string{"us-central1/ip-1", "us-central1/ip-used"}, nil, scanner.ErrConflict, false},
+		{"missing", []string{"us-central1/ip-gone"}, nil, scanner.ErrNotFound, false},
+		{"delete denied", []string{"us-central1/ip-1"}, &googleapi.Error{Code: http.StatusForbidden}, scanner.ErrPermission, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := releaseFixture()
+			client.deleteErr = tt.deleteErr
+			var audit bytes.Buffer
+
+			actions, err := ReleaseAddresses(context.Background(), "finops-accelerator", tt.refs, false, &audit, WithAddressesClient(client))
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if len(client.released) != 0 {
+				t.Errorf("released %v", client.released)
+			}
+			if !tt.released && audit.Len() != 0 {
+				t.Errorf("audit written for a rejected plan: %s", audit.String())
+			}
+			if tt.released && (len(actions) != 1 || actions[0].Released || actions[0].Error == "") {
+				t.Errorf("actions = %+v, want the failure recorded", actions)
+			}
+		})
+	}
+}
+
+func TestReleaseAddressesRequiresAuditLog(t *testing.T) {
+	client := releaseFixture()
+	if _, err := ReleaseAddresses(context.Background(), "finops-accelerator", []string{"us-central1/ip-1"}, false, nil, WithAddressesClient(client)); err == nil {
+		t.Error("released without an audit log")
+	}
+	if _, _, err := ParseAddressRef("ip-1"); err == nil {
+		t.Error("ParseAddressRef accepted a bare name")
+	}
+}
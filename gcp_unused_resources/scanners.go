@@ -0,0 +1,63 @@
+package unused_gcp_resources
+
+import (
+	"context"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// projectScan runs a detector against one project.
+type projectScan func(ctx context.Context, projectID string, scope scanner.Scope) (UnusedResourceMetrics, error)
+
+// projectScoped resolves which projects a scope covers: Scope.Project alone,
+// or, when Scope.Parent is set, every project beneath that organization or
+// folder that passes Scope.Projects. Either way findings carry their project.
+func projectScoped(scan projectScan) scanner.ScanFunc {
+	return func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		if scope.Parent == "" {
+			result, err := scan(ctx, scope.Project, scope)
+			if err != nil {
+				return scanner.Result{}, err
+			}
+			result.InProject(scope.Project)
+			return result, nil
+		}
+		projects, err := ListProjects(ctx, scope.Parent, scope.Projects)
+		if err != nil {
+			return scanner.Result{}, err
+		}
+		return ScanProjects(ctx, projects, DefaultProjectWorkers, func(ctx context.Context, projectID string) (UnusedResourceMetrics, error) {
+			return scan(ctx, projectID, scope)
+		})
+	}
+}
+
+// Register every GCP detector with the shared scanner registry. A zone or
+// region of "all" (or none at all) scans the whole project with one
+// aggregated list call. Scope.Tags is matched against resource labels.
+func init() {
+	scanner.Register(scanner.Detector{
+		Name:         "gcp/disks",
+		Provider:     scanner.GCP,
+		ResourceKind: kindDisks,
+		Scope:        scanner.Zonal,
+		Scan: projectScoped(func(ctx context.Context, projectID string, scope scanner.Scope) (UnusedResourceMetrics, error) {
+			if scope.Zone == "" || scope.Zone == scanner.AllZones {
+				return GetUnusedDisks(ctx, projectID, WithTagRules(scope.Tags))
+			}
+			return Get_Unused_Disks(ctx, projectID, scope.Zone, WithTagRules(scope.Tags))
+		}),
+	})
+	scanner.Register(scanner.Detector{
+		Name:         "gcp/ips",
+		Provider:     scanner.GCP,
+		ResourceKind: kindIPs,
+		Scope:        scanner.Regional,
+		Scan: projectScoped(func(ctx context.Context, projectID string, scope scanner.Scope) (UnusedResourceMetrics, error) {
+			if scope.Region == "" || scope.Region == scanner.AllRegions {
+				return GetUnusedIPs(ctx, projectID, WithTagRules(scope.Tags))
+			}
+			return Get_Unused_IPs(ctx, projectID, scope.Region, WithTagRules(scope.Tags))
+		}),
+	})
+}
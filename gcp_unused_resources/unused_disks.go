@@ -2,112 +2,187 @@ package unused_gcp_resources
 
 import (
 	"context"
-	"log"
+	"path"
+	"strconv"
+	"time"
 
-	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
-	"google.golang.org/api/iterator"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/protobuf/proto"
 )
 
-type UnusedResourceMetrics struct {
-	ResourceIDs []string
-	TotalInstancesCount int
-	UnusedInstancesCount int
+// UnusedResourceMetrics is the result every detector in this package returns.
+type UnusedResourceMetrics = scanner.Result
+
+// Resource kinds reported by the GCP detectors.
+const (
+	kindDisks = "disks"
+	kindIPs   = "ips"
+)
+
+// UnusedDisk holds detailed info about a persistent disk with no users.
+type UnusedDisk struct {
+	Name         string            // disk name
+	Location     string            // zone, or region for regional disks
+	Type         string            // pd-standard, pd-balanced, pd-ssd, ...
+	SizeGb       int64             // provisioned size in GB
+	CreationTime time.Time         // when the disk was created
+	Labels       map[string]string // labels of the resource
 }
 
-func Get_Unused_Disks(projectId string, zone string) UnusedResourceMetrics  {
-	ctx := context.Background()
+// finding converts the disk into the shared finding shape.
+func (u UnusedDisk) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.Name,
+		ResourceKind: kindDisks,
+		Tags:         u.Labels,
+		Name:         u.Name,
+		Region:       u.Location,
+		CreatedAt:    u.CreationTime,
+		Attributes: map[string]string{
+			"location":  u.Location,
+			"disk_type": u.Type,
+			"size_gb":   strconv.FormatInt(u.SizeGb, 10),
+		},
+	}
+}
+
+// parseTimestamp parses the RFC 3339 timestamps returned by the Compute API,
+// yielding the zero time when the value is missing or malformed.
+func parseTimestamp(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
 
-	// Create a new client
-	client, err := compute.NewDisksRESTClient(ctx)
+// Get_Unused_Disks lists the persistent disks in a zone that are not attached to any instance.
+func Get_Unused_Disks(ctx context.Context, projectId string, zone string, opts ...Option) (UnusedResourceMetrics, error) {
+	// Create a new client unless one was injected
+	o := newOptions(opts)
+	client, closeClient, err := o.disksClient(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create client: %v", err)
+		return UnusedResourceMetrics{}, wrapError("NewDisksRESTClient", projectId, err)
 	}
-	defer client.Close()
+	defer closeClient()
 
-	projectId = projectId
-	zone = zone
-	
 	req := &computepb.ListDisksRequest{
 		Project: projectId,
 		Zone:    zone,
 	}
 
+	progress := scanner.ProgressOf(ctx)
 	unused_disks := UnusedResourceMetrics{}
 	totalDiskCount := 0
 	unusedDiskCount := 0
 
-	it := client.List(ctx, req)
 	for {
-		disk, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+		page, err := client.ListDisks(ctx, req)
 		if err != nil {
-			log.Fatalf("Failed to list disks: %v", err)
+			return UnusedResourceMetrics{}, wrapError("disks.list", projectId+"/"+zone, err)
 		}
-		
-		totalDiskCount += 1
-
-		// Check if the disk is attached
-		if len(disk.GetUsers()) == 0 {
-			unusedDiskCount += 1
-			diskName := disk.GetName()
-			unused_disks.ResourceIDs = append(unused_disks.ResourceIDs, diskName)
+
+		progress.Found(len(page.GetItems()))
+		progress.Processed(len(page.GetItems()))
+		for _, disk := range page.GetItems() {
+			if o.labels.Excludes(disk.GetLabels()) {
+				unused_disks.ExcludedInstancesCount++
+				continue
+			}
+			totalDiskCount += 1
+			unused_disks.TotalSizeGiB += float64(disk.GetSizeGb()) // persistent disk GB are GiB
+
+			// Check if the disk is attached
+			if len(disk.GetUsers()) == 0 {
+				unusedDiskCount += 1
+				unused_disks.UnusedSizeGiB += float64(disk.GetSizeGb())
+				diskName := disk.GetName()
+				unused_disks.ResourceIDs = append(unused_disks.ResourceIDs, diskName)
+				unused_disks.Findings = append(unused_disks.Findings, unusedDisk(disk, zone).finding())
+			}
+		}
+
+		if page.GetNextPageToken() == "" {
+			break
 		}
+		req.PageToken = page.NextPageToken
 	}
-	
+
 	unused_disks.TotalInstancesCount = totalDiskCount
 	unused_disks.UnusedInstancesCount = unusedDiskCount
+	unused_disks.InRegion(zone)
 
-	return unused_disks
+	return unused_disks, nil
 }
 
-func Get_Unused_IPs(projectID string, region string) UnusedResourceMetrics {
-	ctx := context.Background()
-
-	// Create a Compute Service client
-	client, err := compute.NewAddressesRESTClient(ctx)
+// GetUnusedDisks lists the unattached persistent disks across every zone of a
+// project, including regional disks, with a single aggregated list call.
+func GetUnusedDisks(ctx context.Context, projectID string, opts ...Option) (UnusedResourceMetrics, error) {
+	o := newOptions(opts)
+	client, closeClient, err := o.disksClient(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create client: %v", err)
+		return UnusedResourceMetrics{}, wrapError("NewDisksRESTClient", projectID, err)
 	}
-	defer client.Close()
-
-	projectID = projectID
-	region = region
+	defer closeClient()
 
-	req := &computepb.ListAddressesRequest{
-		// TODO: Fill request struct fields.
-		// See https://pkg.go.dev/cloud.google.com/go/compute/apiv1/computepb#AggregatedListAddressesRequest.
-		Project: projectID,
-		Region: region,
+	req := &computepb.AggregatedListDisksRequest{
+		Project:              projectID,
+		ReturnPartialSuccess: proto.Bool(true),
 	}
 
-	unusedIPs := UnusedResourceMetrics{}
-	totalIPCount := 0
-	unusedIPCount := 0
-
-	it := client.List(ctx, req)
+	progress := scanner.ProgressOf(ctx)
+	result := UnusedResourceMetrics{}
+	locations := locationCounts{}
 	for {
-		ips, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+		page, err := client.AggregatedListDisks(ctx, req)
 		if err != nil {
-			log.Fatalf("Failed to list ips: %v", err)
+			return UnusedResourceMetrics{}, wrapError("disks.aggregatedList", projectID, err)
 		}
-		
-		totalIPCount += 1
-		// Check if the IP is attached
-		if len(ips.GetUsers()) == 0 {
-			unusedIPCount += 1
-			ipName := ips.GetName()
-			unusedIPs.ResourceIDs = append(unusedIPs.ResourceIDs, ipName)
+
+		for _, key := range sortedKeys(page.GetItems()) {
+			scoped := page.GetItems()[key]
+			loc := location(key)
+			if skipped, ok := scopeWarning(loc, scoped.GetWarning()); ok {
+				result.Skipped = append(result.Skipped, skipped)
+			}
+			progress.Found(len(scoped.GetDisks()))
+			progress.Processed(len(scoped.GetDisks()))
+			for _, disk := range scoped.GetDisks() {
+				if o.labels.Excludes(disk.GetLabels()) {
+					result.ExcludedInstancesCount++
+					continue
+				}
+				unused := len(disk.GetUsers()) == 0
+				locations.add(loc, unused)
+				result.TotalSizeGiB += float64(disk.GetSizeGb())
+				if unused {
+					result.UnusedSizeGiB += float64(disk.GetSizeGb())
+					result.ResourceIDs = append(result.ResourceIDs, disk.GetName())
+					result.Findings = append(result.Findings, unusedDisk(disk, loc).finding())
+				}
+			}
+		}
+		result.Skipped = append(result.Skipped, unreachable(page.GetUnreachables())...)
+
+		if page.GetNextPageToken() == "" {
+			break
 		}
+		req.PageToken = page.NextPageToken
 	}
 
-	unusedIPs.TotalInstancesCount = totalIPCount
-	unusedIPs.UnusedInstancesCount = unusedIPCount
+	locations.apply(&result)
+	return result, nil
+}
 
-	return unusedIPs
-}
\ No newline at end of file
+// unusedDisk describes disk, which was listed under location.
+func unusedDisk(disk *computepb.Disk, location string) UnusedDisk {
+	return UnusedDisk{
+		Name:         disk.GetName(),
+		Location:     location,
+		Type:         path.Base(disk.GetType()),
+		SizeGb:       disk.GetSizeGb(),
+		CreationTime: parseTimestamp(disk.GetCreationTimestamp()),
+		Labels:       disk.GetLabels(),
+	}
+}
@@ -0,0 +1,186 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetUnusedDisksPaginates(t *testing.T) {
+	client := &fakeDisks{
+		pageSize: 2,
+		disks: map[string][]*computepb.Disk{
+			"us-central1-a": {
+				{
+					Name:              proto.String("pd-0"),
+					Zone:              proto.String("https://www.googleapis.com/compute/v1/projects/finops-accelerator/zones/us-central1-a"),
+					Type:              proto.String("projects/finops-accelerator/zones/us-central1-a/diskTypes/pd-balanced"),
+					SizeGb:            proto.Int64(4),
+					CreationTimestamp: proto.String("2024-08-07T07:44:54.000-07:00"),
+				},
+				disk("pd-1", "instances/vm-1"),
+				disk("pd-2"),
+				disk("pd-3"),
+			},
+			"us-central1-b": {disk("pd-other")},
+		},
+	}
+
+	got, err := Get_Unused_Disks(context.Background(), "finops-accelerator", "us-central1-a", WithDisksClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"pd-0", "pd-2", "pd-3"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 4 || got.UnusedInstancesCount != 3 {
+		t.Errorf("counts = %d/%d, want 3/4", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	if got.TotalSizeGiB != 4 || got.UnusedSizeGiB != 4 {
+		t.Errorf("sizes = %v/%v GiB, want the 4 GiB of pd-0 unused", got.UnusedSizeGiB, got.TotalSizeGiB)
+	}
+	f := got.Findings[0]
+	if f.Region != "us-central1-a" || f.Attributes["location"] != "us-central1-a" || f.Attributes["disk_type"] != "pd-balanced" || f.Attributes["size_gb"] != "4" {
+		t.Errorf("finding = %+v", f)
+	}
+	if f.CreatedAt.IsZero() {
+		t.Error("CreatedAt was not parsed")
+	}
+	if len(client.requests) != 2 || client.requests[0].GetProject() != "finops-accelerator" {
+		t.Errorf("requests = %v", client.requests)
+	}
+}
+
+func TestGetUnusedDisksAggregated(t *testing.T) {
+	client := &fakeDisks{
+		aggregated: []*computepb.DiskAggregatedList{
+			{
+				Items: map[string]*computepb.DisksScopedList{
+					"zones/us-central1-b": {Disks: []*computepb.Disk{disk("pd-b"), disk("pd-b-used", "instances/vm-1")}},
+					"zones/us-central1-a": {Disks: []*computepb.Disk{disk("pd-a")}},
+					"zones/europe-west1-b": {Warning: &computepb.Warning{
+						Code: proto.String(computepb.Warning_NO_RESULTS_ON_PAGE.String()),
+					}},
+				},
+			},
+			{
+				Items: map[string]*computepb.DisksScopedList{
+					"regions/us-central1": {Disks: []*computepb.Disk{disk("pd-regional")}},
+					"zones/asia-east1-a": {Warning: &computepb.Warning{
+						Code:    proto.String(computepb.Warning_UNREACHABLE.String()),
+						Message: proto.String("zone unavailable"),
+					}},
+				},
+				Unreachables: []string{"zones/asia-east1-b"},
+			},
+		},
+	}
+
+	got, err := GetUnusedDisks(context.Background(), "finops-accelerator", WithDisksClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"pd-a", "pd-b", "pd-regional"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 4 || got.UnusedInstancesCount != 3 {
+		t.Errorf("counts = %d/%d, want 3/4", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	var locations []string
+	for _, f := range got.Findings {
+		locations = append(locations, f.Region)
+	}
+	if want := []string{"us-central1-a", "us-central1-b", "us-central1"}; !reflect.DeepEqual(locations, want) {
+		t.Errorf("finding locations = %v, want %v", locations, want)
+	}
+	wantRegions := map[string]scanner.Subtotal{
+		"us-central1-a": {TotalInstancesCount: 1, UnusedInstancesCount: 1},
+		"us-central1-b": {TotalInstancesCount: 2, UnusedInstancesCount: 1},
+		"us-central1":   {TotalInstancesCount: 1, UnusedInstancesCount: 1},
+	}
+	if !reflect.DeepEqual(got.Regions, wantRegions) {
+		t.Errorf("Regions = %v, want %v", got.Regions, wantRegions)
+	}
+	var skipped []string
+	for _, s := range got.Skipped {
+		skipped = append(skipped, s.Region)
+	}
+	if want := []string{"asia-east1-a", "asia-east1-b"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
+
+func TestGetUnusedDisksReturnsTypedError(t *testing.T) {
+	client := &fakeDisks{err: &googleapi.Error{Code: http.StatusForbidden}}
+
+	_, err := Get_Unused_Disks(context.Background(), "finops-accelerator", "us-central1-a", WithDisksClient(client))
+
+	if !errors.Is(err, scanner.ErrPermission) {
+		t.Errorf("err = %v, want a permission error", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want scanner.Kind
+	}{
+		{&googleapi.Error{Code: http.StatusUnauthorized}, scanner.KindAuth},
+		{&googleapi.Error{Code: http.StatusNotFound}, scanner.KindNotFound},
+		{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, scanner.KindThrottling},
+		{status.Error(codes.ResourceExhausted, "quota"), scanner.KindThrottling},
+		{status.Error(codes.PermissionDenied, "denied"), scanner.KindPermission},
+		{errors.New("boom"), scanner.KindUnknown},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.err); got != tt.want {
+			t.Errorf("classify(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestGetUnusedDisksExcludesLabelled(t *testing.T) {
+	kept := disk("pd-kept")
+	kept.Labels = map[string]string{"finops-keep": "true"}
+	client := &fakeDisks{
+		disks: map[string][]*computepb.Disk{"us-central1-a": {disk("pd-a"), kept}},
+		aggregated: []*computepb.DiskAggregatedList{{
+			Items: map[string]*computepb.DisksScopedList{
+				"zones/us-central1-a": {Disks: []*computepb.Disk{disk("pd-a"), kept}},
+			},
+		}},
+	}
+	rules := WithTagRules(scanner.TagRules{Exclude: map[string]string{"finops-keep": ""}})
+
+	zonal, err := Get_Unused_Disks(context.Background(), "finops-accelerator", "us-central1-a", WithDisksClient(client), rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregated, err := GetUnusedDisks(context.Background(), "finops-accelerator", WithDisksClient(client), rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, got := range map[string]UnusedResourceMetrics{"zonal": zonal, "aggregated": aggregated} {
+		if want := []string{"pd-a"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+			t.Errorf("%s: ResourceIDs = %v, want %v", name, got.ResourceIDs, want)
+		}
+		if got.TotalInstancesCount != 1 || got.ExcludedInstancesCount != 1 {
+			t.Errorf("%s: total/excluded = %d/%d, want 1/1", name, got.TotalInstancesCount, got.ExcludedInstancesCount)
+		}
+		if got.Regions["us-central1-a"].TotalInstancesCount != 1 {
+			t.Errorf("%s: Regions = %v", name, got.Regions)
+		}
+	}
+}
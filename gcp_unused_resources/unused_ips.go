@@ -0,0 +1,166 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"time"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnusedAddress holds detailed info about a reserved IP address with no users.
+type UnusedAddress struct {
+	Name         string            // address resource name
+	Address      string            // the reserved IP
+	AddressType  string            // EXTERNAL or INTERNAL
+	Region       string            // region of the address, or "global"
+	CreationTime time.Time         // when the address was reserved
+	Labels       map[string]string // labels of the resource
+}
+
+// finding converts the address into the shared finding shape.
+func (u UnusedAddress) finding() scanner.Finding {
+	return scanner.Finding{
+		ResourceID:   u.Name,
+		ResourceKind: kindIPs,
+		Tags:         u.Labels,
+		Name:         u.Name,
+		Region:       u.Region,
+		CreatedAt:    u.CreationTime,
+		Attributes: map[string]string{
+			"address":      u.Address,
+			"address_type": u.AddressType,
+			"location":     u.Region,
+		},
+	}
+}
+
+// Get_Unused_IPs lists the reserved addresses in a region that nothing uses.
+func Get_Unused_IPs(ctx context.Context, projectID string, region string, opts ...Option) (UnusedResourceMetrics, error) {
+	// Create a Compute Service client unless one was injected
+	o := newOptions(opts)
+	client, closeClient, err := o.addressesClient(ctx)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("NewAddressesRESTClient", projectID, err)
+	}
+	defer closeClient()
+
+	req := &computepb.ListAddressesRequest{
+		Project: projectID,
+		Region:  region,
+	}
+
+	progress := scanner.ProgressOf(ctx)
+	unusedIPs := UnusedResourceMetrics{}
+	totalIPCount := 0
+	unusedIPCount := 0
+
+	for {
+		page, err := client.ListAddresses(ctx, req)
+		if err != nil {
+			return UnusedResourceMetrics{}, wrapError("addresses.list", projectID+"/"+region, err)
+		}
+
+		progress.Found(len(page.GetItems()))
+		progress.Processed(len(page.GetItems()))
+		for _, ips := range page.GetItems() {
+			if o.labels.Excludes(ips.GetLabels()) {
+				unusedIPs.ExcludedInstancesCount++
+				continue
+			}
+			totalIPCount += 1
+			// Check if the IP is attached
+			if len(ips.GetUsers()) == 0 {
+				unusedIPCount += 1
+				ipName := ips.GetName()
+				unusedIPs.ResourceIDs = append(unusedIPs.ResourceIDs, ipName)
+				unusedIPs.Findings = append(unusedIPs.Findings, unusedAddress(ips, region).finding())
+			}
+		}
+
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+
+	unusedIPs.TotalInstancesCount = totalIPCount
+	unusedIPs.UnusedInstancesCount = unusedIPCount
+	unusedIPs.InRegion(region)
+
+	return unusedIPs, nil
+}
+
+// GetUnusedIPs lists the unused reserved addresses across every region of a
+// project. The aggregated list also holds its global addresses, under the
+// "global" scope, which are recorded under "global".
+func GetUnusedIPs(ctx context.Context, projectID string, opts ...Option) (UnusedResourceMetrics, error) {
+	o := newOptions(opts)
+	client, closeClient, err := o.addressesClient(ctx)
+	if err != nil {
+		return UnusedResourceMetrics{}, wrapError("NewAddressesRESTClient", projectID, err)
+	}
+	defer closeClient()
+
+	progress := scanner.ProgressOf(ctx)
+	result := UnusedResourceMetrics{}
+	locations := locationCounts{}
+	record := func(ip *computepb.Address, loc string) {
+		progress.Found(1)
+		progress.Processed(1)
+		if o.labels.Excludes(ip.GetLabels()) {
+			result.ExcludedInstancesCount++
+			return
+		}
+		unused := len(ip.GetUsers()) == 0
+		locations.add(loc, unused)
+		if unused {
+			result.ResourceIDs = append(result.ResourceIDs, ip.GetName())
+			result.Findings = append(result.Findings, unusedAddress(ip, loc).finding())
+		}
+	}
+
+	req := &computepb.AggregatedListAddressesRequest{
+		Project:              projectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+	for {
+		page, err := client.AggregatedListAddresses(ctx, req)
+		if err != nil {
+			return UnusedResourceMetrics{}, wrapError("addresses.aggregatedList", projectID, err)
+		}
+
+		for _, key := range sortedKeys(page.GetItems()) {
+			scoped := page.GetItems()[key]
+			loc := location(key)
+			if skipped, ok := scopeWarning(loc, scoped.GetWarning()); ok {
+				result.Skipped = append(result.Skipped, skipped)
+			}
+			for _, ip := range scoped.GetAddresses() {
+				record(ip, loc)
+			}
+		}
+		result.Skipped = append(result.Skipped, unreachable(page.GetUnreachables())...)
+
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+
+	locations.apply(&result)
+	return result, nil
+}
+
+// unusedAddress describes ip, which was listed under location.
+func unusedAddress(ip *computepb.Address, location string) UnusedAddress {
+	return UnusedAddress{
+		Name:         ip.GetName(),
+		Address:      ip.GetAddress(),
+		AddressType:  ip.GetAddressType(),
+		Region:       location,
+		CreationTime: parseTimestamp(ip.GetCreationTimestamp()),
+		Labels:       ip.GetLabels(),
+	}
+}
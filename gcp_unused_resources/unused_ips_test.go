@@ -0,0 +1,90 @@
+package unused_gcp_resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestGetUnusedIPs(t *testing.T) {
+	client := &fakeAddresses{
+		pageSize: 1,
+		addresses: map[string][]*computepb.Address{
+			"us-central1": {address("ip-free"), address("ip-used", "forwardingRules/fr-1")},
+		},
+	}
+
+	got, err := Get_Unused_IPs(context.Background(), "finops-accelerator", "us-central1", WithAddressesClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"ip-free"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 2 {
+		t.Errorf("TotalInstancesCount = %d, want 2", got.TotalInstancesCount)
+	}
+}
+
+func TestGetUnusedIPsAggregatedIncludesGlobal(t *testing.T) {
+	client := &fakeAddresses{
+		aggregated: []*computepb.AddressAggregatedList{{
+			Items: map[string]*computepb.AddressesScopedList{
+				"global":               {Addresses: []*computepb.Address{address("ip-global"), address("ip-global-used", "globalForwardingRules/fr-1")}},
+				"regions/us-central1":  {Addresses: []*computepb.Address{address("ip-central")}},
+				"regions/europe-west1": {Addresses: []*computepb.Address{address("ip-europe", "instances/vm-1")}},
+			},
+		}},
+	}
+
+	got, err := GetUnusedIPs(context.Background(), "finops-accelerator", WithAddressesClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"ip-global", "ip-central"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 4 || got.UnusedInstancesCount != 2 {
+		t.Errorf("counts = %d/%d, want 2/4", got.UnusedInstancesCount, got.TotalInstancesCount)
+	}
+	if f := got.Findings[0]; f.Region != "global" || f.Attributes["location"] != "global" {
+		t.Errorf("global finding = %+v", f)
+	}
+	if got.Regions["global"] != (scanner.Subtotal{TotalInstancesCount: 2, UnusedInstancesCount: 1}) {
+		t.Errorf("Regions = %v", got.Regions)
+	}
+}
+
+func TestGetUnusedIPsIncludesOnlyLabelled(t *testing.T) {
+	dev := address("ip-dev")
+	dev.Labels = map[string]string{"environment": "dev"}
+	client := &fakeAddresses{
+		aggregated: []*computepb.AddressAggregatedList{{
+			Items: map[string]*computepb.AddressesScopedList{
+				"global":              {Addresses: []*computepb.Address{address("ip-global")}},
+				"regions/us-central1": {Addresses: []*computepb.Address{dev, address("ip-other")}},
+			},
+		}},
+	}
+	rules := scanner.TagRules{Include: map[string]string{"environment": "dev"}}
+
+	got, err := GetUnusedIPs(context.Background(), "finops-accelerator", WithAddressesClient(client), WithTagRules(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"ip-dev"}; !reflect.DeepEqual(got.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", got.ResourceIDs, want)
+	}
+	if got.TotalInstancesCount != 1 || got.ExcludedInstancesCount != 2 {
+		t.Errorf("total/excluded = %d/%d, want 1/2", got.TotalInstancesCount, got.ExcludedInstancesCount)
+	}
+	if _, ok := got.Regions["global"]; ok {
+		t.Errorf("Regions = %v, want no global subtotal", got.Regions)
+	}
+}
@@ -0,0 +1,298 @@
+// Package jobs runs scans in the background for the API: a job is queued,
+// runs when a worker is free, reports the detector's progress and can be
+// cancelled, which cancels the context the detector scans with.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+const (
+	// DefaultWorkers is how many jobs a manager runs at once.
+	DefaultWorkers = 4
+	// DefaultHistory is how many finished jobs a manager keeps.
+	DefaultHistory = 100
+)
+
+// Status is where a job is in its life.
+type Status string
+
+const (
+	Queued     Status = "queued" // waiting for a free worker
+	Running    Status = "running"
+	Cancelling Status = "cancelling" // cancelled while running, the detector has not returned yet
+	Succeeded  Status = "succeeded"
+	Failed     Status = "failed"
+	Cancelled  Status = "cancelled"
+)
+
+// Done reports whether a job in status s has finished.
+func (s Status) Done() bool {
+	return s == Succeeded || s == Failed || s == Cancelled
+}
+
+// Progress is how far a running job got: the resources the detector has
+// processed out of those it has found so far.
+type Progress struct {
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+}
+
+// Job is a snapshot of one background scan.
+type Job struct {
+	ID         string             `json:"id"`
+	Detector   string             `json:"detector"`
+	Scope      scanner.Scope      `json:"scope"`
+	Parameters scanner.Parameters `json:"parameters"`
+	Status     Status             `json:"status"`
+	Progress   Progress           `json:"progress"`
+	CreatedAt  time.Time          `json:"created_at"`
+	StartedAt  time.Time          `json:"started_at,omitempty"`
+	FinishedAt time.Time          `json:"finished_at,omitempty"`
+	Result     *scanner.Result    `json:"result,omitempty"` // once succeeded
+	Error      string             `json:"error,omitempty"`
+	Kind       scanner.Kind       `json:"kind,omitempty"`
+}
+
+// job is a Job with what it takes to run and cancel it.
+type job struct {
+	Job
+	scan     config.Scan
+	ctx      context.Context
+	cancel   context.CancelFunc
+	progress scanner.Progress
+	done     chan struct{} // closed once finished
+}
+
+// Manager runs jobs, at most a fixed number at once, and keeps them until
+// they are among the oldest beyond its history size.
+type Manager struct {
+	catalog *pricing.Catalog
+	workers chan struct{} // one token per running job
+	history int
+	now     func() time.Time
+
+	mu       sync.Mutex
+	jobs     map[string]*job
+	finished []string // IDs of finished jobs, oldest first
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// New returns a manager pricing results from catalog that runs up to
+// workers jobs at once, or DefaultWorkers when it is not positive.
+func New(catalog *pricing.Catalog, workers int) *Manager {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &Manager{
+		catalog: catalog,
+		workers: make(chan struct{}, workers),
+		history: DefaultHistory,
+		now:     time.Now,
+		jobs:    map[string]*job{},
+	}
+}
+
+// Submit queues scan and returns the job running it.
+func (m *Manager) Submit(scan config.Scan) (Job, error) {
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+	s := scan.Detector.New(scan.Scope, scan.Params)
+	j := &job{
+		Job: Job{
+			ID:         id,
+			Detector:   scan.Detector.Name,
+			Scope:      s.Scope(),
+			Parameters: s.Parameters(),
+			Status:     Queued,
+			CreatedAt:  m.now().UTC(),
+		},
+		scan: scan,
+		done: make(chan struct{}),
+	}
+	j.ctx, j.cancel = context.WithCancel(scanner.WithProgress(context.Background(), &j.progress))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		j.cancel()
+		return Job{}, errors.New("jobs: manager is closed")
+	}
+	m.jobs[id] = j
+	m.wg.Add(1)
+	go m.run(j)
+	return j.snapshot(), nil
+}
+
+// run waits for a worker, scans and records the outcome.
+func (m *Manager) run(j *job) {
+	defer m.wg.Done()
+	defer j.cancel()
+	select {
+	case m.workers <- struct{}{}:
+		defer func() { <-m.workers }()
+	case <-j.ctx.Done():
+		m.finish(j, nil, j.ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	if j.ctx.Err() != nil { // cancelled as it got a worker
+		m.mu.Unlock()
+		m.finish(j, nil, j.ctx.Err())
+		return
+	}
+	j.Status, j.StartedAt = Running, m.now().UTC()
+	m.mu.Unlock()
+
+	run, err := store.Scan(j.ctx, []config.Scan{j.scan}, m.catalog)
+	m.finish(j, &run.Result, err)
+}
+
+// finish records how j ended and forgets the oldest finished jobs beyond
+// the history size.
+func (m *Manager) finish(j *job, result *scanner.Result, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j.Progress = j.counts()
+	j.FinishedAt = m.now().UTC()
+	switch {
+	case j.ctx.Err() != nil:
+		j.Status = Cancelled
+	case err != nil:
+		j.Status, j.Error, j.Kind = Failed, err.Error(), scanner.KindOf(err)
+	default:
+		j.Status, j.Result = Succeeded, result
+	}
+	close(j.done)
+
+	m.finished = append(m.finished, j.ID)
+	if drop := len(m.finished) - m.history; drop > 0 {
+		for _, id := range m.finished[:drop] {
+			delete(m.jobs, id)
+		}
+		m.finished = m.finished[drop:]
+	}
+}
+
+// Get returns job id, or an error of kind not_found.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, notFound(id)
+	}
+	return j.snapshot(), nil
+}
+
+// List returns every job kept, newest first.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		list = append(list, j.snapshot())
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreatedAt.After(list[b].CreatedAt) })
+	return list
+}
+
+// Cancel cancels job id. A queued job is cancelled at once; a running one
+// is cancelling until its detector returns. Cancelling a finished job is an
+// error of kind conflict.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return Job{}, notFound(id)
+	}
+	if j.Status.Done() {
+		m.mu.Unlock()
+		return Job{}, &scanner.Error{Kind: scanner.KindConflict, Resource: id, Err: fmt.Errorf("job already %s", j.Status)}
+	}
+	queued := j.Status == Queued
+	if !queued {
+		j.Status = Cancelling
+	}
+	j.cancel()
+	m.mu.Unlock()
+
+	if queued {
+		<-j.done // run returns right away, there is nothing to wait for
+	}
+	return m.Get(id)
+}
+
+// Wait blocks until job id finishes or ctx is done and returns the job.
+func (m *Manager) Wait(ctx context.Context, id string) (Job, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return Job{}, notFound(id)
+	}
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return j.snapshot(), nil
+}
+
+// Close refuses new jobs, cancels the others and waits for them to finish.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	m.closed = true
+	for _, j := range m.jobs {
+		j.cancel()
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
+
+// snapshot returns a copy of the job with its current progress. The
+// caller holds the manager's lock.
+func (j *job) snapshot() Job {
+	snap := j.Job
+	if !snap.Status.Done() {
+		snap.Progress = j.counts()
+	}
+	return snap
+}
+
+func (j *job) counts() Progress {
+	processed, total := j.progress.Counts()
+	return Progress{Processed: processed, Total: total}
+}
+
+func notFound(id string) error {
+	return &scanner.Error{Kind: scanner.KindNotFound, Resource: id, Err: errors.New("no such job")}
+}
+
+// newID returns a random job ID.
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+// scan returns a scan of a detector whose scan calls fn.
+func scan(fn scanner.ScanFunc) config.Scan {
+	d := scanner.Detector{Name: "aws/ec2", Provider: scanner.AWS, Defaults: scanner.Parameters{Days: 7}, Scan: fn}
+	return config.Scan{Detector: d, Scope: scanner.Scope{Region: "us-east-1"}}
+}
+
+// blocking scans two of three resources, then waits for its context.
+func blocking(started chan<- struct{}) config.Scan {
+	return scan(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		progress := scanner.ProgressOf(ctx)
+		progress.Found(3)
+		progress.Processed(2)
+		close(started)
+		<-ctx.Done()
+		return scanner.Result{}, ctx.Err()
+	})
+}
+
+func newManager(t *testing.T, workers int) *Manager {
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(catalog, workers)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func wait(t *testing.T, m *Manager, id string) Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := m.Wait(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestJobSucceeds(t *testing.T) {
+	m := newManager(t, 1)
+	job, err := m.Submit(scan(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		scanner.ProgressOf(ctx).Found(2)
+		scanner.ProgressOf(ctx).Processed(2)
+		return scanner.Result{ResourceIDs: []string{"i-1"}, TotalInstancesCount: 2, UnusedInstancesCount: 1}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != Queued || job.Parameters.Days != 7 {
+		t.Errorf("submitted %+v, want queued with the detector's defaults", job)
+	}
+
+	job = wait(t, m, job.ID)
+	if job.Status != Succeeded || job.Result == nil || job.Result.UnusedInstancesCount != 1 || job.Progress != (Progress{2, 2}) {
+		t.Errorf("job = %+v", job)
+	}
+	if job.StartedAt.IsZero() || job.FinishedAt.Before(job.StartedAt) {
+		t.Errorf("times = %s, %s", job.StartedAt, job.FinishedAt)
+	}
+}
+
+func TestJobFails(t *testing.T) {
+	m := newManager(t, 1)
+	job, _ := m.Submit(scan(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		return scanner.Result{}, scanner.ErrThrottled
+	}))
+	if job = wait(t, m, job.ID); job.Status != Failed || job.Kind != scanner.KindThrottling || job.Result != nil {
+		t.Errorf("job = %+v", job)
+	}
+	if _, err := m.Cancel(job.ID); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("cancelling a finished job: err = %v, want conflict", err)
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	m := newManager(t, 1)
+	started := make(chan struct{})
+	job, _ := m.Submit(blocking(started))
+	<-started
+
+	if job, _ = m.Get(job.ID); job.Status != Running || job.Progress != (Progress{2, 3}) {
+		t.Errorf("job = %+v, want running at 2 of 3", job)
+	}
+	if job, _ = m.Cancel(job.ID); job.Status != Cancelling && job.Status != Cancelled {
+		t.Errorf("job = %+v, want cancelling", job)
+	}
+	if job = wait(t, m, job.ID); job.Status != Cancelled || job.Error != "" {
+		t.Errorf("job = %+v, want cancelled", job)
+	}
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+	m := newManager(t, 1)
+	started := make(chan struct{})
+	first, _ := m.Submit(blocking(started))
+	<-started
+	second, _ := m.Submit(blocking(make(chan struct{})))
+
+	if job, _ := m.Get(second.ID); job.Status != Queued {
+		t.Errorf("second job = %+v, want queued behind the first", job)
+	}
+	if job, _ := m.Cancel(second.ID); job.Status != Cancelled || !job.StartedAt.IsZero() {
+		t.Errorf("second job = %+v, want cancelled before it started", job)
+	}
+	if jobs := m.List(); len(jobs) != 2 || jobs[0].ID != second.ID {
+		t.Errorf("List = %+v, want the second job first", jobs)
+	}
+	m.Cancel(first.ID)
+}
+
+func TestGetUnknownJob(t *testing.T) {
+	m := newManager(t, 1)
+	if _, err := m.Get("nope"); !errors.Is(err, scanner.ErrNotFound) {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	m := newManager(t, 1)
+	m.history = 2
+	var ids []string
+	for range 3 {
+		job, _ := m.Submit(scan(func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+			return scanner.Result{}, nil
+		}))
+		wait(t, m, job.ID)
+		ids = append(ids, job.ID)
+	}
+	if _, err := m.Get(ids[0]); err == nil {
+		t.Error("the oldest job was kept")
+	}
+	if len(m.List()) != 2 {
+		t.Errorf("List = %+v, want 2 jobs", m.List())
+	}
+}
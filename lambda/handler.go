@@ -0,0 +1,139 @@
+// Package lambda runs scans from an AWS Lambda function: on an EventBridge
+// schedule, or when invoked with a payload naming the detectors and regions
+// to scan. Runs are stored like those of cmd/collect.
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// Request narrows the scans of one invocation. It is the whole payload of
+// a direct invocation, or the detail of an EventBridge event; empty fields
+// keep what the config says.
+type Request struct {
+	Detectors []string `json:"detectors"` // detector names, e.g. "aws/ebs"
+	Regions   []string `json:"regions"`   // regions, or "all", replacing the config's AWS and GCP regions
+}
+
+// Summary is what an invocation returns.
+type Summary struct {
+	Source string       `json:"source"` // "aws.events" for a scheduled event, "invoke" otherwise
+	Runs   []RunSummary `json:"runs"`   // the stored runs
+	Errors []string     `json:"errors,omitempty"`
+}
+
+// RunSummary is the KPI of one stored run.
+type RunSummary struct {
+	Detector    string  `json:"detector"`
+	RunID       string  `json:"run_id"`
+	Total       int     `json:"total_count"`
+	Unused      int     `json:"unused_count"`
+	MonthlyCost float64 `json:"monthly_cost_usd"`
+	Duration    float64 `json:"duration_seconds"`
+}
+
+// Handler scans what the config and the invocation ask for and stores the
+// runs.
+type Handler struct {
+	cfg     *config.Config
+	store   store.Store
+	catalog *pricing.Catalog
+}
+
+// NewHandler returns a handler running the scans of cfg into s.
+func NewHandler(cfg *config.Config, s store.Store, catalog *pricing.Catalog) *Handler {
+	return &Handler{cfg: cfg, store: s, catalog: catalog}
+}
+
+// Handle runs one invocation. It returns an error, failing the invocation,
+// when the payload is invalid or any detector failed; the runs of the
+// others are stored all the same.
+func (h *Handler) Handle(ctx context.Context, payload json.RawMessage) (Summary, error) {
+	source, req, err := decode(payload)
+	if err != nil {
+		return Summary{}, &scanner.Error{Kind: scanner.KindInvalid, Err: err}
+	}
+	cfg, err := h.narrow(req)
+	if err != nil {
+		return Summary{}, &scanner.Error{Kind: scanner.KindInvalid, Err: err}
+	}
+
+	summary := Summary{Source: source, Runs: []RunSummary{}}
+	runs, err := store.Collect(ctx, h.store, cfg.Scans(), h.catalog)
+	for _, run := range runs {
+		summary.Runs = append(summary.Runs, RunSummary{
+			Detector:    run.Detector,
+			RunID:       run.ID,
+			Total:       run.Result.TotalInstancesCount,
+			Unused:      run.Result.UnusedInstancesCount,
+			MonthlyCost: run.Result.MonthlyCost,
+			Duration:    run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond).Seconds(),
+		})
+	}
+	if err != nil {
+		summary.Errors = []string{err.Error()}
+	}
+	return summary, err
+}
+
+// decode tells an EventBridge event from a direct invocation and returns
+// the request either carries.
+func decode(payload json.RawMessage) (string, Request, error) {
+	var event events.EventBridgeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return "", Request{}, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	source, body := "invoke", []byte(payload)
+	if event.Source != "" && event.DetailType != "" {
+		source, body = event.Source, event.Detail
+	}
+	var req Request
+	if len(body) > 0 && string(body) != "null" {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return "", Request{}, fmt.Errorf("request: %w", err)
+		}
+	}
+	return source, req, nil
+}
+
+// narrow returns a copy of the handler's config limited to req, validated.
+// The handler's own config is left alone, since Lambda reuses it across
+// invocations.
+func (h *Handler) narrow(req Request) (*config.Config, error) {
+	cfg := *h.cfg
+	if len(req.Detectors) > 0 {
+		cfg.Detectors = make(map[string]scanner.Parameters, len(req.Detectors))
+		for _, name := range req.Detectors {
+			cfg.Detectors[name] = h.cfg.Detectors[name]
+		}
+	}
+	if len(req.Regions) > 0 {
+		if cfg.AWS != nil {
+			aws := *cfg.AWS
+			aws.Regions = req.Regions
+			cfg.AWS = &aws
+		}
+		if cfg.GCP != nil {
+			gcp := *cfg.GCP
+			gcp.Regions = req.Regions
+			cfg.GCP = &gcp
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Scans()) == 0 {
+		return nil, errors.New("nothing to scan")
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,138 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// scanned records the regions each fake detector was asked to scan.
+var scanned = map[string][]string{}
+
+func init() {
+	for _, name := range []string{"aws/ebs", "aws/ec2"} {
+		scanner.Register(scanner.Detector{
+			Name:     name,
+			Provider: scanner.AWS,
+			Scope:    scanner.Regional,
+			Scan: func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+				scanned[name] = append(scanned[name], scope.Region)
+				if scope.Region == "broken-1" {
+					return scanner.Result{}, scanner.ErrThrottled
+				}
+				return scanner.Result{TotalInstancesCount: 2, UnusedInstancesCount: 1}, nil
+			},
+		})
+	}
+}
+
+func newHandler(t *testing.T) (*Handler, store.Store) {
+	s, err := store.OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "unused.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		AWS:       &config.AWS{Regions: []string{"us-east-1", "us-west-2"}},
+		Detectors: map[string]scanner.Parameters{"aws/ebs": {}, "aws/ec2": {}},
+	}
+	return NewHandler(cfg, s, catalog), s
+}
+
+func invoke(t *testing.T, h *Handler, event string) (Summary, error) {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", event))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clear(scanned)
+	return h.Handle(context.Background(), payload)
+}
+
+func TestScheduledEventScansEverything(t *testing.T) {
+	h, s := newHandler(t)
+
+	summary, err := invoke(t, h, "scheduled-event.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Source != "aws.events" || len(summary.Runs) != 2 {
+		t.Fatalf("summary = %+v, want both detectors", summary)
+	}
+	for _, run := range summary.Runs {
+		if run.Total != 4 || run.Unused != 2 {
+			t.Errorf("%s: %+v, want both regions merged", run.Detector, run)
+		}
+		if latest, err := s.LatestRun(context.Background(), run.Detector); err != nil || latest.ID != run.RunID {
+			t.Errorf("%s: run %s not stored: %v", run.Detector, run.RunID, err)
+		}
+	}
+}
+
+func TestInvocationNarrowsScans(t *testing.T) {
+	h, _ := newHandler(t)
+	for event, wantRegion := range map[string]string{
+		"event-with-detail.json": "eu-west-1",
+		"payload.json":           "us-east-1",
+	} {
+		summary, err := invoke(t, h, event)
+		if err != nil {
+			t.Fatalf("%s: %v", event, err)
+		}
+		if len(summary.Runs) != 1 || summary.Runs[0].Detector != "aws/ebs" {
+			t.Errorf("%s: summary = %+v, want aws/ebs only", event, summary)
+		}
+		if len(scanned) != 1 || len(scanned["aws/ebs"]) != 1 || scanned["aws/ebs"][0] != wantRegion {
+			t.Errorf("%s: scanned %v, want aws/ebs in %s", event, scanned, wantRegion)
+		}
+	}
+	// The handler's config is reused by later invocations and must not change.
+	if regions := h.cfg.AWS.Regions; len(regions) != 2 || len(h.cfg.Detectors) != 2 {
+		t.Errorf("invocations changed the config: %v, %v", regions, h.cfg.Detectors)
+	}
+}
+
+func TestFailedDetectorFailsInvocation(t *testing.T) {
+	h, _ := newHandler(t)
+	payload, _ := json.Marshal(Request{Regions: []string{"us-east-1", "broken-1"}})
+
+	summary, err := h.Handle(context.Background(), payload)
+
+	if !errors.Is(err, scanner.ErrThrottled) || len(summary.Errors) != 1 {
+		t.Errorf("summary = %+v, err = %v", summary, err)
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	h, _ := newHandler(t)
+	for _, payload := range []string{
+		`[1, 2]`,
+		`{"detectors": ["aws/nat"]}`,
+		`{"detectors": ["gcp/disks"]}`,
+		`{"regions": ["all", "us-east-1"]}`,
+	} {
+		if _, err := h.Handle(context.Background(), json.RawMessage(payload)); !errors.Is(err, scanner.ErrInvalid) {
+			t.Errorf("%s: err = %v, want invalid", payload, err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	source, req, err := decode(json.RawMessage(`{"source": "aws.events", "detail-type": "Scheduled Event", "detail": {"detectors": ["aws/ec2"]}}`))
+	if err != nil || source != "aws.events" || len(req.Detectors) != 1 || req.Detectors[0] != "aws/ec2" {
+		t.Errorf("decode = %q, %+v, %v", source, req, err)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPI is the OpenAPI 3 document of the /v1 API. openapi_test.go checks
+// it against the routes and the response types, and the client package is
+// generated from it.
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// serveOpenAPI returns the OpenAPI document.
+func serveOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPI)
+}
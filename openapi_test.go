@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/client"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/jobs"
+	"github.com/sawlemon/unused-cloud-resources/remediation"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/scheduler"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// document is the part of an OpenAPI document the tests look at.
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Legacy     []struct{ Method, Path string }       `json:"x-legacy-routes"`
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type schema struct {
+	Ref        string            `json:"$ref"`
+	Required   []string          `json:"required"`
+	Properties map[string]schema `json:"properties"`
+	AllOf      []schema          `json:"allOf"`
+}
+
+func loadDocument(t *testing.T) document {
+	t.Helper()
+	var doc document
+	if err := json.Unmarshal(openAPI, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// fields returns the properties and required properties of s, following
+// $ref and allOf.
+func (doc document) fields(s schema) (properties, required []string) {
+	if s.Ref != "" {
+		return doc.fields(doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")])
+	}
+	for name := range s.Properties {
+		properties = append(properties, name)
+	}
+	required = append(required, s.Required...)
+	for _, part := range s.AllOf {
+		p, r := doc.fields(part)
+		properties, required = append(properties, p...), append(required, r...)
+	}
+	slices.Sort(properties)
+	slices.Sort(required)
+	return properties, required
+}
+
+// jsonFields returns the JSON names of the fields of struct t, and those
+// without omitempty, as encoding/json sees them.
+func jsonFields(t reflect.Type) (names, required []string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			n, r := jsonFields(f.Type)
+			names, required = append(names, n...), append(required, r...)
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(required)
+	return names, required
+}
+
+// newServer returns the router of the API server, with every route main
+// registers, on the default config and an empty store.
+func newServer(t *testing.T) *gin.Engine {
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs, err := store.OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "unused.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { runs.Close() })
+	plans, err := remediation.NewFileStore(filepath.Join(t.TempDir(), "plans"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanJobs := jobs.New(catalog, 1)
+	t.Cleanup(scanJobs.Close)
+	r := gin.New()
+	routes(r, config.Default(), catalog, runs, scheduler.New(runs, catalog), remediation.NewService(plans, remediation.Executors(io.Discard)), scanJobs)
+	return r
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	r := newServer(t)
+	doc := loadDocument(t)
+	param := regexp.MustCompile(`:(\w+)`)
+
+	// Legacy routes are listed with {placeholders} for the configured
+	// detectors' resources.
+	legacy := map[*regexp.Regexp]string{}
+	placeholder := regexp.MustCompile(`\{\w+\}`)
+	for _, route := range doc.Legacy {
+		legacy[regexp.MustCompile("^"+placeholder.ReplaceAllString(route.Path, "[^/]+")+"$")] = route.Method + " " + route.Path
+	}
+	served := map[string]bool{}
+	for _, route := range r.Routes() {
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		op := route.Method + " " + path
+		if _, ok := doc.Paths[path][strings.ToLower(route.Method)]; ok {
+			served[op] = true
+			continue
+		}
+		documented := false
+		for pattern, listed := range legacy {
+			if strings.HasPrefix(listed, route.Method+" ") && pattern.MatchString(path) {
+				served[listed], documented = true, true
+			}
+		}
+		if !documented {
+			t.Errorf("%s is served but neither in openapi.json nor in its x-legacy-routes", op)
+		}
+	}
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			if op := strings.ToUpper(method) + " " + path; !served[op] {
+				t.Errorf("%s is in openapi.json but not served", op)
+			}
+		}
+	}
+	for _, listed := range legacy {
+		if !served[listed] {
+			t.Errorf("%s is in x-legacy-routes but not served", listed)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), openAPI) {
+		t.Errorf("GET /openapi.json: %d", w.Code)
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	doc := loadDocument(t)
+	for name, v := range map[string]any{
+		"Error":         apiError{},
+		"ErrorBody":     errorBody{},
+		"Scope":         scanner.Scope{},
+		"TagRules":      scanner.TagRules{},
+		"ProjectFilter": scanner.ProjectFilter{},
+		"Parameters":    scanner.Parameters{},
+		"Finding":       scanner.Finding{},
+		"Skipped":       scanner.Skipped{},
+		"Subtotal":      scanner.Subtotal{},
+		"Result":        scanner.Result{},
+		"Progress":      jobs.Progress{},
+		"Job":           jobs.Job{},
+		"Listed":        store.Listed{},
+		"FindingPage":   store.FindingPage{},
+		"KPI":           store.KPI{},
+		"ResourceKPI":   store.ResourceKPI{},
+		"Summary":       store.Summary{},
+		"Schedule":      scheduler.Entry{},
+		"ScheduledRun":  scheduler.Record{},
+		"Plan":          remediation.Plan{},
+	} {
+		s, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("no schema %s", name)
+			continue
+		}
+		properties, required := doc.fields(s)
+		names, always := jsonFields(reflect.TypeOf(v))
+		if !slices.Equal(properties, names) {
+			t.Errorf("%s has properties %v, %T has fields %v", name, properties, v, names)
+		}
+		if !slices.Equal(required, always) {
+			t.Errorf("%s requires %v, %T always has %v", name, required, v, always)
+		}
+	}
+
+	// These endpoints build their bodies from maps.
+	r, _ := newV1(t)
+	server := newServer(t)
+	for name, body := range map[string]func() map[string]any{
+		"ScanResult": func() (body map[string]any) { get(t, r, "/v1/aws/fake", &body); return body },
+		"Scanner": func() map[string]any {
+			var body []map[string]any
+			get(t, server, "/scanners", &body)
+			return body[0]
+		},
+		"PlainError": func() (body map[string]any) { get(t, server, "/plans/nope", &body); return body },
+	} {
+		var keys []string
+		for key := range body() {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		if properties, required := doc.fields(doc.Components.Schemas[name]); !slices.Equal(properties, keys) || !slices.Equal(required, keys) {
+			t.Errorf("%s has properties %v, its endpoint returns %v", name, properties, keys)
+		}
+	}
+}
+
+func TestOpenAPIClient(t *testing.T) {
+	r, runs := newV1(t)
+	server := httptest.NewServer(r)
+	defer server.Close()
+	c, err := client.NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	finding := scanner.Finding{ResourceID: "vol-1", ResourceKind: "ebs", Region: "us-east-1", MonthlyCost: 8}
+	run := store.Run{ID: "run-1", Detector: "aws/fake", StartedAt: time.Now(), FinishedAt: time.Now(),
+		Result: scanner.Result{TotalInstancesCount: 2, UnusedInstancesCount: 1, Findings: []scanner.Finding{finding}, MonthlyCost: 8}}
+	if err := runs.PutRun(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+
+	kpi, err := c.GetKPIWithResponse(ctx)
+	if err != nil || kpi.JSON200 == nil || kpi.JSON200.Overall.Percentage != 50 || kpi.JSON200.Resources[0].RunId != "run-1" {
+		t.Fatalf("GetKPI: %v %s", err, kpi.Body)
+	}
+
+	providers := []client.Provider{client.Aws}
+	regions := []string{"us-east-1", "eu-west-1"}
+	page, err := c.ListFindingsWithResponse(ctx, &client.ListFindingsParams{Provider: &providers, Region: &regions})
+	if err != nil || page.JSON200 == nil || page.JSON200.Total != 1 || page.JSON200.Findings[0].ResourceId != "vol-1" {
+		t.Fatalf("ListFindings: %v %s", err, page.Body)
+	}
+
+	region := "us-west-2"
+	job, err := c.SubmitScanWithResponse(ctx, client.ScanRequest{Detector: "aws/fake", Region: &region})
+	if err != nil || job.JSON202 == nil || job.JSON202.Scope.Region == nil || *job.JSON202.Scope.Region != region {
+		t.Fatalf("SubmitScan: %v %s", err, job.Body)
+	}
+
+	missing, err := c.GetScanWithResponse(ctx, "nope")
+	if err != nil || missing.StatusCode() != http.StatusNotFound || missing.JSONDefault == nil || missing.JSONDefault.Error.Kind != client.NotFound {
+		t.Errorf("GetScan of an unknown job: %v %s", err, missing.Body)
+	}
+
+	// The unversioned routes answer errors with a body of their own.
+	full := httptest.NewServer(newServer(t))
+	defer full.Close()
+	if c, err = client.NewClientWithResponses(full.URL); err != nil {
+		t.Fatal(err)
+	}
+	plans, err := c.ListPlansWithResponse(ctx)
+	if err != nil || plans.JSON200 == nil || len(*plans.JSON200) != 0 {
+		t.Errorf("ListPlans: %v %s", err, plans.Body)
+	}
+	plan, err := c.GetPlanWithResponse(ctx, "nope")
+	if err != nil || plan.StatusCode() != http.StatusNotFound || plan.JSONDefault == nil || plan.JSONDefault.Kind != client.NotFound {
+		t.Errorf("GetPlan of an unknown plan: %v %s", err, plan.Body)
+	}
+}
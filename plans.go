@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/remediation"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// planRequest is the body of POST /plans: the scan whose findings to
+// remediate and who asks for it.
+type planRequest struct {
+	Detector    string        `json:"detector" binding:"required"`
+	Scope       scanner.Scope `json:"scope"`
+	ResourceIDs []string      `json:"resource_ids"` // findings to remediate; empty means all of them
+	RequestedBy string        `json:"requested_by" binding:"required"`
+	Reason      string        `json:"reason"`
+}
+
+// decision is the body of POST /plans/:id/approve and /reject.
+type decision struct {
+	User    string `json:"user" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+// plansAPI serves the approval workflow for remediations.
+type plansAPI struct {
+	plans *remediation.Service
+	cfg   *config.Config
+}
+
+// register adds the workflow routes to r.
+func (a plansAPI) register(r gin.IRouter) {
+	r.POST("/plans", a.create)
+	r.GET("/plans", a.list)
+	r.GET("/plans/:id", a.get)
+	r.POST("/plans/:id/approve", a.decide(a.plans.Approve))
+	r.POST("/plans/:id/reject", a.decide(a.plans.Reject))
+	r.POST("/plans/:id/execute", a.execute)
+}
+
+// create scans, picks the requested findings and stores them as a pending
+// plan after a dry run.
+func (a plansAPI) create(c *gin.Context) {
+	var req planRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("body: %w", err)})
+		return
+	}
+	d, ok := scanner.Lookup(req.Detector)
+	if !ok {
+		abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: errors.New("unknown detector " + req.Detector)})
+		return
+	}
+	scope, err := a.scope(d, req.Scope)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := d.New(scope, a.cfg.Detectors[d.Name]).Scan(ctx)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	findings, err := remediation.SelectFindings(result.Findings, req.ResourceIDs)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	plan, err := a.plans.Create(ctx, remediation.Request{
+		Detector:    d.Name,
+		Role:        scope.Role,
+		Findings:    findings,
+		RequestedBy: req.RequestedBy,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// scope returns the scope of the scan a plan is made from: the location
+// the request asks for, which must be one the config scans, with the
+// config's role and tag rules, so resources the rules protect never become
+// targets. The role, parent and rules are the config's alone; asking for
+// others is an error of kind invalid.
+func (a plansAPI) scope(d scanner.Detector, req scanner.Scope) (scanner.Scope, error) {
+	bad := paramError{}
+	if req.Role != "" {
+		bad["scope.role"] = "not accepted: plans assume the configured role"
+	}
+	if req.Parent != "" || len(req.Projects.Include)+len(req.Projects.Exclude)+len(req.Projects.Labels) > 0 {
+		bad["scope.parent"] = "not accepted: plans scan the configured projects"
+	}
+	if !req.Tags.Empty() {
+		bad["scope.tags"] = "not accepted: the configured tag rules apply"
+	}
+	scope := scanner.Scope{Tags: a.cfg.Tags}
+	var regions, zones []string
+	switch {
+	case d.Provider == scanner.AWS && a.cfg.AWS != nil:
+		scope.Role, scope.Account, regions = a.cfg.AWS.Role, req.Account, a.cfg.AWS.Regions
+		if !slices.Contains(a.cfg.AWS.Accounts, scanner.AllAccounts) && !slices.Contains(orNone(a.cfg.AWS.Accounts), req.Account) {
+			bad["scope.account"] = "not an account the config scans"
+		}
+	case d.Provider == scanner.GCP && a.cfg.GCP != nil:
+		scope.Project, regions, zones = req.Project, a.cfg.GCP.Regions, a.cfg.GCP.Zones
+		switch {
+		case a.cfg.GCP.Parent != "" && req.Project == "":
+			scope.Parent, scope.Projects = a.cfg.GCP.Parent, scanner.ProjectFilter{Include: a.cfg.GCP.Include, Exclude: a.cfg.GCP.Exclude, Labels: a.cfg.GCP.Labels}
+		case a.cfg.GCP.Parent != "" && len(a.cfg.GCP.Labels) > 0:
+			// Project labels cannot be checked without asking GCP.
+			bad["scope.project"] = "cannot be checked against the configured labels; leave it out to plan across the parent's projects"
+		case a.cfg.GCP.Parent != "":
+			if !(scanner.ProjectFilter{Include: a.cfg.GCP.Include, Exclude: a.cfg.GCP.Exclude}).Match(req.Project, nil) {
+				bad["scope.project"] = "not a project the config scans"
+			}
+		case !slices.Contains(a.cfg.GCP.Projects, req.Project):
+			bad["scope.project"] = "not a project the config scans"
+		}
+	default:
+		bad["detector"] = string(d.Provider) + " is not configured"
+	}
+	switch d.Scope {
+	case scanner.Regional:
+		scope.Region = configuredLocation(bad, "scope.region", req.Region, regions, scanner.AllRegions)
+	case scanner.Zonal:
+		scope.Zone = configuredLocation(bad, "scope.zone", req.Zone, zones, scanner.AllZones)
+	default:
+		if d.Provider == scanner.AWS {
+			scope.Region = scanner.AllRegions // global detectors list from every region
+		}
+	}
+	if d.Scope != scanner.Regional && req.Region != "" {
+		bad["scope.region"] = "not a location of " + d.Name
+	}
+	if d.Scope != scanner.Zonal && req.Zone != "" {
+		bad["scope.zone"] = "not a location of " + d.Name
+	}
+	if len(bad) > 0 {
+		return scanner.Scope{}, &scanner.Error{Kind: scanner.KindInvalid, Err: bad}
+	}
+	return scope, nil
+}
+
+// configuredLocation checks the region or zone a plan asks for, all when
+// empty, against those the config scans, all when it names none.
+func configuredLocation(bad paramError, name, value string, configured []string, all string) string {
+	if value == "" {
+		value = all
+	}
+	if len(configured) > 0 && !slices.Contains(configured, all) && !slices.Contains(configured, value) {
+		bad[name] = strconv.Quote(value) + " is not a " + strings.TrimPrefix(name, "scope.") + " the config scans"
+	}
+	return value
+}
+
+// orNone returns values, or the empty value when there are none: the
+// default credentials' account when the config names no accounts.
+func orNone(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+func (a plansAPI) list(c *gin.Context) {
+	plans, err := a.plans.List(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+func (a plansAPI) get(c *gin.Context) {
+	plan, err := a.plans.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// decide records an approval or rejection by the named user.
+func (a plansAPI) decide(record func(ctx context.Context, id, user, comment string) (remediation.Plan, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req decision
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortWithError(c, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("body: %w", err)})
+			return
+		}
+		plan, err := record(c.Request.Context(), c.Param("id"), req.User, req.Comment)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+// execute carries out an approved plan. The plan is returned with its
+// outcome even when some targets failed.
+func (a plansAPI) execute(c *gin.Context) {
+	plan, err := a.plans.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil && plan.ID == "" {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/remediation"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// planned is the scope aws/tagged last ran with.
+var planned scanner.Scope
+
+func init() {
+	// aws/tagged finds two volumes, one tagged to be kept, and leaves out
+	// those its scope's tag rules exclude, as every detector does.
+	scanner.Register(scanner.Detector{
+		Name:     "aws/tagged",
+		Provider: scanner.AWS,
+		Scope:    scanner.Regional,
+		Scan: func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+			planned = scope
+			var result scanner.Result
+			for _, f := range []scanner.Finding{
+				{ResourceID: "vol-keep", ResourceKind: "ebs", Region: scope.Region, Tags: map[string]string{"finops:keep": "true"}},
+				{ResourceID: "vol-old", ResourceKind: "ebs", Region: scope.Region},
+			} {
+				if !scope.Tags.Excludes(f.Tags) {
+					result.Findings = append(result.Findings, f)
+				}
+			}
+			return result, nil
+		},
+	})
+}
+
+func newPlans(t *testing.T) *gin.Engine {
+	plans, err := remediation.NewFileStore(filepath.Join(t.TempDir(), "plans"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		AWS:  &config.AWS{Role: "FinOpsRemediation", Regions: []string{"us-east-1"}},
+		Tags: scanner.TagRules{Exclude: map[string]string{"finops:keep": "true"}},
+	}
+	r := gin.New()
+	plansAPI{plans: remediation.NewService(plans, remediation.Executors(io.Discard)), cfg: cfg}.register(r)
+	return r
+}
+
+func TestCreatePlanScansAsConfigured(t *testing.T) {
+	r := newPlans(t)
+	var body struct {
+		Error string       `json:"error"`
+		Kind  scanner.Kind `json:"kind"`
+	}
+
+	// The tag rules of the config apply: the kept volume is not a finding.
+	payload := `{"detector": "aws/tagged", "scope": {"region": "us-east-1"}, "resource_ids": ["vol-keep"], "requested_by": "alice"}`
+	if code := do(t, r, http.MethodPost, "/plans", payload, &body); code != http.StatusBadRequest || !strings.Contains(body.Error, "vol-keep") {
+		t.Errorf("planning an excluded volume: %d %+v, want it refused", code, body)
+	}
+	if planned.Role != "FinOpsRemediation" || planned.Tags.Exclude["finops:keep"] != "true" {
+		t.Errorf("scanned with %+v, want the config's role and tag rules", planned)
+	}
+
+	for name, scope := range map[string]string{
+		"role":       `{"region": "us-east-1", "role": "Admin"}`,
+		"tags":       `{"region": "us-east-1", "tags": {"include": {"team": "x"}}}`,
+		"region":     `{"region": "eu-west-1"}`,
+		"account":    `{"region": "us-east-1", "account": "123456789012"}`,
+		"all region": `{}`,
+	} {
+		planned = scanner.Scope{}
+		payload := `{"detector": "aws/tagged", "scope": ` + scope + `, "requested_by": "alice"}`
+		if code := do(t, r, http.MethodPost, "/plans", payload, &body); code != http.StatusBadRequest || body.Kind != scanner.KindInvalid {
+			t.Errorf("%s: %d %+v, want invalid", name, code, body)
+		}
+		if planned.Region != "" {
+			t.Errorf("%s: scanned %+v before refusing", name, planned)
+		}
+	}
+}
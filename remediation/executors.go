@@ -0,0 +1,103 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	aws_unused_resources "github.com/sawlemon/unused-cloud-resources/aws_unused_resources"
+	unused_gcp_resources "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Executors returns the executors of every action, backed by the provider
+// packages. Released GCP addresses are audited to audit; gcpOpts override
+// how they are reached.
+func Executors(audit io.Writer, gcpOpts ...unused_gcp_resources.Option) map[Action]Executor {
+	return map[Action]Executor{
+		DeleteVolumes: func(ctx context.Context, plan Plan, dryRun bool) (any, error) {
+			return perAccountRegion(ctx, plan, dryRun, func(ctx context.Context, region string, ids []string, opts []aws_unused_resources.Option) ([]aws_unused_resources.VolumeAction, error) {
+				return aws_unused_resources.SnapshotAndDeleteVolumes(ctx, region, ids, dryRun, opts...)
+			})
+		},
+		StopInstances: func(ctx context.Context, plan Plan, dryRun bool) (any, error) {
+			return perAccountRegion(ctx, plan, dryRun, func(ctx context.Context, region string, ids []string, opts []aws_unused_resources.Option) ([]aws_unused_resources.InstanceAction, error) {
+				return aws_unused_resources.StopInstances(ctx, region, aws_unused_resources.StopRequest{
+					InstanceIDs: ids,
+					By:          plan.RequestedBy + ", approved by " + plan.DecidedBy,
+					Reason:      "plan " + plan.ID + ": " + plan.Reason,
+					DryRun:      dryRun,
+				}, opts...)
+			})
+		},
+		ReleaseAddresses: func(ctx context.Context, plan Plan, dryRun bool) (any, error) {
+			var all []unused_gcp_resources.AddressAction
+			var errs []error
+			for _, g := range groupTargets(plan.Targets, func(f scanner.Finding) [2]string { return [2]string{f.Project} }) {
+				refs := make([]string, 0, len(g.targets))
+				for _, f := range g.targets {
+					refs = append(refs, f.Region+"/"+f.ResourceID)
+				}
+				actions, err := unused_gcp_resources.ReleaseAddresses(ctx, g.key[0], refs, dryRun, audit, gcpOpts...)
+				all = append(all, actions...)
+				if err != nil {
+					if dryRun {
+						return nil, err
+					}
+					errs = append(errs, err)
+				}
+			}
+			return all, errors.Join(errs...)
+		},
+	}
+}
+
+// perAccountRegion runs an AWS remediation once per account and region of
+// the plan's targets. A failed dry run stops at once; a failed execution
+// goes on with the remaining groups.
+func perAccountRegion[T any](ctx context.Context, plan Plan, dryRun bool, run func(ctx context.Context, region string, ids []string, opts []aws_unused_resources.Option) ([]T, error)) ([]T, error) {
+	var all []T
+	var errs []error
+	for _, g := range groupTargets(plan.Targets, func(f scanner.Finding) [2]string { return [2]string{f.Account, f.Region} }) {
+		ids := make([]string, 0, len(g.targets))
+		for _, f := range g.targets {
+			ids = append(ids, f.ResourceID)
+		}
+		opts, err := aws_unused_resources.AccountOptions(ctx, g.key[0], plan.Role)
+		if err == nil {
+			var actions []T
+			actions, err = run(ctx, g.key[1], ids, opts)
+			all = append(all, actions...)
+		}
+		if err != nil {
+			if dryRun {
+				return nil, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return all, errors.Join(errs...)
+}
+
+// targetGroup is the targets sharing a key, e.g. an account and region.
+type targetGroup struct {
+	key     [2]string
+	targets []scanner.Finding
+}
+
+// groupTargets groups targets by key, in the order keys first appear.
+func groupTargets(targets []scanner.Finding, key func(scanner.Finding) [2]string) []targetGroup {
+	var groups []targetGroup
+	index := map[[2]string]int{}
+	for _, f := range targets {
+		k := key(f)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, targetGroup{key: k})
+		}
+		groups[i].targets = append(groups[i].targets, f)
+	}
+	return groups
+}
@@ -0,0 +1,69 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	unused_gcp_resources "github.com/sawlemon/unused-cloud-resources/gcp_unused_resources"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGroupTargets(t *testing.T) {
+	targets := []scanner.Finding{
+		{ResourceID: "vol-1", Account: "111111111111", Region: "us-east-1"},
+		{ResourceID: "vol-2", Account: "222222222222", Region: "us-east-1"},
+		{ResourceID: "vol-3", Account: "111111111111", Region: "us-east-1"},
+		{ResourceID: "vol-4", Account: "111111111111", Region: "eu-west-1"},
+	}
+
+	groups := groupTargets(targets, func(f scanner.Finding) [2]string { return [2]string{f.Account, f.Region} })
+
+	var got [][]string
+	for _, g := range groups {
+		var ids []string
+		for _, f := range g.targets {
+			ids = append(ids, f.ResourceID)
+		}
+		got = append(got, ids)
+	}
+	if want := [][]string{{"vol-1", "vol-3"}, {"vol-2"}, {"vol-4"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+}
+
+// fakeAddresses records the projects addresses are looked up and released in.
+type fakeAddresses struct {
+	unused_gcp_resources.AddressesAPI
+	released []string // "project/region/name"
+}
+
+func (f *fakeAddresses) GetAddress(ctx context.Context, req *computepb.GetAddressRequest) (*computepb.Address, error) {
+	return &computepb.Address{Name: proto.String(req.GetAddress())}, nil
+}
+
+func (f *fakeAddresses) DeleteAddress(ctx context.Context, req *computepb.DeleteAddressRequest) error {
+	f.released = append(f.released, req.GetProject()+"/"+req.GetRegion()+"/"+req.GetAddress())
+	return nil
+}
+
+func TestReleaseAddressesPerProject(t *testing.T) {
+	client := &fakeAddresses{}
+	var audit bytes.Buffer
+	release := Executors(&audit, unused_gcp_resources.WithAddressesClient(client))[ReleaseAddresses]
+	plan := Plan{ID: "plan-1", Action: ReleaseAddresses, Targets: []scanner.Finding{
+		{ResourceID: "ip-1", Project: "finops-prod", Region: "us-central1"},
+		{ResourceID: "ip-2", Project: "finops-dev", Region: "us-central1"},
+	}}
+
+	if _, err := release(context.Background(), plan, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"finops-prod/us-central1/ip-1", "finops-dev/us-central1/ip-2"}; !reflect.DeepEqual(client.released, want) {
+		t.Errorf("released %v, want %v", client.released, want)
+	}
+}
@@ -0,0 +1,138 @@
+// Package remediation turns the findings of a scan into remediation plans
+// that must be approved by a named user before they are executed.
+package remediation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Status is where a plan is in the approval workflow.
+type Status string
+
+const (
+	Pending   Status = "pending"   // waiting for approval
+	Approved  Status = "approved"  // may be executed
+	Rejected  Status = "rejected"  // will never be executed
+	Executing Status = "executing" // being executed
+	Executed  Status = "executed"  // every target was remediated
+	Failed    Status = "failed"    // executed, but some targets were left in place
+)
+
+// Action is what a plan does to its targets.
+type Action string
+
+const (
+	DeleteVolumes    Action = "snapshot-delete-volumes" // aws/ebs: snapshot, then delete
+	StopInstances    Action = "stop-instances"          // aws/ec2: stop or hibernate
+	ReleaseAddresses Action = "release-addresses"       // gcp/ips: release
+)
+
+// actions maps the detectors whose findings can be remediated to the action
+// that remediates them.
+var actions = map[string]Action{
+	"aws/ebs": DeleteVolumes,
+	"aws/ec2": StopInstances,
+	"gcp/ips": ReleaseAddresses,
+}
+
+// ActionFor returns the action remediating findings of the named detector.
+func ActionFor(detector string) (Action, bool) {
+	a, ok := actions[detector]
+	return a, ok
+}
+
+// Plan is a remediation of the findings of one scan.
+type Plan struct {
+	ID          string            `json:"id"`
+	Action      Action            `json:"action"`
+	Detector    string            `json:"detector"`
+	Role        string            `json:"role,omitempty"` // role assumed in the accounts of the targets
+	Targets     []scanner.Finding `json:"targets"`        // findings to remediate, with their account, region or project
+	Reason      string            `json:"reason,omitempty"`
+	Status      Status            `json:"status"`
+	RequestedBy string            `json:"requested_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	DecidedBy   string            `json:"decided_by,omitempty"` // who approved or rejected the plan
+	DecidedAt   time.Time         `json:"decided_at,omitempty"`
+	Comment     string            `json:"comment,omitempty"` // why it was approved or rejected
+	ExecutedAt  time.Time         `json:"executed_at,omitempty"`
+	Preview     json.RawMessage   `json:"preview,omitempty"` // dry run output when the plan was created
+	Result      json.RawMessage   `json:"result,omitempty"`  // actions taken when the plan was executed
+	Error       string            `json:"error,omitempty"`
+}
+
+// Request asks for a plan remediating the findings of a scan.
+type Request struct {
+	Detector    string            `json:"detector"`
+	Role        string            `json:"role"`
+	Findings    []scanner.Finding `json:"findings"`
+	RequestedBy string            `json:"requested_by"`
+	Reason      string            `json:"reason"`
+}
+
+// SelectFindings returns the findings with the given resource IDs, or all
+// of them when ids is empty. Asking for a resource the scan did not find
+// unused is an error, so only unused resources are ever remediated.
+func SelectFindings(findings []scanner.Finding, ids []string) ([]scanner.Finding, error) {
+	if len(ids) == 0 {
+		return findings, nil
+	}
+	byID := make(map[string]scanner.Finding, len(findings))
+	for _, f := range findings {
+		byID[f.ResourceID] = f
+	}
+	selected := make([]scanner.Finding, 0, len(ids))
+	for _, id := range ids {
+		f, ok := byID[id]
+		if !ok {
+			return nil, &scanner.Error{Kind: scanner.KindInvalid, Resource: id, Err: errors.New("not among the scan's findings")}
+		}
+		selected = append(selected, f)
+	}
+	return selected, nil
+}
+
+// newPlan builds a pending plan from req.
+func newPlan(req Request, now time.Time) (Plan, error) {
+	action, ok := ActionFor(req.Detector)
+	if !ok {
+		return Plan{}, fmt.Errorf("findings of %s cannot be remediated", req.Detector)
+	}
+	if req.RequestedBy == "" {
+		return Plan{}, fmt.Errorf("requested_by is required")
+	}
+	if len(req.Findings) == 0 {
+		return Plan{}, fmt.Errorf("no findings to remediate")
+	}
+	id, err := newID()
+	if err != nil {
+		return Plan{}, err
+	}
+	return Plan{
+		ID:          id,
+		Action:      action,
+		Detector:    req.Detector,
+		Role:        req.Role,
+		Targets:     req.Findings,
+		Reason:      req.Reason,
+		Status:      Pending,
+		RequestedBy: req.RequestedBy,
+		CreatedAt:   now,
+	}, nil
+}
+
+// newID returns a random plan ID.
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,149 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Executor carries out a plan, or with dryRun only checks that it could,
+// and returns the actions taken for the record.
+type Executor func(ctx context.Context, plan Plan, dryRun bool) (any, error)
+
+// Service runs the approval workflow: a plan is created pending, approved or
+// rejected by a named user other than the one who requested it, and only an
+// approved plan is executed, once.
+type Service struct {
+	store     Store
+	executors map[Action]Executor
+	now       func() time.Time
+
+	mu sync.Mutex // serializes status changes
+}
+
+// NewService returns a workflow keeping plans in store and executing them
+// with executors.
+func NewService(store Store, executors map[Action]Executor) *Service {
+	return &Service{store: store, executors: executors, now: time.Now}
+}
+
+// Create checks the requested remediation with a dry run and stores it as a
+// pending plan. Nothing is stored when the dry run fails.
+func (s *Service) Create(ctx context.Context, req Request) (Plan, error) {
+	plan, err := newPlan(req, s.now().UTC())
+	if err != nil {
+		return Plan{}, invalid(err)
+	}
+	exec, ok := s.executors[plan.Action]
+	if !ok {
+		return Plan{}, invalid(fmt.Errorf("no executor for %s", plan.Action))
+	}
+	preview, err := exec(ctx, plan, true)
+	if err != nil {
+		return Plan{}, err
+	}
+	if plan.Preview, err = json.Marshal(preview); err != nil {
+		return Plan{}, err
+	}
+	if err := s.store.Put(ctx, plan); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// Get returns the plan with the given ID.
+func (s *Service) Get(ctx context.Context, id string) (Plan, error) {
+	return s.store.Get(ctx, id)
+}
+
+// List returns every plan, newest first.
+func (s *Service) List(ctx context.Context) ([]Plan, error) {
+	return s.store.List(ctx)
+}
+
+// Approve lets the plan be executed.
+func (s *Service) Approve(ctx context.Context, id, user, comment string) (Plan, error) {
+	return s.decide(ctx, id, user, comment, Approved)
+}
+
+// Reject closes the plan without executing it.
+func (s *Service) Reject(ctx context.Context, id, user, comment string) (Plan, error) {
+	return s.decide(ctx, id, user, comment, Rejected)
+}
+
+// decide records user's decision on a pending plan.
+func (s *Service) decide(ctx context.Context, id, user, comment string, status Status) (Plan, error) {
+	if user == "" {
+		return Plan{}, invalid(errors.New("user is required"))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plan, err := s.store.Get(ctx, id)
+	if err != nil {
+		return Plan{}, err
+	}
+	if plan.Status != Pending {
+		return Plan{}, conflict(id, "plan is %s, only pending plans can be approved or rejected", plan.Status)
+	}
+	if user == plan.RequestedBy {
+		return Plan{}, conflict(id, "plan was requested by %s, who cannot also decide on it", user)
+	}
+	plan.Status = status
+	plan.DecidedBy = user
+	plan.DecidedAt = s.now().UTC()
+	plan.Comment = comment
+	if err := s.store.Put(ctx, plan); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// Execute carries out an approved plan and records the outcome. A plan is
+// executed at most once; it ends Executed, or Failed when any target was
+// left in place.
+func (s *Service) Execute(ctx context.Context, id string) (Plan, error) {
+	s.mu.Lock()
+	plan, err := s.store.Get(ctx, id)
+	if err == nil && plan.Status != Approved {
+		err = conflict(id, "plan is %s, only approved plans can be executed", plan.Status)
+	}
+	if err == nil {
+		plan.Status = Executing
+		err = s.store.Put(ctx, plan)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	result, execErr := s.executors[plan.Action](ctx, plan, false)
+	plan.Status = Executed
+	plan.ExecutedAt = s.now().UTC()
+	if execErr != nil {
+		plan.Status = Failed
+		plan.Error = execErr.Error()
+	}
+	if plan.Result, err = json.Marshal(result); err != nil {
+		return Plan{}, err
+	}
+	// Record the outcome even if the request was cancelled meanwhile.
+	if err := s.store.Put(context.WithoutCancel(ctx), plan); err != nil {
+		return Plan{}, err
+	}
+	return plan, execErr
+}
+
+// invalid is the error for a request the workflow cannot accept.
+func invalid(err error) error {
+	return &scanner.Error{Kind: scanner.KindInvalid, Err: err}
+}
+
+// conflict is the error for a status change the plan does not allow.
+func conflict(id, format string, args ...any) error {
+	return &scanner.Error{Kind: scanner.KindConflict, Resource: id, Err: fmt.Errorf(format, args...)}
+}
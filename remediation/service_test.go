@@ -0,0 +1,167 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// fakeExecutor records its calls and fails the dry run or the execution on demand.
+type fakeExecutor struct {
+	calls      []bool // dryRun of every call
+	dryRunErr  error
+	executeErr error
+}
+
+func (f *fakeExecutor) run(ctx context.Context, plan Plan, dryRun bool) (any, error) {
+	f.calls = append(f.calls, dryRun)
+	if dryRun {
+		return []string{"would remediate " + plan.Targets[0].ResourceID}, f.dryRunErr
+	}
+	return []string{"remediated " + plan.Targets[0].ResourceID}, f.executeErr
+}
+
+func newTestService(t *testing.T, exec *fakeExecutor) *Service {
+	t.Helper()
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(store, map[Action]Executor{DeleteVolumes: exec.run})
+}
+
+var volumeRequest = Request{
+	Detector:    "aws/ebs",
+	Findings:    []scanner.Finding{{ResourceID: "vol-1", Region: "us-east-1"}},
+	RequestedBy: "alice",
+	Reason:      "unattached for 90 days",
+}
+
+func TestApproveThenExecute(t *testing.T) {
+	exec := &fakeExecutor{}
+	s := newTestService(t, exec)
+	ctx := context.Background()
+
+	plan, err := s.Create(ctx, volumeRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Status != Pending || plan.Action != DeleteVolumes || string(plan.Preview) != `["would remediate vol-1"]` {
+		t.Errorf("created plan = %+v", plan)
+	}
+
+	if _, err := s.Execute(ctx, plan.ID); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("executing a pending plan: err = %v, want a conflict", err)
+	}
+	if _, err := s.Approve(ctx, plan.ID, "alice", ""); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("self approval: err = %v, want a conflict", err)
+	}
+	if _, err := s.Approve(ctx, plan.ID, "", ""); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("anonymous approval: err = %v, want invalid", err)
+	}
+
+	approved, err := s.Approve(ctx, plan.ID, "bob", "looks right")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != Approved || approved.DecidedBy != "bob" || approved.DecidedAt.IsZero() {
+		t.Errorf("approved plan = %+v", approved)
+	}
+
+	done, err := s.Execute(ctx, plan.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != Executed || string(done.Result) != `["remediated vol-1"]` || done.ExecutedAt.IsZero() {
+		t.Errorf("executed plan = %+v", done)
+	}
+	if len(exec.calls) != 2 || !exec.calls[0] || exec.calls[1] {
+		t.Errorf("executor calls = %v, want a dry run then one execution", exec.calls)
+	}
+	if _, err := s.Execute(ctx, plan.ID); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("second execution: err = %v, want a conflict", err)
+	}
+
+	stored, err := s.Get(ctx, plan.ID)
+	if err != nil || stored.Status != Executed {
+		t.Errorf("stored plan = %+v, err = %v", stored, err)
+	}
+}
+
+func TestRejectedPlanIsNeverExecuted(t *testing.T) {
+	exec := &fakeExecutor{}
+	s := newTestService(t, exec)
+	ctx := context.Background()
+
+	plan, err := s.Create(ctx, volumeRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reject(ctx, plan.ID, "bob", "still needed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Approve(ctx, plan.ID, "carol", ""); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("approving a rejected plan: err = %v, want a conflict", err)
+	}
+	if _, err := s.Execute(ctx, plan.ID); !errors.Is(err, scanner.ErrConflict) {
+		t.Errorf("executing a rejected plan: err = %v, want a conflict", err)
+	}
+	if len(exec.calls) != 1 {
+		t.Errorf("executor calls = %v, want only the dry run", exec.calls)
+	}
+}
+
+func TestCreateFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		exec *fakeExecutor
+		want error
+	}{
+		{"unremediable detector", Request{Detector: "aws/s3", Findings: volumeRequest.Findings, RequestedBy: "alice"}, &fakeExecutor{}, scanner.ErrInvalid},
+		{"no requester", Request{Detector: "aws/ebs", Findings: volumeRequest.Findings}, &fakeExecutor{}, scanner.ErrInvalid},
+		{"no findings", Request{Detector: "aws/ebs", RequestedBy: "alice"}, &fakeExecutor{}, scanner.ErrInvalid},
+		{"dry run fails", volumeRequest, &fakeExecutor{dryRunErr: scanner.ErrPermission}, scanner.ErrPermission},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.exec)
+			if _, err := s.Create(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if plans, _ := s.List(context.Background()); len(plans) != 0 {
+				t.Errorf("stored %d plans, want none", len(plans))
+			}
+		})
+	}
+}
+
+func TestFailedExecutionIsRecorded(t *testing.T) {
+	exec := &fakeExecutor{executeErr: scanner.ErrThrottled}
+	s := newTestService(t, exec)
+	ctx := context.Background()
+	plan, _ := s.Create(ctx, volumeRequest)
+	s.Approve(ctx, plan.ID, "bob", "")
+
+	got, err := s.Execute(ctx, plan.ID)
+
+	if !errors.Is(err, scanner.ErrThrottled) || got.Status != Failed || got.Error == "" {
+		t.Errorf("plan = %+v, err = %v", got, err)
+	}
+}
+
+func TestSelectFindings(t *testing.T) {
+	findings := []scanner.Finding{{ResourceID: "vol-1"}, {ResourceID: "vol-2"}}
+
+	if got, err := SelectFindings(findings, []string{"vol-2"}); err != nil || len(got) != 1 || got[0].ResourceID != "vol-2" {
+		t.Errorf("SelectFindings = %+v, %v", got, err)
+	}
+	if got, _ := SelectFindings(findings, nil); len(got) != 2 {
+		t.Errorf("SelectFindings(nil) = %+v, want every finding", got)
+	}
+	if _, err := SelectFindings(findings, []string{"vol-in-use"}); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("err = %v, want invalid for a resource that is not unused", err)
+	}
+}
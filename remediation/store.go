@@ -0,0 +1,102 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Store persists plans.
+type Store interface {
+	Put(ctx context.Context, plan Plan) error
+	Get(ctx context.Context, id string) (Plan, error) // a *scanner.Error of KindNotFound for unknown IDs
+	List(ctx context.Context) ([]Plan, error)         // newest first
+}
+
+// FileStore keeps one JSON file per plan in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store keeping plans in dir, which is created if missing.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put writes the plan, replacing any earlier version. The file is written
+// under a temporary name and renamed so readers never see a partial plan.
+func (s *FileStore) Put(ctx context.Context, plan Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".plan-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(plan.ID))
+}
+
+// Get reads the plan with the given ID.
+func (s *FileStore) Get(ctx context.Context, id string) (Plan, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return Plan{}, notFound(id)
+	}
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Plan{}, notFound(id)
+	}
+	if err != nil {
+		return Plan{}, err
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return Plan{}, fmt.Errorf("plan %s: %w", id, err)
+	}
+	return plan, nil
+}
+
+// List reads every plan, newest first.
+func (s *FileStore) List(ctx context.Context) ([]Plan, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]Plan, 0, len(paths))
+	for _, p := range paths {
+		plan, err := s.Get(ctx, strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.After(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+func (s *FileStore) path(id string) string { return filepath.Join(s.dir, id+".json") }
+
+// notFound is the error for an unknown plan ID.
+func notFound(id string) error {
+	return &scanner.Error{Kind: scanner.KindNotFound, Resource: id, Err: errors.New("no such plan")}
+}
@@ -0,0 +1,43 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	older := Plan{ID: "a1", Status: Pending, CreatedAt: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)}
+	newer := Plan{ID: "b2", Status: Pending, CreatedAt: time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC)}
+	for _, p := range []Plan{older, newer} {
+		if err := store.Put(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	older.Status = Approved
+	if err := store.Put(ctx, older); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(ctx, "a1")
+	if err != nil || got.Status != Approved {
+		t.Errorf("Get = %+v, %v", got, err)
+	}
+	plans, err := store.List(ctx)
+	if err != nil || len(plans) != 2 || plans[0].ID != "b2" {
+		t.Errorf("List = %+v, %v, want b2 then a1", plans, err)
+	}
+	for _, id := range []string{"missing", "../a1", ""} {
+		if _, err := store.Get(ctx, id); !errors.Is(err, scanner.ErrNotFound) {
+			t.Errorf("Get(%q): err = %v, want not found", id, err)
+		}
+	}
+}
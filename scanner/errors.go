@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies why a cloud API call failed.
+type Kind string
+
+const (
+	KindUnknown    Kind = "unknown"
+	KindAuth       Kind = "auth"       // credentials missing, invalid or expired
+	KindThrottling Kind = "throttling" // rate limited by the provider
+	KindNotFound   Kind = "not_found"  // the resource or scope does not exist
+	KindPermission Kind = "permission" // authenticated but not allowed
+	KindConflict   Kind = "conflict"   // the resource is not in a state the operation allows
+	KindInvalid    Kind = "invalid"    // the request itself is malformed
+)
+
+// Error is returned by detectors when a cloud API call fails.
+type Error struct {
+	Kind     Kind   // classification of the failure
+	Op       string // API operation, e.g. "DescribeVolumes"
+	Resource string // resource the call was about, if any
+	Err      error  // underlying SDK error
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Kind)
+	if e.Op != "" {
+		msg = e.Op + ": " + msg
+	}
+	if e.Resource != "" {
+		msg += " (" + e.Resource + ")"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is the sentinel for e's kind, so callers can
+// write errors.Is(err, scanner.ErrThrottled).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Op == "" && t.Resource == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// Sentinels matching each Kind with errors.Is.
+var (
+	ErrAuth       = &Error{Kind: KindAuth}
+	ErrThrottled  = &Error{Kind: KindThrottling}
+	ErrNotFound   = &Error{Kind: KindNotFound}
+	ErrPermission = &Error{Kind: KindPermission}
+	ErrConflict   = &Error{Kind: KindConflict}
+	ErrInvalid    = &Error{Kind: KindInvalid}
+)
+
+// KindOf returns the Kind of err, or KindUnknown when it is not an *Error.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindUnknown
+}
+
+// Skipped records a resource that could not be evaluated. Skipped resources
+// are left out of TotalInstancesCount so they do not skew the KPI.
+type Skipped struct {
+	ResourceID string `json:"resource_id"`
+	Region     string `json:"region,omitempty"`
+	Account    string `json:"account,omitempty"`
+	Project    string `json:"project,omitempty"`
+	Kind       Kind   `json:"kind"`
+	Reason     string `json:"reason"`
+}
+
+// Skip builds a Skipped entry from the error that prevented evaluation.
+func Skip(resourceID string, err error) Skipped {
+	return Skipped{ResourceID: resourceID, Kind: KindOf(err), Reason: fmt.Sprint(err)}
+}
@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOut calls scan for indexes 0..n-1 using at most 'workers' goroutines and
+// returns the results and errors by index, so callers can merge in order.
+func FanOut(
+	ctx context.Context,
+	n int,
+	workers int,
+	scan func(ctx context.Context, i int) (Result, error),
+) ([]Result, []error) {
+	results := make([]Result, n)
+	errs := make([]error, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = scan(ctx, i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, errs
+}
@@ -0,0 +1,3 @@
+module github.com/sawlemon/unused-cloud-resources/scanner
+
+go 1.22.5
@@ -0,0 +1,155 @@
+// Package pricing estimates what unused resources cost from an offline
+// catalog of list prices. The catalog ships embedded in the binary and can
+// be refreshed from the AWS Price List and GCP Cloud Billing Catalog APIs
+// (see cmd/pricing).
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// HoursPerMonth is the number of hours AWS and GCP bill in an average month.
+const HoursPerMonth = 730
+
+// Units prices are quoted in.
+const (
+	UnitGBMonth   = "GB-Mo"   // per GB (GiB) of provisioned storage per month
+	UnitIOPSMonth = "IOPS-Mo" // per provisioned IOPS per month
+	UnitHour      = "Hrs"     // per hour the resource exists
+	UnitLCUHour   = "LCU-Hrs" // per load balancer capacity unit hour
+)
+
+// Price is one list price in USD.
+type Price struct {
+	Provider scanner.Provider `json:"provider"`
+	Service  string           `json:"service"`          // ebs, ec2, rds, rds-storage, elb, pd or ip
+	Region   string           `json:"region,omitempty"` // empty for the price used when a region has none of its own
+	SKU      string           `json:"sku"`              // volume type, instance type, "class/engine", disk type, ...
+	Unit     string           `json:"unit"`
+	USD      float64          `json:"usd"`
+}
+
+// key identifies a price in the catalog index.
+type key struct {
+	provider scanner.Provider
+	service  string
+	region   string
+	sku      string
+	unit     string
+}
+
+func (p Price) key() key { return key{p.Provider, p.Service, p.Region, p.SKU, p.Unit} }
+
+// Catalog is a set of list prices.
+type Catalog struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Prices    []Price   `json:"prices"`
+
+	index map[key]float64
+}
+
+//go:embed catalog.json
+var embedded []byte
+
+// Default returns the catalog embedded in the binary.
+func Default() *Catalog {
+	c, err := Parse(embedded)
+	if err != nil {
+		panic("pricing: embedded catalog: " + err.Error())
+	}
+	return c
+}
+
+// Open returns the catalog at path, or the embedded catalog when path is empty.
+func Open(path string) (*Catalog, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	return Load(path)
+}
+
+// Load reads a catalog file written by Save.
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a JSON catalog.
+func Parse(data []byte) (*Catalog, error) {
+	c := &Catalog{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	c.reindex()
+	return c, nil
+}
+
+// Save writes the catalog as JSON, sorted so refreshed files diff cleanly.
+func (c *Catalog) Save(path string) error {
+	sort.Slice(c.Prices, func(i, j int) bool {
+		a, b := c.Prices[i], c.Prices[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.SKU != b.SKU {
+			return a.SKU < b.SKU
+		}
+		return a.Unit < b.Unit
+	})
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Update adds prices to the catalog, replacing any it already has for the
+// same provider, service, region, SKU and unit.
+func (c *Catalog) Update(prices []Price) {
+	pos := make(map[key]int, len(c.Prices))
+	for i, p := range c.Prices {
+		pos[p.key()] = i
+	}
+	for _, p := range prices {
+		if i, ok := pos[p.key()]; ok {
+			c.Prices[i].USD = p.USD
+			continue
+		}
+		pos[p.key()] = len(c.Prices)
+		c.Prices = append(c.Prices, p)
+	}
+	c.UpdatedAt = time.Now().UTC()
+	c.reindex()
+}
+
+// Lookup returns the price for sku in region, falling back to the catalog's
+// region-less price.
+func (c *Catalog) Lookup(provider scanner.Provider, service, region, sku, unit string) (float64, bool) {
+	if usd, ok := c.index[key{provider, service, region, sku, unit}]; ok {
+		return usd, true
+	}
+	usd, ok := c.index[key{provider, service, "", sku, unit}]
+	return usd, ok
+}
+
+func (c *Catalog) reindex() {
+	c.index = make(map[key]float64, len(c.Prices))
+	for _, p := range c.Prices {
+		c.index[p.key()] = p.USD
+	}
+}
@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestDefaultCatalogParses(t *testing.T) {
+	c := Default()
+	if _, ok := c.Lookup(scanner.AWS, "ebs", "us-east-1", "gp3", UnitGBMonth); !ok {
+		t.Error("embedded catalog has no gp3 price")
+	}
+	if _, ok := c.Lookup(scanner.GCP, "ip", "us-central1", "external", UnitHour); !ok {
+		t.Error("embedded catalog has no static IP price")
+	}
+}
+
+func TestLookupPrefersRegionalPrice(t *testing.T) {
+	c := &Catalog{}
+	c.Update([]Price{
+		{Provider: scanner.AWS, Service: "ebs", SKU: "gp3", Unit: UnitGBMonth, USD: 0.08},
+		{Provider: scanner.AWS, Service: "ebs", Region: "eu-central-1", SKU: "gp3", Unit: UnitGBMonth, USD: 0.0952},
+	})
+
+	if got, _ := c.Lookup(scanner.AWS, "ebs", "eu-central-1", "gp3", UnitGBMonth); got != 0.0952 {
+		t.Errorf("eu-central-1 = %v, want 0.0952", got)
+	}
+	if got, _ := c.Lookup(scanner.AWS, "ebs", "us-west-2", "gp3", UnitGBMonth); got != 0.08 {
+		t.Errorf("us-west-2 = %v, want the fallback 0.08", got)
+	}
+	if _, ok := c.Lookup(scanner.AWS, "ebs", "us-west-2", "io2", UnitGBMonth); ok {
+		t.Error("io2 should be unpriced")
+	}
+}
+
+func TestUpdateReplacesAndSaveRoundTrips(t *testing.T) {
+	c := &Catalog{}
+	c.Update([]Price{{Provider: scanner.GCP, Service: "pd", SKU: "pd-ssd", Unit: UnitGBMonth, USD: 0.17}})
+	c.Update([]Price{{Provider: scanner.GCP, Service: "pd", SKU: "pd-ssd", Unit: UnitGBMonth, USD: 0.2}})
+	if len(c.Prices) != 1 {
+		t.Fatalf("Prices = %+v, want a single replaced entry", c.Prices)
+	}
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Lookup(scanner.GCP, "pd", "us-central1", "pd-ssd", UnitGBMonth); got != 0.2 {
+		t.Errorf("loaded pd-ssd = %v, want 0.2", got)
+	}
+	if loaded.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt was not saved")
+	}
+}
@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// gp3Baseline is the IOPS every gp3 volume includes at no extra charge.
+const gp3Baseline = 3000
+
+// lcuNewConnections is the new connections (or flows) per second one LCU
+// covers, by load balancer type; idle load balancers are billed on this
+// dimension.
+var lcuNewConnections = map[string]float64{
+	"application": 25,
+	"network":     800,
+	"gateway":     600,
+}
+
+// Apply sets the estimated monthly cost of every finding in r, and r's total,
+// for findings produced by a detector of the given provider.
+func (c *Catalog) Apply(provider scanner.Provider, r *scanner.Result) {
+	r.MonthlyCost = 0
+	for i := range r.Findings {
+		r.Findings[i].MonthlyCost, _ = c.Estimate(provider, r.Findings[i])
+		r.MonthlyCost += r.Findings[i].MonthlyCost
+	}
+}
+
+// Estimate returns the monthly cost of keeping the resource behind f. It
+// reports false when the catalog has no price for it or the resource kind
+// costs nothing while unused (S3 buckets without objects, VPCs).
+func (c *Catalog) Estimate(provider scanner.Provider, f scanner.Finding) (float64, bool) {
+	attr := f.Attributes
+	switch provider {
+	case scanner.AWS:
+		region := f.Region
+		switch f.ResourceKind {
+		case "ebs":
+			volumeType := attr["volume_type"]
+			storage, ok := c.Lookup(scanner.AWS, "ebs", region, volumeType, UnitGBMonth)
+			if !ok {
+				return 0, false
+			}
+			cost := storage * number(attr["size_gib"])
+			if iops, ok := c.Lookup(scanner.AWS, "ebs", region, volumeType, UnitIOPSMonth); ok {
+				provisioned := number(attr["iops"])
+				if volumeType == "gp3" {
+					provisioned = max(provisioned-gp3Baseline, 0)
+				}
+				cost += iops * provisioned
+			}
+			return cost, true
+		case "ec2":
+			hourly, ok := c.Lookup(scanner.AWS, "ec2", region, attr["instance_type"], UnitHour)
+			return hourly * HoursPerMonth, ok
+		case "rds":
+			hourly, ok := c.Lookup(scanner.AWS, "rds", region, attr["instance_class"]+"/"+attr["engine"], UnitHour)
+			if !ok {
+				return 0, false
+			}
+			cost := hourly * HoursPerMonth
+			if storage, ok := c.Lookup(scanner.AWS, "rds-storage", region, attr["storage_type"], UnitGBMonth); ok {
+				cost += storage * number(attr["storage_gib"])
+			}
+			return cost, true
+		case "lb":
+			hourly, ok := c.Lookup(scanner.AWS, "elb", region, attr["type"], UnitHour)
+			if !ok {
+				return 0, false
+			}
+			cost := hourly * HoursPerMonth
+			perLCU, billed := lcuNewConnections[attr["type"]]
+			if lcu, ok := c.Lookup(scanner.AWS, "elb", region, attr["type"], UnitLCUHour); ok && billed {
+				perSecond := f.Metrics["avg_request_count"] / (24 * 60 * 60)
+				cost += lcu * perSecond / perLCU * HoursPerMonth
+			}
+			return cost, true
+		}
+	case scanner.GCP:
+		region := gcpRegion(f.Region)
+		switch f.ResourceKind {
+		case "disks":
+			storage, ok := c.Lookup(scanner.GCP, "pd", region, attr["disk_type"], UnitGBMonth)
+			return storage * number(attr["size_gb"]), ok
+		case "ips":
+			// Internal addresses are free; an empty type means EXTERNAL.
+			if attr["address_type"] == "INTERNAL" {
+				return 0, false
+			}
+			hourly, ok := c.Lookup(scanner.GCP, "ip", region, "external", UnitHour)
+			return hourly * HoursPerMonth, ok
+		}
+	}
+	return 0, false
+}
+
+// gcpRegion returns the region of a GCP zone ("us-central1-a" becomes
+// "us-central1"); regions and "global" are returned unchanged.
+func gcpRegion(location string) string {
+	if i := strings.LastIndex(location, "-"); i > 0 && len(location)-i == 2 {
+		return location[:i]
+	}
+	return location
+}
+
+// number parses a numeric attribute, treating a missing value as zero.
+func number(value string) float64 {
+	n, _ := strconv.ParseFloat(value, 64)
+	return n
+}
@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestEstimate(t *testing.T) {
+	c := &Catalog{}
+	c.Update([]Price{
+		{Provider: scanner.AWS, Service: "ebs", SKU: "gp3", Unit: UnitGBMonth, USD: 0.08},
+		{Provider: scanner.AWS, Service: "ebs", SKU: "gp3", Unit: UnitIOPSMonth, USD: 0.005},
+		{Provider: scanner.AWS, Service: "ebs", SKU: "io2", Unit: UnitGBMonth, USD: 0.125},
+		{Provider: scanner.AWS, Service: "ebs", SKU: "io2", Unit: UnitIOPSMonth, USD: 0.065},
+		{Provider: scanner.AWS, Service: "ec2", SKU: "t3.micro", Unit: UnitHour, USD: 0.0104},
+		{Provider: scanner.AWS, Service: "rds", SKU: "db.t3.micro/mysql", Unit: UnitHour, USD: 0.017},
+		{Provider: scanner.AWS, Service: "rds-storage", SKU: "gp2", Unit: UnitGBMonth, USD: 0.115},
+		{Provider: scanner.AWS, Service: "elb", SKU: "application", Unit: UnitHour, USD: 0.0225},
+		{Provider: scanner.AWS, Service: "elb", SKU: "application", Unit: UnitLCUHour, USD: 0.008},
+		{Provider: scanner.AWS, Service: "elb", SKU: "network", Unit: UnitHour, USD: 0.0225},
+		{Provider: scanner.AWS, Service: "elb", SKU: "network", Unit: UnitLCUHour, USD: 0.006},
+		{Provider: scanner.GCP, Service: "pd", Region: "europe-west1", SKU: "pd-balanced", Unit: UnitGBMonth, USD: 0.11},
+		{Provider: scanner.GCP, Service: "ip", SKU: "external", Unit: UnitHour, USD: 0.01},
+	})
+
+	tests := []struct {
+		name     string
+		provider scanner.Provider
+		finding  scanner.Finding
+		want     float64
+		priced   bool
+	}{
+		{"gp3 above baseline IOPS", scanner.AWS, scanner.Finding{ResourceKind: "ebs", Attributes: map[string]string{"volume_type": "gp3", "size_gib": "100", "iops": "4000"}}, 100*0.08 + 1000*0.005, true},
+		{"gp3 at baseline IOPS", scanner.AWS, scanner.Finding{ResourceKind: "ebs", Attributes: map[string]string{"volume_type": "gp3", "size_gib": "100", "iops": "3000"}}, 8, true},
+		{"io2", scanner.AWS, scanner.Finding{ResourceKind: "ebs", Attributes: map[string]string{"volume_type": "io2", "size_gib": "10", "iops": "100"}}, 10*0.125 + 100*0.065, true},
+		{"ec2", scanner.AWS, scanner.Finding{ResourceKind: "ec2", Attributes: map[string]string{"instance_type": "t3.micro"}}, 0.0104 * HoursPerMonth, true},
+		{"unpriced ec2", scanner.AWS, scanner.Finding{ResourceKind: "ec2", Attributes: map[string]string{"instance_type": "x2iedn.32xlarge"}}, 0, false},
+		{"rds", scanner.AWS, scanner.Finding{ResourceKind: "rds", Attributes: map[string]string{"instance_class": "db.t3.micro", "engine": "mysql", "storage_type": "gp2", "storage_gib": "20"}}, 0.017*HoursPerMonth + 20*0.115, true},
+		{"idle alb", scanner.AWS, scanner.Finding{ResourceKind: "lb", Attributes: map[string]string{"type": "application"}}, 0.0225 * HoursPerMonth, true},
+		{"alb connections", scanner.AWS, scanner.Finding{ResourceKind: "lb", Attributes: map[string]string{"type": "application"}, Metrics: map[string]float64{"avg_request_count": 86400}}, 0.0225*HoursPerMonth + 0.008/25*HoursPerMonth, true},
+		{"nlb connections", scanner.AWS, scanner.Finding{ResourceKind: "lb", Attributes: map[string]string{"type": "network"}, Metrics: map[string]float64{"avg_request_count": 86400}}, 0.0225*HoursPerMonth + 0.006/800*HoursPerMonth, true},
+		{"vpc", scanner.AWS, scanner.Finding{ResourceKind: "vpc"}, 0, false},
+		{"regional pd price", scanner.GCP, scanner.Finding{ResourceKind: "disks", Region: "europe-west1-b", Attributes: map[string]string{"disk_type": "pd-balanced", "size_gb": "50"}}, 50 * 0.11, true},
+		{"external ip", scanner.GCP, scanner.Finding{ResourceKind: "ips", Region: "global", Attributes: map[string]string{"address_type": "EXTERNAL"}}, 0.01 * HoursPerMonth, true},
+		{"internal ip", scanner.GCP, scanner.Finding{ResourceKind: "ips", Attributes: map[string]string{"address_type": "INTERNAL"}}, 0, false},
+	}
+	for _, tt := range tests {
+		got, priced := c.Estimate(tt.provider, tt.finding)
+		if math.Abs(got-tt.want) > 1e-9 || priced != tt.priced {
+			t.Errorf("%s: Estimate = %v, %v; want %v, %v", tt.name, got, priced, tt.want, tt.priced)
+		}
+	}
+}
+
+func TestApplyTotalsFindings(t *testing.T) {
+	c := &Catalog{}
+	c.Update([]Price{{Provider: scanner.GCP, Service: "pd", SKU: "pd-standard", Unit: UnitGBMonth, USD: 0.04}})
+	r := scanner.Result{Findings: []scanner.Finding{
+		{ResourceKind: "disks", Attributes: map[string]string{"disk_type": "pd-standard", "size_gb": "100"}},
+		{ResourceKind: "disks", Attributes: map[string]string{"disk_type": "pd-standard", "size_gb": "50"}},
+	}}
+
+	c.Apply(scanner.GCP, &r)
+
+	if r.Findings[0].MonthlyCost != 4 || math.Abs(r.MonthlyCost-6) > 1e-9 {
+		t.Errorf("costs = %v, total %v; want 4 and 6", r.Findings[0].MonthlyCost, r.MonthlyCost)
+	}
+}
+
+func TestGCPRegion(t *testing.T) {
+	for location, want := range map[string]string{
+		"us-central1-a": "us-central1",
+		"us-central1":   "us-central1",
+		"global":        "global",
+	} {
+		if got := gcpRegion(location); got != want {
+			t.Errorf("gcpRegion(%q) = %q, want %q", location, got, want)
+		}
+	}
+}
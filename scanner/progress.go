@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Progress counts the resources a scan has found and how many of them it
+// has processed. A detector reports to the Progress in its context, if
+// any; the total grows as each region or project is listed, so it is only
+// final once the scan returns. It is safe for concurrent use, and a nil
+// Progress ignores reports.
+type Progress struct {
+	found, processed atomic.Int64
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context whose scans report to p.
+func WithProgress(ctx context.Context, p *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// ProgressOf returns the Progress scans in ctx report to, or nil.
+func ProgressOf(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+// Found adds n resources to the total.
+func (p *Progress) Found(n int) {
+	if p != nil {
+		p.found.Add(int64(n))
+	}
+}
+
+// Processed adds n resources to those processed.
+func (p *Progress) Processed(n int) {
+	if p != nil {
+		p.processed.Add(int64(n))
+	}
+}
+
+// Counts returns the resources processed and found so far.
+func (p *Progress) Counts() (processed, total int) {
+	if p == nil {
+		return 0, 0
+	}
+	return int(p.processed.Load()), int(p.found.Load())
+}
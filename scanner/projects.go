@@ -0,0 +1,38 @@
+package scanner
+
+import "path"
+
+// ProjectFilter selects GCP projects found under an organization or folder.
+// An empty filter selects every project.
+type ProjectFilter struct {
+	Include []string          `json:"include,omitempty"` // glob patterns on the project ID; empty includes all
+	Exclude []string          `json:"exclude,omitempty"` // glob patterns on the project ID, applied after Include
+	Labels  map[string]string `json:"labels,omitempty"`  // labels a project must carry; an empty value matches any value
+}
+
+// Match reports whether the project with the given ID and labels passes the filter.
+func (f ProjectFilter) Match(id string, labels map[string]string) bool {
+	if len(f.Include) > 0 && !matchAny(f.Include, id) {
+		return false
+	}
+	if matchAny(f.Exclude, id) {
+		return false
+	}
+	for key, want := range f.Labels {
+		if !hasTag(labels, key, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny reports whether id matches one of the glob patterns. Malformed
+// patterns match nothing.
+func matchAny(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package scanner
+
+import "testing"
+
+func TestProjectFilterMatch(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "finops"}
+	tests := []struct {
+		name   string
+		filter ProjectFilter
+		id     string
+		want   bool
+	}{
+		{"empty", ProjectFilter{}, "finops-prod", true},
+		{"include", ProjectFilter{Include: []string{"finops-*"}}, "finops-prod", true},
+		{"not included", ProjectFilter{Include: []string{"data-*"}}, "finops-prod", false},
+		{"excluded", ProjectFilter{Include: []string{"finops-*"}, Exclude: []string{"*-prod"}}, "finops-prod", false},
+		{"label value", ProjectFilter{Labels: map[string]string{"env": "prod"}}, "finops-prod", true},
+		{"label mismatch", ProjectFilter{Labels: map[string]string{"env": "dev"}}, "finops-prod", false},
+		{"label present", ProjectFilter{Labels: map[string]string{"team": ""}}, "finops-prod", true},
+		{"label missing", ProjectFilter{Labels: map[string]string{"owner": ""}}, "finops-prod", false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.id, labels); got != tt.want {
+			t.Errorf("%s: Match(%q) = %v, want %v", tt.name, tt.id, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds detectors by name.
+type Registry struct {
+	mu        sync.RWMutex
+	detectors map[string]Detector
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{detectors: map[string]Detector{}}
+}
+
+// Register adds a detector. It panics on an empty or duplicate name, since
+// registration happens from init functions.
+func (r *Registry) Register(d Detector) {
+	if d.Name == "" || d.Scan == nil {
+		panic("scanner: detector needs a name and a scan function")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.detectors[d.Name]; dup {
+		panic(fmt.Sprintf("scanner: detector %q registered twice", d.Name))
+	}
+	r.detectors[d.Name] = d
+}
+
+// Lookup returns the detector registered under name.
+func (r *Registry) Lookup(name string) (Detector, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.detectors[name]
+	return d, ok
+}
+
+// All returns every registered detector sorted by name.
+func (r *Registry) All() []Detector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]Detector, 0, len(r.detectors))
+	for _, d := range r.detectors {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ByProvider returns the registered detectors for one provider, sorted by name.
+func (r *Registry) ByProvider(p Provider) []Detector {
+	var result []Detector
+	for _, d := range r.All() {
+		if d.Provider == p {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Default is the registry the provider packages register into.
+var Default = NewRegistry()
+
+// Register adds a detector to the default registry.
+func Register(d Detector) { Default.Register(d) }
+
+// Lookup returns a detector from the default registry.
+func Lookup(name string) (Detector, bool) { return Default.Lookup(name) }
+
+// All returns every detector in the default registry.
+func All() []Detector { return Default.All() }
+
+// ByProvider returns the default registry's detectors for one provider.
+func ByProvider(p Provider) []Detector { return Default.ByProvider(p) }
+
+// Select resolves a comma separated list of detector names for one provider.
+// "all" selects every detector of the provider.
+func (r *Registry) Select(p Provider, names string) ([]Detector, error) {
+	if names == "all" {
+		return r.ByProvider(p), nil
+	}
+	var result []Detector
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		d, ok := r.Lookup(name)
+		if !ok || d.Provider != p {
+			return nil, fmt.Errorf("scanner: unknown %s detector %q", p, name)
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// Select resolves detector names against the default registry.
+func Select(p Provider, names string) ([]Detector, error) { return Default.Select(p, names) }
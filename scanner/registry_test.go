@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	var gotParams Parameters
+	r.Register(Detector{
+		Name:         "aws/ec2",
+		Provider:     AWS,
+		ResourceKind: "ec2",
+		Scope:        Regional,
+		Defaults:     Parameters{Threshold: 5, Days: 7},
+		Scan: func(ctx context.Context, scope Scope, params Parameters) (Result, error) {
+			gotParams = params
+			return Result{ResourceIDs: []string{scope.Region}}, nil
+		},
+	})
+	r.Register(Detector{Name: "gcp/disks", Provider: GCP, Scan: func(context.Context, Scope, Parameters) (Result, error) {
+		return Result{}, nil
+	}})
+
+	d, ok := r.Lookup("aws/ec2")
+	if !ok {
+		t.Fatal("aws/ec2 not registered")
+	}
+	res, err := d.New(Scope{Region: "eu-west-1"}, Parameters{Days: 30}).Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ResourceIDs[0] != "eu-west-1" {
+		t.Errorf("scope not passed through: %v", res.ResourceIDs)
+	}
+	if gotParams != (Parameters{Threshold: 5, Days: 30}) {
+		t.Errorf("params = %+v, want defaults merged with overrides", gotParams)
+	}
+
+	if all := r.All(); len(all) != 2 || all[0].Name != "aws/ec2" {
+		t.Errorf("All() = %v", all)
+	}
+	if gcp := r.ByProvider(GCP); len(gcp) != 1 || gcp[0].Name != "gcp/disks" {
+		t.Errorf("ByProvider(GCP) = %v", gcp)
+	}
+	if _, err := r.Select(AWS, "gcp/disks"); err == nil {
+		t.Error("Select should reject a detector of another provider")
+	}
+	if sel, err := r.Select(AWS, "all"); err != nil || len(sel) != 1 {
+		t.Errorf("Select(all) = %v, %v", sel, err)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	r := NewRegistry()
+	d := Detector{Name: "x", Scan: func(context.Context, Scope, Parameters) (Result, error) { return Result{}, nil }}
+	r.Register(d)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic on duplicate registration")
+		}
+	}()
+	r.Register(d)
+}
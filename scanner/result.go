@@ -0,0 +1,98 @@
+package scanner
+
+// Percentage returns unused as a percentage of total, 0 when total is 0.
+func Percentage(unused, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(unused) / float64(total)
+}
+
+// Percentage returns the unused share of the resources scanned, 0 when
+// nothing was scanned.
+func (r Result) Percentage() float64 {
+	return Percentage(r.UnusedInstancesCount, r.TotalInstancesCount)
+}
+
+// InRegion stamps region on every finding and skipped entry that does not
+// already carry one, and records the result's counts as that region's subtotal.
+func (r *Result) InRegion(region string) {
+	for i := range r.Findings {
+		if r.Findings[i].Region == "" {
+			r.Findings[i].Region = region
+		}
+	}
+	for i := range r.Skipped {
+		if r.Skipped[i].Region == "" {
+			r.Skipped[i].Region = region
+		}
+	}
+	r.Regions = map[string]Subtotal{region: {
+		TotalInstancesCount:  r.TotalInstancesCount,
+		UnusedInstancesCount: r.UnusedInstancesCount,
+	}}
+}
+
+// InAccount stamps the account on every finding and skipped entry and records
+// the result's counts as that account's subtotal.
+func (r *Result) InAccount(id, alias string) {
+	for i := range r.Findings {
+		r.Findings[i].Account = id
+		r.Findings[i].AccountAlias = alias
+	}
+	for i := range r.Skipped {
+		r.Skipped[i].Account = id
+	}
+	r.Accounts = map[string]Subtotal{id: {
+		TotalInstancesCount:  r.TotalInstancesCount,
+		UnusedInstancesCount: r.UnusedInstancesCount,
+	}}
+}
+
+// InProject stamps the GCP project on every finding and skipped entry and
+// records the result's counts as that project's subtotal.
+func (r *Result) InProject(id string) {
+	for i := range r.Findings {
+		r.Findings[i].Project = id
+	}
+	for i := range r.Skipped {
+		r.Skipped[i].Project = id
+	}
+	r.Projects = map[string]Subtotal{id: {
+		TotalInstancesCount:  r.TotalInstancesCount,
+		UnusedInstancesCount: r.UnusedInstancesCount,
+	}}
+}
+
+// Merge adds other's resources, counts and subtotals to r.
+func (r *Result) Merge(other Result) {
+	r.ResourceIDs = append(r.ResourceIDs, other.ResourceIDs...)
+	r.TotalInstancesCount += other.TotalInstancesCount
+	r.UnusedInstancesCount += other.UnusedInstancesCount
+	r.ExcludedInstancesCount += other.ExcludedInstancesCount
+	r.MonthlyCost += other.MonthlyCost
+	r.TotalSizeGiB += other.TotalSizeGiB
+	r.UnusedSizeGiB += other.UnusedSizeGiB
+	r.Findings = append(r.Findings, other.Findings...)
+	r.Skipped = append(r.Skipped, other.Skipped...)
+	r.Regions = mergeSubtotals(r.Regions, other.Regions)
+	r.Accounts = mergeSubtotals(r.Accounts, other.Accounts)
+	r.Projects = mergeSubtotals(r.Projects, other.Projects)
+}
+
+// mergeSubtotals adds the subtotals in src to dst, allocating dst if needed.
+func mergeSubtotals(dst, src map[string]Subtotal) map[string]Subtotal {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]Subtotal{}
+	}
+	for key, sub := range src {
+		cur := dst[key]
+		cur.TotalInstancesCount += sub.TotalInstancesCount
+		cur.UnusedInstancesCount += sub.UnusedInstancesCount
+		dst[key] = cur
+	}
+	return dst
+}
@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeRegions(t *testing.T) {
+	east := Result{
+		ResourceIDs:          []string{"vol-1"},
+		TotalInstancesCount:  3,
+		UnusedInstancesCount: 1,
+		Findings:             []Finding{{ResourceID: "vol-1"}},
+		Skipped:              []Skipped{{ResourceID: "vol-9"}},
+	}
+	east.InRegion("us-east-1")
+	west := Result{
+		ResourceIDs:          []string{"vol-2", "vol-3"},
+		TotalInstancesCount:  2,
+		UnusedInstancesCount: 2,
+		Findings:             []Finding{{ResourceID: "vol-2"}, {ResourceID: "vol-3", Region: "us-west-2"}},
+	}
+	west.InRegion("us-west-2")
+
+	var merged Result
+	merged.Merge(east)
+	merged.Merge(west)
+
+	if merged.TotalInstancesCount != 5 || merged.UnusedInstancesCount != 3 {
+		t.Errorf("counts = %d/%d, want 3/5", merged.UnusedInstancesCount, merged.TotalInstancesCount)
+	}
+	if want := []string{"vol-1", "vol-2", "vol-3"}; !reflect.DeepEqual(merged.ResourceIDs, want) {
+		t.Errorf("ResourceIDs = %v, want %v", merged.ResourceIDs, want)
+	}
+	wantRegions := map[string]Subtotal{
+		"us-east-1": {TotalInstancesCount: 3, UnusedInstancesCount: 1},
+		"us-west-2": {TotalInstancesCount: 2, UnusedInstancesCount: 2},
+	}
+	if !reflect.DeepEqual(merged.Regions, wantRegions) {
+		t.Errorf("Regions = %v, want %v", merged.Regions, wantRegions)
+	}
+	var org Result
+	east.InAccount("111111111111", "prod")
+	org.Merge(east)
+	org.Merge(west)
+	if org.Findings[0].AccountAlias != "prod" || org.Accounts["111111111111"].UnusedInstancesCount != 1 {
+		t.Errorf("account not stamped: %+v %v", org.Findings[0], org.Accounts)
+	}
+	if merged.Findings[0].Region != "us-east-1" || merged.Findings[1].Region != "us-west-2" || merged.Skipped[0].Region != "us-east-1" {
+		t.Errorf("regions not stamped: %+v %+v", merged.Findings, merged.Skipped)
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	for _, tc := range []struct {
+		unused, total int
+		want          float64
+	}{
+		{0, 0, 0},
+		{3, 0, 0}, // a detector that counted no resources
+		{1, 4, 25},
+		{2, 3, 100 * 2.0 / 3},
+	} {
+		if got := Percentage(tc.unused, tc.total); got != tc.want {
+			t.Errorf("Percentage(%d, %d) = %v, want %v", tc.unused, tc.total, got, tc.want)
+		}
+	}
+	if got := (Result{TotalInstancesCount: 5, UnusedInstancesCount: 5}).Percentage(); got != 100 {
+		t.Errorf("Result.Percentage = %v, want 100", got)
+	}
+}
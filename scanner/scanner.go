@@ -0,0 +1,145 @@
+// Package scanner defines the interface shared by every unused resource
+// detector and a registry the server, CLI and scheduler use to discover them.
+package scanner
+
+import (
+	"context"
+	"time"
+)
+
+// Provider identifies the cloud a detector talks to.
+type Provider string
+
+const (
+	AWS Provider = "aws"
+	GCP Provider = "gcp"
+)
+
+// ScopeKind describes which location field a detector needs in its Scope.
+type ScopeKind string
+
+const (
+	Global   ScopeKind = "global"   // no location needed
+	Regional ScopeKind = "regional" // Scope.Region is required
+	Zonal    ScopeKind = "zonal"    // Scope.Zone is required
+)
+
+// AllRegions as Scope.Region asks a regional detector to scan every enabled region.
+const AllRegions = "all"
+
+// AllZones as Scope.Zone asks a zonal detector to scan every zone.
+const AllZones = "all"
+
+// AllAccounts as Scope.Account asks an AWS detector to scan every active
+// member account of the organization.
+const AllAccounts = "all"
+
+// Scope is where a scan runs.
+type Scope struct {
+	Account string `json:"account,omitempty"` // AWS account ID
+	Role    string `json:"role,omitempty"`    // role assumed to reach Account
+	Project string `json:"project,omitempty"` // GCP project ID
+	Parent  string `json:"parent,omitempty"`  // GCP organization or folder to scan instead of Project, e.g. "organizations/123"
+	Region  string `json:"region,omitempty"`
+	Zone    string `json:"zone,omitempty"`
+
+	Projects ProjectFilter `json:"projects"` // narrows the projects found under Parent
+	Tags     TagRules      `json:"tags"`     // include and exclude resources by tag or label
+}
+
+// Parameters tune how a detector decides a resource is unused.
+// Zero fields take the detector's defaults.
+type Parameters struct {
+	Threshold float64 `json:"threshold,omitempty"` // metric value below which a resource is unused
+	Days      int     `json:"days,omitempty"`      // look-back window for metrics
+}
+
+// Result is the outcome of a single scan. The field names match the
+// UnusedResourceMetrics type the provider packages have always returned.
+type Result struct {
+	ResourceIDs            []string            `json:"resource_ids"`
+	TotalInstancesCount    int                 `json:"total_count"`
+	UnusedInstancesCount   int                 `json:"unused_count"`
+	ExcludedInstancesCount int                 `json:"excluded_count"`            // resources left out by Scope.Tags, not part of TotalInstancesCount
+	Findings               []Finding           `json:"findings"`                  // one per entry in ResourceIDs
+	Skipped                []Skipped           `json:"skipped,omitempty"`         // resources that could not be evaluated
+	Regions                map[string]Subtotal `json:"regions,omitempty"`         // per-region counts
+	Accounts               map[string]Subtotal `json:"accounts,omitempty"`        // per-account counts
+	Projects               map[string]Subtotal `json:"projects,omitempty"`        // per-project counts
+	MonthlyCost            float64             `json:"monthly_cost_usd"`          // estimated monthly waste of all findings, in USD
+	TotalSizeGiB           float64             `json:"total_size_gib,omitempty"`  // provisioned storage of the resources in TotalInstancesCount, for detectors of storage
+	UnusedSizeGiB          float64             `json:"unused_size_gib,omitempty"` // of those unused
+}
+
+// Subtotal is the KPI for one slice of a result, e.g. one region.
+type Subtotal struct {
+	TotalInstancesCount  int `json:"total_count"`
+	UnusedInstancesCount int `json:"unused_count"`
+}
+
+// Finding describes one unused resource and why it was flagged.
+type Finding struct {
+	ResourceID   string             `json:"resource_id"`
+	ResourceKind string             `json:"resource_kind"`
+	Name         string             `json:"name,omitempty"`
+	Region       string             `json:"region,omitempty"` // region or zone of the resource
+	Account      string             `json:"account,omitempty"`
+	AccountAlias string             `json:"account_alias,omitempty"`
+	Project      string             `json:"project,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`           // zero when the API does not report it
+	Tags         map[string]string  `json:"tags,omitempty"`       // AWS tags or GCP labels of the resource, when the detector read them
+	Attributes   map[string]string  `json:"attributes,omitempty"` // e.g. instance_type, engine, scheme, cidr_block
+	Metrics      map[string]float64 `json:"metrics,omitempty"`    // observed values compared to the threshold, e.g. avg_cpu
+	MonthlyCost  float64            `json:"monthly_cost_usd"`     // estimated monthly waste in USD, zero when unpriced
+}
+
+// Scanner is a detector bound to a scope and a set of parameters.
+type Scanner interface {
+	Name() string
+	Provider() Provider
+	ResourceKind() string
+	Scope() Scope
+	Parameters() Parameters
+	Scan(ctx context.Context) (Result, error)
+}
+
+// ScanFunc runs a detector for the given scope and parameters.
+type ScanFunc func(ctx context.Context, scope Scope, params Parameters) (Result, error)
+
+// Detector describes a registered detector and how to run it.
+type Detector struct {
+	Name         string     // unique name, e.g. "aws/ebs"
+	Provider     Provider   // cloud provider
+	ResourceKind string     // resource kind, e.g. "ebs"
+	Scope        ScopeKind  // location the detector needs
+	Defaults     Parameters // parameters used when the caller leaves them zero
+	Scan         ScanFunc
+}
+
+// New binds the detector to a scope and parameters.
+func (d Detector) New(scope Scope, params Parameters) Scanner {
+	if params.Threshold == 0 {
+		params.Threshold = d.Defaults.Threshold
+	}
+	if params.Days == 0 {
+		params.Days = d.Defaults.Days
+	}
+	return &boundScanner{detector: d, scope: scope, params: params}
+}
+
+// boundScanner is the Scanner returned by Detector.New.
+type boundScanner struct {
+	detector Detector
+	scope    Scope
+	params   Parameters
+}
+
+func (s *boundScanner) Name() string           { return s.detector.Name }
+func (s *boundScanner) Provider() Provider     { return s.detector.Provider }
+func (s *boundScanner) ResourceKind() string   { return s.detector.ResourceKind }
+func (s *boundScanner) Scope() Scope           { return s.scope }
+func (s *boundScanner) Parameters() Parameters { return s.params }
+
+func (s *boundScanner) Scan(ctx context.Context) (Result, error) {
+	return s.detector.Scan(ctx, s.scope, s.params)
+}
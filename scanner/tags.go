@@ -0,0 +1,36 @@
+package scanner
+
+// TagRules decide from a resource's AWS tags or GCP labels whether a
+// detector considers it at all, e.g. to leave out standbys tagged
+// finops:keep=true or to scan only environment=dev. Resources the rules
+// exclude are left out of the KPI and counted in ExcludedInstancesCount.
+type TagRules struct {
+	Include map[string]string `json:"include,omitempty"` // tags a resource must all carry; an empty value matches any value
+	Exclude map[string]string `json:"exclude,omitempty"` // tags any one of which excludes a resource; an empty value matches any value
+}
+
+// Empty reports whether the rules let every resource through, so detectors
+// can skip fetching tags they would otherwise need a separate call for.
+func (r TagRules) Empty() bool { return len(r.Include) == 0 && len(r.Exclude) == 0 }
+
+// Excludes reports whether a resource carrying tags is left out of the scan.
+func (r TagRules) Excludes(tags map[string]string) bool {
+	for key, want := range r.Include {
+		if !hasTag(tags, key, want) {
+			return true
+		}
+	}
+	for key, value := range r.Exclude {
+		if hasTag(tags, key, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTag reports whether tags carry key with the value want, or with any
+// value when want is empty.
+func hasTag(tags map[string]string, key, want string) bool {
+	got, ok := tags[key]
+	return ok && (want == "" || got == want)
+}
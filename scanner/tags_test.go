@@ -0,0 +1,29 @@
+package scanner
+
+import "testing"
+
+func TestTagRulesExcludes(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules TagRules
+		tags  map[string]string
+		want  bool
+	}{
+		{"no rules", TagRules{}, nil, false},
+		{"keep tag", TagRules{Exclude: map[string]string{"finops:keep": "true"}}, map[string]string{"finops:keep": "true"}, true},
+		{"keep tag false", TagRules{Exclude: map[string]string{"finops:keep": "true"}}, map[string]string{"finops:keep": "false"}, false},
+		{"exclude any value", TagRules{Exclude: map[string]string{"dr-standby": ""}}, map[string]string{"dr-standby": "eu"}, true},
+		{"include match", TagRules{Include: map[string]string{"environment": "dev"}}, map[string]string{"environment": "dev"}, false},
+		{"include mismatch", TagRules{Include: map[string]string{"environment": "dev"}}, map[string]string{"environment": "prod"}, true},
+		{"include untagged", TagRules{Include: map[string]string{"environment": "dev"}}, nil, true},
+		{"include then exclude", TagRules{
+			Include: map[string]string{"environment": "dev"},
+			Exclude: map[string]string{"finops:keep": "true"},
+		}, map[string]string{"environment": "dev", "finops:keep": "true"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.Excludes(tt.tags); got != tt.want {
+			t.Errorf("%s: Excludes(%v) = %v, want %v", tt.name, tt.tags, got, tt.want)
+		}
+	}
+}
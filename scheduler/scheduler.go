@@ -0,0 +1,196 @@
+// Package scheduler runs detectors on cron schedules inside the API server,
+// stores every run and keeps a record of the recent ones.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// DefaultHistory is how many records a scheduler keeps.
+const DefaultHistory = 200
+
+// Status is how a scheduled scan went.
+type Status string
+
+const (
+	Running   Status = "running"
+	Succeeded Status = "succeeded" // the run was stored
+	Failed    Status = "failed"
+	Skipped   Status = "skipped" // the previous scan of the detector was still running
+)
+
+// Record describes one scheduled scan of a detector.
+type Record struct {
+	Detector   string       `json:"detector"`
+	Status     Status       `json:"status"`
+	RunID      string       `json:"run_id,omitempty"` // the stored run, once succeeded
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at,omitempty"`
+	Duration   float64      `json:"duration_seconds"`
+	Unused     int          `json:"unused_count"`
+	Total      int          `json:"total_count"`
+	Error      string       `json:"error,omitempty"`
+	Kind       scanner.Kind `json:"kind,omitempty"`
+}
+
+// Entry is a detector and the schedule it is scanned on.
+type Entry struct {
+	Detector string    `json:"detector"`
+	Spec     string    `json:"schedule"`
+	Next     time.Time `json:"next_run"`
+
+	id cron.EntryID
+}
+
+// Scheduler scans detectors on their schedules and stores the runs. Scans
+// of one detector never overlap: a scan due while the previous one is
+// still running is skipped.
+type Scheduler struct {
+	cron    *cron.Cron
+	store   store.Store
+	catalog *pricing.Catalog
+	history int
+	now     func() time.Time
+
+	mu      sync.Mutex
+	ctx     context.Context // cancelled by Stop
+	cancel  context.CancelFunc
+	entries []Entry
+	running map[string]bool
+	records []*Record // oldest first, at most history
+}
+
+// New returns a scheduler storing runs in s, priced from catalog.
+func New(s store.Store, catalog *pricing.Catalog) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:    cron.New(cron.WithLocation(time.UTC)),
+		store:   s,
+		catalog: catalog,
+		history: DefaultHistory,
+		now:     time.Now,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: map[string]bool{},
+	}
+}
+
+// Add schedules scans, all of one detector, on the cron expression spec.
+func (s *Scheduler) Add(spec string, scans []config.Scan) error {
+	if len(scans) == 0 {
+		return errors.New("no scans to schedule")
+	}
+	id, err := s.cron.AddFunc(spec, func() { s.Run(s.ctx, scans) })
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, Entry{Detector: scans[0].Detector.Name, Spec: spec, id: id})
+	return nil
+}
+
+// Schedule adds the scans of every detector cfg schedules.
+func (s *Scheduler) Schedule(cfg *config.Config) error {
+	for _, scans := range store.ByDetector(cfg.Scans()) {
+		name := scans[0].Detector.Name
+		if spec := cfg.Schedule.For(name); spec != "" {
+			if err := s.Add(spec, scans); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Start runs the schedules in the background.
+func (s *Scheduler) Start() { s.cron.Start() }
+
+// Stop stops the schedules, cancels the running scans and waits for them
+// to return.
+func (s *Scheduler) Stop() {
+	done := s.cron.Stop()
+	s.cancel()
+	<-done.Done()
+}
+
+// Run scans the detector of scans now, unless it is being scanned already,
+// stores the run and records how it went.
+func (s *Scheduler) Run(ctx context.Context, scans []config.Scan) Record {
+	detector := scans[0].Detector.Name
+	rec := &Record{Detector: detector, Status: Running, StartedAt: s.now().UTC()}
+	s.mu.Lock()
+	if s.running[detector] {
+		rec.Status, rec.FinishedAt = Skipped, rec.StartedAt
+		s.record(rec)
+		s.mu.Unlock()
+		return *rec
+	}
+	s.running[detector] = true
+	s.record(rec)
+	s.mu.Unlock()
+
+	run, err := store.Scan(ctx, scans, s.catalog)
+	if err == nil {
+		err = s.store.PutRun(ctx, run)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, detector)
+	rec.FinishedAt = s.now().UTC()
+	rec.Duration = rec.FinishedAt.Sub(rec.StartedAt).Seconds()
+	if err != nil {
+		rec.Status, rec.Error, rec.Kind = Failed, err.Error(), scanner.KindOf(err)
+		log.Printf("scheduled scan of %s failed: %v", detector, err)
+		return *rec
+	}
+	rec.Status, rec.RunID = Succeeded, run.ID
+	rec.Unused, rec.Total = run.Result.UnusedInstancesCount, run.Result.TotalInstancesCount
+	return *rec
+}
+
+// record appends rec, dropping the oldest record beyond the history size.
+// The caller holds s.mu.
+func (s *Scheduler) record(rec *Record) {
+	s.records = append(s.records, rec)
+	if len(s.records) > s.history {
+		s.records = s.records[len(s.records)-s.history:]
+	}
+}
+
+// Recent returns up to limit records, newest first, of detector or of
+// every detector when it is empty.
+func (s *Scheduler) Recent(detector string, limit int) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recent := []Record{}
+	for i := len(s.records) - 1; i >= 0 && len(recent) < limit; i-- {
+		if detector == "" || s.records[i].Detector == detector {
+			recent = append(recent, *s.records[i])
+		}
+	}
+	return recent
+}
+
+// Entries returns the scheduled detectors with their next scan.
+func (s *Scheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, len(s.entries))
+	for i, e := range s.entries {
+		e.Next = s.cron.Entry(e.id).Next
+		entries[i] = e
+	}
+	return entries
+}
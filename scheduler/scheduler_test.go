@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// scans returns the scans of a detector whose scan calls scan.
+func scans(name string, scan scanner.ScanFunc) []config.Scan {
+	d := scanner.Detector{Name: name, Provider: scanner.AWS, Scan: scan}
+	return []config.Scan{{Detector: d, Scope: scanner.Scope{Region: "us-east-1"}}}
+}
+
+func newScheduler(t *testing.T) (*Scheduler, store.Store) {
+	s, err := store.OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "unused.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(s, catalog), s
+}
+
+func TestRunRecordsAndStores(t *testing.T) {
+	sched, s := newScheduler(t)
+	ok := scans("aws/ebs", func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		return scanner.Result{ResourceIDs: []string{"vol-1"}, TotalInstancesCount: 3, UnusedInstancesCount: 1,
+			Findings: []scanner.Finding{{ResourceID: "vol-1"}}}, nil
+	})
+	broken := scans("aws/ec2", func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		return scanner.Result{}, scanner.ErrThrottled
+	})
+
+	rec := sched.Run(context.Background(), ok)
+	if rec.Status != Succeeded || rec.RunID == "" || rec.Unused != 1 || rec.Total != 3 || rec.FinishedAt.IsZero() {
+		t.Errorf("record = %+v", rec)
+	}
+	if run, err := s.LatestRun(context.Background(), "aws/ebs"); err != nil || run.ID != rec.RunID {
+		t.Errorf("LatestRun = %+v, %v, want run %s", run, err, rec.RunID)
+	}
+	rec = sched.Run(context.Background(), broken)
+	if rec.Status != Failed || rec.Kind != scanner.KindThrottling || rec.Error == "" {
+		t.Errorf("record = %+v", rec)
+	}
+
+	recent := sched.Recent("", 10)
+	if len(recent) != 2 || recent[0].Detector != "aws/ec2" {
+		t.Errorf("Recent = %+v, want aws/ec2 first", recent)
+	}
+	if recent := sched.Recent("aws/ebs", 10); len(recent) != 1 || recent[0].Status != Succeeded {
+		t.Errorf("Recent(aws/ebs) = %+v", recent)
+	}
+}
+
+func TestRunSkipsOverlappingScans(t *testing.T) {
+	sched, _ := newScheduler(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := scans("aws/ebs", func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		close(started)
+		<-release
+		return scanner.Result{}, nil
+	})
+
+	done := make(chan Record)
+	go func() { done <- sched.Run(context.Background(), slow) }()
+	<-started
+	if running := sched.Recent("aws/ebs", 1); running[0].Status != Running {
+		t.Errorf("record = %+v, want running", running[0])
+	}
+	if rec := sched.Run(context.Background(), slow); rec.Status != Skipped {
+		t.Errorf("overlapping run = %+v, want skipped", rec)
+	}
+	close(release)
+	if rec := <-done; rec.Status != Succeeded {
+		t.Errorf("first run = %+v", rec)
+	}
+}
+
+func TestRecentKeepsHistorySize(t *testing.T) {
+	sched, _ := newScheduler(t)
+	sched.history = 3
+	empty := scans("aws/ebs", func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+		return scanner.Result{}, nil
+	})
+	for range 5 {
+		sched.Run(context.Background(), empty)
+	}
+	if got := sched.Recent("", 10); len(got) != 3 {
+		t.Errorf("kept %d records, want 3", len(got))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	sched, _ := newScheduler(t)
+	noop := scans("aws/ebs", nil)
+	if err := sched.Add("not a schedule", noop); err == nil {
+		t.Error("accepted a bad cron expression")
+	}
+	if err := sched.Add("@hourly", noop); err != nil {
+		t.Fatal(err)
+	}
+	sched.Start()
+	defer sched.Stop()
+	entries := sched.Entries()
+	if len(entries) != 1 || entries[0].Detector != "aws/ebs" || entries[0].Next.Minute() != 0 || entries[0].Next.Before(time.Now()) {
+		t.Errorf("entries = %+v, want aws/ebs at the next full hour", entries)
+	}
+}
@@ -0,0 +1,311 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// DynamoDBAPI is the subset of the DynamoDB client the store uses.
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
+// DynamoDB keeps runs in one table with a string partition key "pk" and
+// sort key "sk". A run is an item under "detector#<name>" sorted by start
+// time; its findings are items under "run#<id>", one per finding, so no
+// item comes near the 400 KB limit however many resources are unused.
+type DynamoDB struct {
+	client DynamoDBAPI
+	table  string
+}
+
+const (
+	batchSize       = 25 // most items BatchWriteItem accepts at once
+	batchAttempts   = 5  // tries before unprocessed items are an error
+	tableWaitTime   = 2 * time.Minute
+	sortableTimeFmt = "2006-01-02T15:04:05.000000000Z" // fixed width, so sort keys order by time
+)
+
+// NewDynamoDB returns a store keeping runs in table through client.
+func NewDynamoDB(client DynamoDBAPI, table string) *DynamoDB {
+	return &DynamoDB{client: client, table: table}
+}
+
+// OpenDynamoDB returns a store for the table cfg names, reached with the
+// default credential chain, or at cfg.Endpoint for DynamoDB Local.
+func OpenDynamoDB(ctx context.Context, cfg config.DynamoDB) (*DynamoDB, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client := dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+	return NewDynamoDB(client, cfg.Table), nil
+}
+
+// CreateTable creates the table with on-demand capacity, unless it already
+// exists, and waits until it is active.
+func (d *DynamoDB) CreateTable(ctx context.Context) error {
+	_, err := d.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(d.table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	var inUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUse) {
+		return dynamoError("CreateTable", d.table, err)
+	}
+	waiter := dynamodb.NewTableExistsWaiter(d.client)
+	return waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(d.table)}, tableWaitTime)
+}
+
+// PutRun writes the run's findings, then the run itself, so a reader that
+// finds the run also finds all of its findings.
+func (d *DynamoDB) PutRun(ctx context.Context, run Run) error {
+	requests := make([]types.WriteRequest, 0, len(run.Result.Findings))
+	for i, f := range run.Result.Findings {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+			"pk":      str(runKey(run.ID)),
+			"sk":      str(fmt.Sprintf("finding#%08d", i)),
+			"finding": str(string(data)),
+		}}})
+	}
+	for len(requests) > 0 {
+		n := min(len(requests), batchSize)
+		if err := d.batchWrite(ctx, requests[:n]); err != nil {
+			return err
+		}
+		requests = requests[n:]
+	}
+
+	// Findings and resource IDs are one per finding item; the rest of the
+	// result is kept on the run, with the KPI counts as numbers.
+	summary := run.Result
+	summary.Findings, summary.ResourceIDs = nil, nil
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.table),
+		Item: map[string]types.AttributeValue{
+			"pk":               str(detectorKey(run.Detector)),
+			"sk":               str(runSortKey(run.StartedAt) + "#" + run.ID),
+			"id":               str(run.ID),
+			"detector":         str(run.Detector),
+			"started_at":       str(run.StartedAt.UTC().Format(time.RFC3339Nano)),
+			"finished_at":      str(run.FinishedAt.UTC().Format(time.RFC3339Nano)),
+			"total_count":      num(run.Result.TotalInstancesCount),
+			"unused_count":     num(run.Result.UnusedInstancesCount),
+			"excluded_count":   num(run.Result.ExcludedInstancesCount),
+			"monthly_cost_usd": num(run.Result.MonthlyCost),
+			"findings":         num(len(run.Result.Findings)),
+			"result":           str(string(data)),
+		},
+	})
+	return dynamoError("PutItem", run.ID, err)
+}
+
+// batchWrite writes requests, retrying the items DynamoDB leaves
+// unprocessed when it throttles.
+func (d *DynamoDB) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	pending := map[string][]types.WriteRequest{d.table: requests}
+	for attempt := 0; ; attempt++ {
+		out, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+		if err != nil {
+			return dynamoError("BatchWriteItem", d.table, err)
+		}
+		if len(out.UnprocessedItems[d.table]) == 0 {
+			return nil
+		}
+		if attempt == batchAttempts-1 {
+			return &scanner.Error{Kind: scanner.KindThrottling, Op: "BatchWriteItem", Resource: d.table,
+				Err: fmt.Errorf("%d items left unprocessed", len(out.UnprocessedItems[d.table]))}
+		}
+		pending = out.UnprocessedItems
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(100<<attempt) * time.Millisecond):
+		}
+	}
+}
+
+// LatestRun returns the most recent run of detector with its findings.
+func (d *DynamoDB) LatestRun(ctx context.Context, detector string) (Run, error) {
+	run, err := d.LatestSummary(ctx, detector)
+	if err != nil {
+		return Run{}, err
+	}
+
+	pages := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+		TableName:              aws.String(d.table),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     str(runKey(run.ID)),
+			":prefix": str("finding#"),
+		},
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return Run{}, dynamoError("Query", run.ID, err)
+		}
+		for _, item := range page.Items {
+			var f scanner.Finding
+			if err := json.Unmarshal([]byte(stringAttr(item, "finding")), &f); err != nil {
+				return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+			}
+			run.Result.Findings = append(run.Result.Findings, f)
+			run.Result.ResourceIDs = append(run.Result.ResourceIDs, f.ResourceID)
+		}
+	}
+	return run, nil
+}
+
+// LatestSummary returns the most recent run of detector from its run item
+// alone.
+func (d *DynamoDB) LatestSummary(ctx context.Context, detector string) (Run, error) {
+	out, err := d.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(d.table),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     str(detectorKey(detector)),
+			":prefix": str("run#"),
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+	})
+	if err != nil {
+		return Run{}, dynamoError("Query", detector, err)
+	}
+	if len(out.Items) == 0 {
+		return Run{}, noRuns(detector)
+	}
+	return decodeRun(out.Items[0])
+}
+
+// Snapshots returns the KPI of the runs of detector started in [from, to),
+// read from the run items alone.
+func (d *DynamoDB) Snapshots(ctx context.Context, detector string, from, to time.Time) ([]Snapshot, error) {
+	// A run started at to sorts after runSortKey(to), so BETWEEN leaves it out.
+	pages := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+		TableName:              aws.String(d.table),
+		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   str(detectorKey(detector)),
+			":from": str(runSortKey(from)),
+			":to":   str(runSortKey(to)),
+		},
+	})
+	var snapshots []Snapshot
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, dynamoError("Query", detector, err)
+		}
+		for _, item := range page.Items {
+			run, err := decodeRun(item)
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, snapshot(run))
+		}
+	}
+	return snapshots, nil
+}
+
+// Close does nothing; the DynamoDB client holds no connection.
+func (d *DynamoDB) Close() error { return nil }
+
+// decodeRun reads a run item, without its findings.
+func decodeRun(item map[string]types.AttributeValue) (Run, error) {
+	run := Run{ID: stringAttr(item, "id"), Detector: stringAttr(item, "detector")}
+	var err error
+	if run.StartedAt, err = time.Parse(time.RFC3339Nano, stringAttr(item, "started_at")); err != nil {
+		return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+	}
+	if run.FinishedAt, err = time.Parse(time.RFC3339Nano, stringAttr(item, "finished_at")); err != nil {
+		return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+	}
+	if err := json.Unmarshal([]byte(stringAttr(item, "result")), &run.Result); err != nil {
+		return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+	}
+	return run, nil
+}
+
+func detectorKey(detector string) string { return "detector#" + detector }
+func runKey(id string) string            { return "run#" + id }
+
+// runSortKey is the sort key prefix of the runs started at t.
+func runSortKey(t time.Time) string { return "run#" + t.UTC().Format(sortableTimeFmt) }
+
+func str(s string) types.AttributeValue { return &types.AttributeValueMemberS{Value: s} }
+
+func num[T int | float64](n T) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: fmt.Sprint(n)}
+}
+
+// stringAttr returns the string attribute name of item, or "" if it has none.
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
+
+// dynamoError classifies a DynamoDB error the way detector errors are, so
+// the API answers a throttled or misconfigured store like a throttled scan.
+func dynamoError(op, resource string, err error) error {
+	if err == nil {
+		return nil
+	}
+	kind := scanner.KindUnknown
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ResourceNotFoundException":
+			kind = scanner.KindNotFound
+		case "ProvisionedThroughputExceededException", "ThrottlingException", "RequestLimitExceeded":
+			kind = scanner.KindThrottling
+		case "AccessDeniedException":
+			kind = scanner.KindPermission
+		case "UnrecognizedClientException", "InvalidSignatureException", "ExpiredTokenException":
+			kind = scanner.KindAuth
+		}
+	}
+	return &scanner.Error{Kind: kind, Op: op, Resource: resource, Err: err}
+}
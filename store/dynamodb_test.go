@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func testRun(id string, started time.Time, volumes ...string) Run {
+	run := Run{ID: id, Detector: "aws/ebs", StartedAt: started, FinishedAt: started.Add(time.Second)}
+	for _, v := range volumes {
+		run.Result.Merge(scanner.Result{
+			ResourceIDs:          []string{v},
+			TotalInstancesCount:  2,
+			UnusedInstancesCount: 1,
+			Findings:             []scanner.Finding{{ResourceID: v, ResourceKind: "ebs", Region: "us-east-1", MonthlyCost: 8}},
+			Regions:              map[string]scanner.Subtotal{"us-east-1": {TotalInstancesCount: 2, UnusedInstancesCount: 1}},
+			MonthlyCost:          8,
+		})
+	}
+	return run
+}
+
+// testStore checks a store round trips runs, serves the latest one and
+// the KPI of each.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	if _, err := s.LatestRun(ctx, "aws/ebs"); !errors.Is(err, scanner.ErrNotFound) {
+		t.Fatalf("err = %v before any run, want not found", err)
+	}
+
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	older := testRun("older", start, "vol-old")
+	var volumes []string
+	for i := range 30 { // more than one BatchWriteItem call
+		volumes = append(volumes, fmt.Sprintf("vol-%02d", i))
+	}
+	latest := testRun("latest", start.Add(24*time.Hour), volumes...)
+	for _, run := range []Run{latest, older} {
+		if err := s.PutRun(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.LatestRun(ctx, "aws/ebs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, latest) {
+		t.Errorf("LatestRun =\n%+v\nwant\n%+v", got, latest)
+	}
+	summary := latest
+	summary.Result.Findings, summary.Result.ResourceIDs = nil, nil
+	if got, err := s.LatestSummary(ctx, "aws/ebs"); err != nil || !reflect.DeepEqual(got, summary) {
+		t.Errorf("LatestSummary =\n%+v, %v\nwant\n%+v", got, err, summary)
+	}
+
+	snapshots, err := s.Snapshots(ctx, "aws/ebs", start, latest.StartedAt.Add(time.Nanosecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Snapshot{snapshot(older), snapshot(latest)}; !reflect.DeepEqual(snapshots, want) {
+		t.Errorf("Snapshots =\n%+v\nwant\n%+v", snapshots, want)
+	}
+	if snapshots, err := s.Snapshots(ctx, "aws/ebs", start, latest.StartedAt); err != nil || len(snapshots) != 1 {
+		t.Errorf("Snapshots up to the latest run = %+v, %v, want only the older run", snapshots, err)
+	}
+}
+
+func TestDynamoDB(t *testing.T) {
+	testStore(t, NewDynamoDB(newFakeDynamoDB(), "runs"))
+}
+
+func TestDynamoDBRetriesUnprocessedItems(t *testing.T) {
+	client := newFakeDynamoDB()
+	client.unprocess = 2
+	s := NewDynamoDB(client, "runs")
+
+	if err := s.PutRun(context.Background(), testRun("r", time.Now(), "vol-1", "vol-2")); err != nil {
+		t.Fatal(err)
+	}
+	if client.batches != 3 || len(client.items[runKey("r")]) != 2 {
+		t.Errorf("batches = %d, findings = %d, want 3 and 2", client.batches, len(client.items[runKey("r")]))
+	}
+}
+
+// TestDynamoDBLocal runs against DynamoDB Local when DYNAMODB_ENDPOINT is
+// set, e.g. after docker run -p 8000:8000 amazon/dynamodb-local:
+//
+//	DYNAMODB_ENDPOINT=http://localhost:8000 go test ./store
+func TestDynamoDBLocal(t *testing.T) {
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT not set")
+	}
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(endpoint),
+		Credentials:  credentials.NewStaticCredentialsProvider("local", "local", ""),
+	})
+	s := NewDynamoDB(client, fmt.Sprintf("unused-test-%d", time.Now().UnixNano()))
+	if err := s.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(s.table)})
+	})
+	testStore(t, s)
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDB keeps items in memory and answers the queries the store
+// makes: a partition key and a sort key prefix or range.
+type fakeDynamoDB struct {
+	items     map[string]map[string]map[string]types.AttributeValue // pk, sk
+	pageSize  int                                                   // items per Query page, to exercise pagination
+	unprocess int                                                   // BatchWriteItem calls that leave their last item unprocessed
+	batches   int
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: map[string]map[string]map[string]types.AttributeValue{}, pageSize: 2}
+}
+
+func (f *fakeDynamoDB) put(item map[string]types.AttributeValue) {
+	pk, sk := stringAttr(item, "pk"), stringAttr(item, "sk")
+	if f.items[pk] == nil {
+		f.items[pk] = map[string]map[string]types.AttributeValue{}
+	}
+	f.items[pk][sk] = item
+}
+
+func (f *fakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.put(params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	f.batches++
+	out := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+	for table, requests := range params.RequestItems {
+		if f.unprocess > 0 && len(requests) > 0 {
+			f.unprocess--
+			out.UnprocessedItems[table] = requests[len(requests)-1:]
+			requests = requests[:len(requests)-1]
+		}
+		for _, r := range requests {
+			f.put(r.PutRequest.Item)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	pk := stringAttr(params.ExpressionAttributeValues, ":pk")
+	prefix := stringAttr(params.ExpressionAttributeValues, ":prefix")
+	from, to := stringAttr(params.ExpressionAttributeValues, ":from"), stringAttr(params.ExpressionAttributeValues, ":to")
+	var keys []string
+	for sk := range f.items[pk] {
+		if strings.HasPrefix(sk, prefix) && (to == "" || from <= sk && sk <= to) {
+			keys = append(keys, sk)
+		}
+	}
+	sort.Strings(keys)
+	if params.ScanIndexForward != nil && !*params.ScanIndexForward {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	}
+	if params.ExclusiveStartKey != nil {
+		start := stringAttr(params.ExclusiveStartKey, "sk")
+		for len(keys) > 0 && keys[0] != start {
+			keys = keys[1:]
+		}
+		keys = keys[1:]
+	}
+	limit := f.pageSize
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+	out := &dynamodb.QueryOutput{}
+	for i, sk := range keys {
+		if i == limit {
+			last := out.Items[i-1]
+			out.LastEvaluatedKey = map[string]types.AttributeValue{"pk": last["pk"], "sk": last["sk"]}
+			break
+		}
+		out.Items = append(out.Items, f.items[pk][sk])
+	}
+	return out, nil
+}
+
+func (f *fakeDynamoDB) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (f *fakeDynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableName: params.TableName, TableStatus: types.TableStatusActive}}, nil
+}
@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Page sizes of Findings: the default and the most a query gets.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// Order is how findings are sorted. Findings whose creation time is
+// unknown come last when sorting by age.
+type Order string
+
+const (
+	CostDesc Order = "-cost" // most expensive first, the default
+	CostAsc  Order = "cost"
+	AgeDesc  Order = "-age" // oldest first
+	AgeAsc   Order = "age"  // newest first
+)
+
+// ParseOrder parses a sort order; empty means CostDesc and anything else
+// unknown is an error of kind invalid.
+func ParseOrder(s string) (Order, error) {
+	switch o := Order(s); o {
+	case "":
+		return CostDesc, nil
+	case CostDesc, CostAsc, AgeDesc, AgeAsc:
+		return o, nil
+	}
+	return "", &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("sort %q is not cost, -cost, age or -age", s)}
+}
+
+// Listed is a finding of a stored run with the detector that found it.
+type Listed struct {
+	Detector  string           `json:"detector"`
+	Provider  scanner.Provider `json:"provider"`
+	RunID     string           `json:"run_id"`
+	ScannedAt time.Time        `json:"scanned_at"`
+	scanner.Finding
+
+	position int // in the findings of the run, the tiebreak of the sort orders
+}
+
+// FindingQuery selects findings from the latest run of each detector,
+// orders and pages them. Empty fields match every finding; a finding
+// matches a list when it matches any entry. S3 buckets and load balancers
+// only carry their tags when the scan had tag rules, reading them costing
+// an API call per resource, so Tags never matches them otherwise.
+type FindingQuery struct {
+	Detectors     []string
+	Accounts      []string          // account IDs or aliases
+	Projects      []string          // GCP project IDs
+	Regions       []string          // regions, matching their zones too, or zones
+	Kinds         []string          // resource kinds, e.g. "ebs"
+	Tags          map[string]string // every key must be set, to the value unless it is empty
+	CreatedAfter  time.Time         // excludes findings of unknown age when set
+	CreatedBefore time.Time         // likewise
+	MinCost       *float64          // estimated monthly cost in USD
+	MaxCost       *float64
+	Order         Order
+	Limit         int    // page size, DefaultPageSize when not positive, at most MaxPageSize
+	Cursor        string // NextCursor of the previous page
+}
+
+// FindingPage is one page of findings.
+type FindingPage struct {
+	Findings   []Listed `json:"findings"`
+	Total      int      `json:"total"`                 // findings matching the query across all pages
+	NextCursor string   `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// Findings returns the page of findings q asks for from the latest run of
+// each of its detectors; detectors never run are left out. Pages are cut
+// by a cursor on the sort order, so findings are neither repeated nor
+// skipped when a new run lands between two pages, though the new run's
+// findings are only seen by starting over. SQL stores filter, sort and page
+// in the database; other stores load the latest runs and do it in memory.
+func Findings(ctx context.Context, s Store, q FindingQuery) (FindingPage, error) {
+	if q.Order == "" {
+		q.Order = CostDesc
+	}
+	limit := min(q.Limit, MaxPageSize)
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	var after *findingKey
+	if q.Cursor != "" {
+		k, err := decodeCursor(q.Cursor, q.Order)
+		if err != nil {
+			return FindingPage{}, err
+		}
+		after = &k
+	}
+	if db, ok := s.(*SQL); ok {
+		return db.findings(ctx, q, after, limit)
+	}
+
+	matched := []Listed{}
+	for _, detector := range q.Detectors {
+		run, err := s.LatestRun(ctx, detector)
+		if errors.Is(err, scanner.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return FindingPage{}, err
+		}
+		d, _ := scanner.Lookup(detector)
+		for i, f := range run.Result.Findings {
+			if q.matches(f) {
+				matched = append(matched, Listed{Detector: detector, Provider: d.Provider, RunID: run.ID, ScannedAt: run.FinishedAt, Finding: f, position: i})
+			}
+		}
+	}
+
+	keys := make([]findingKey, len(matched))
+	for i, l := range matched {
+		keys[i] = keyOf(l, q.Order)
+	}
+	sort.Sort(byKey{matched, keys})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(keys), func(i int) bool { return after.less(keys[i]) })
+	}
+	end := min(start+limit, len(matched))
+	page := FindingPage{Findings: matched[start:end], Total: len(matched)}
+	if end < len(matched) {
+		page.NextCursor = keys[end-1].cursor(q.Order)
+	}
+	return page, nil
+}
+
+// matches reports whether f passes every filter of q.
+func (q FindingQuery) matches(f scanner.Finding) bool {
+	switch {
+	case len(q.Accounts) > 0 && !anyOf(q.Accounts, func(a string) bool { return a == f.Account || (a != "" && a == f.AccountAlias) }):
+		return false
+	case len(q.Projects) > 0 && !anyOf(q.Projects, func(p string) bool { return p == f.Project }):
+		return false
+	case len(q.Regions) > 0 && !anyOf(q.Regions, func(r string) bool { return f.Region == r || strings.HasPrefix(f.Region, r+"-") }):
+		return false
+	case len(q.Kinds) > 0 && !anyOf(q.Kinds, func(k string) bool { return k == f.ResourceKind }):
+		return false
+	case !q.CreatedAfter.IsZero() && (f.CreatedAt.IsZero() || !f.CreatedAt.After(q.CreatedAfter)):
+		return false
+	case !q.CreatedBefore.IsZero() && (f.CreatedAt.IsZero() || !f.CreatedAt.Before(q.CreatedBefore)):
+		return false
+	case q.MinCost != nil && f.MonthlyCost < *q.MinCost:
+		return false
+	case q.MaxCost != nil && f.MonthlyCost > *q.MaxCost:
+		return false
+	}
+	for key, value := range q.Tags {
+		if v, ok := f.Tags[key]; !ok || (value != "" && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyOf(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// findingKey places a finding in a sort order: known values first, by
+// value, then by run and position in the run, which are unique to the
+// finding. Values sorted descending are negated.
+type findingKey struct {
+	Unknown  bool    `json:"u,omitempty"`
+	Value    float64 `json:"v"`
+	RunID    string  `json:"r"`
+	Position int     `json:"p"`
+}
+
+func keyOf(l Listed, order Order) findingKey {
+	k := findingKey{RunID: l.RunID, Position: l.position}
+	switch order {
+	case CostAsc:
+		k.Value = l.MonthlyCost
+	case CostDesc:
+		k.Value = -l.MonthlyCost
+	case AgeDesc, AgeAsc:
+		k.Unknown = l.CreatedAt.IsZero()
+		k.Value = float64(l.CreatedAt.UnixMilli())
+		if order == AgeAsc {
+			k.Value = -k.Value
+		}
+	}
+	return k
+}
+
+func (k findingKey) less(other findingKey) bool {
+	if k.Unknown != other.Unknown {
+		return other.Unknown
+	}
+	if k.Value != other.Value {
+		return k.Value < other.Value
+	}
+	if k.RunID != other.RunID {
+		return k.RunID < other.RunID
+	}
+	return k.Position < other.Position
+}
+
+// cursor encodes k, and the order it belongs to, for the next page.
+func (k findingKey) cursor(order Order) string {
+	b, _ := json.Marshal(struct {
+		Order Order `json:"o"`
+		findingKey
+	}{order, k})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(cursor string, order Order) (findingKey, error) {
+	var c struct {
+		Order Order `json:"o"`
+		findingKey
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	switch {
+	case err != nil:
+		return findingKey{}, &scanner.Error{Kind: scanner.KindInvalid, Err: errors.New("cursor is not one a previous page returned")}
+	case c.Order != order:
+		return findingKey{}, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("cursor is for sort %s, not %s", c.Order, order)}
+	}
+	return c.findingKey, nil
+}
+
+// byKey sorts findings by their keys.
+type byKey struct {
+	findings []Listed
+	keys     []findingKey
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i].less(b.keys[j]) }
+func (b byKey) Swap(i, j int) {
+	b.findings[i], b.findings[j] = b.findings[j], b.findings[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
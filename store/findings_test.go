@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// emptyStores returns an empty store of each kind: DynamoDB, on a fake,
+// and SQLite.
+func emptyStores(t *testing.T) map[string]Store {
+	s, err := OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "unused.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return map[string]Store{"dynamodb": NewDynamoDB(newFakeDynamoDB(), "runs"), "sqlite": s}
+}
+
+func TestFindings(t *testing.T) {
+	for name, s := range emptyStores(t) {
+		t.Run(name, func(t *testing.T) { testFindings(t, s) })
+	}
+}
+
+func testFindings(t *testing.T, s Store) {
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ebs := Run{ID: "ebs-run", Detector: "aws/ebs", Result: scanner.Result{Findings: []scanner.Finding{
+		{ResourceID: "vol-1", ResourceKind: "ebs", Region: "us-east-1", Account: "111111111111", AccountAlias: "prod", MonthlyCost: 8, CreatedAt: created, Tags: map[string]string{"team": "data"}},
+		{ResourceID: "vol-2", ResourceKind: "ebs", Region: "eu-west-1", Account: "222222222222", MonthlyCost: 40, CreatedAt: created.AddDate(0, 6, 0)},
+		{ResourceID: "vol-3", ResourceKind: "ebs", Region: "us-east-1", Account: "111111111111", AccountAlias: "prod", MonthlyCost: 8, Tags: map[string]string{"team": "web"}},
+	}}}
+	disks := Run{ID: "disks-run", Detector: "gcp/disks", Result: scanner.Result{Findings: []scanner.Finding{
+		{ResourceID: "disk-1", ResourceKind: "disks", Region: "us-central1-a", Project: "finops", MonthlyCost: 4, CreatedAt: created.AddDate(0, 3, 0)},
+	}}}
+	for _, run := range []Run{ebs, disks} {
+		if err := s.PutRun(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all := []string{"aws/ebs", "gcp/disks", "aws/ec2"} // aws/ec2 never ran
+	cost := func(v float64) *float64 { return &v }
+
+	for name, tc := range map[string]struct {
+		q    FindingQuery
+		want []string
+	}{
+		"by cost":          {FindingQuery{}, []string{"vol-2", "vol-1", "vol-3", "disk-1"}},
+		"cheapest":         {FindingQuery{Order: CostAsc}, []string{"disk-1", "vol-1", "vol-3", "vol-2"}},
+		"oldest":           {FindingQuery{Order: AgeDesc}, []string{"vol-1", "disk-1", "vol-2", "vol-3"}},
+		"newest":           {FindingQuery{Order: AgeAsc}, []string{"vol-2", "disk-1", "vol-1", "vol-3"}},
+		"alias":            {FindingQuery{Accounts: []string{"prod"}}, []string{"vol-1", "vol-3"}},
+		"region and zones": {FindingQuery{Regions: []string{"us-central1", "eu-west-1"}}, []string{"vol-2", "disk-1"}},
+		"project":          {FindingQuery{Projects: []string{"finops"}, Kinds: []string{"disks"}}, []string{"disk-1"}},
+		"tag key":          {FindingQuery{Tags: map[string]string{"team": ""}}, []string{"vol-1", "vol-3"}},
+		"tag value":        {FindingQuery{Tags: map[string]string{"team": "web"}}, []string{"vol-3"}},
+		"older than":       {FindingQuery{CreatedBefore: created.AddDate(0, 4, 0)}, []string{"vol-1", "disk-1"}},
+		"cost range":       {FindingQuery{MinCost: cost(5), MaxCost: cost(8)}, []string{"vol-1", "vol-3"}},
+		"nothing":          {FindingQuery{Kinds: []string{"ec2"}}, nil},
+	} {
+		tc.q.Detectors = all
+		page, err := Findings(ctx, s, tc.q)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := ids(page.Findings); !slices.Equal(got, tc.want) || page.Total != len(tc.want) || page.NextCursor != "" {
+			t.Errorf("%s: %v (total %d), want %v", name, got, page.Total, tc.want)
+		}
+	}
+
+	// One finding a page comes in the same order, past equal and unknown values.
+	for _, order := range []Order{CostDesc, CostAsc, AgeDesc, AgeAsc} {
+		q := FindingQuery{Detectors: all, Order: order}
+		whole, err := Findings(ctx, s, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Limit = 1
+		var got []string
+		for range whole.Findings {
+			page, err := Findings(ctx, s, q)
+			if err != nil {
+				t.Fatalf("%s: %v", order, err)
+			}
+			got = append(got, ids(page.Findings)...)
+			q.Cursor = page.NextCursor
+		}
+		if want := ids(whole.Findings); !slices.Equal(got, want) || q.Cursor != "" {
+			t.Errorf("%s: pages %v, want %v", order, got, want)
+		}
+	}
+
+	page, _ := Findings(ctx, s, FindingQuery{Detectors: all, Kinds: []string{"disks"}})
+	if l := page.Findings[0]; l.Detector != "gcp/disks" || l.RunID != "disks-run" {
+		t.Errorf("listed = %+v", l)
+	}
+}
+
+func TestFindingsPages(t *testing.T) {
+	for name, s := range emptyStores(t) {
+		t.Run(name, func(t *testing.T) { testFindingsPages(t, s) })
+	}
+}
+
+func testFindingsPages(t *testing.T, s Store) {
+	ctx := context.Background()
+	run := testRun("run", time.Now(), "vol-a", "vol-b", "vol-c", "vol-d", "vol-e")
+	if err := s.PutRun(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+
+	q := FindingQuery{Detectors: []string{"aws/ebs"}, Limit: 2}
+	var got []string
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("cursor does not advance")
+		}
+		page, err := Findings(ctx, s, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 5 {
+			t.Errorf("total = %d, want 5", page.Total)
+		}
+		got = append(got, ids(page.Findings)...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	// Equal costs fall back to the order of the run.
+	if want := []string{"vol-a", "vol-b", "vol-c", "vol-d", "vol-e"}; !slices.Equal(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+
+	q.Order = AgeDesc
+	if _, err := Findings(ctx, s, q); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("err = %v for a cursor of another order, want invalid", err)
+	}
+	q.Cursor = "garbage"
+	if _, err := Findings(ctx, s, q); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("err = %v for a bad cursor, want invalid", err)
+	}
+}
+
+func ids(findings []Listed) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ResourceID)
+	}
+	return ids
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// Interval is the width of the buckets a KPI history is drawn in.
+type Interval string
+
+const (
+	Hour  Interval = "hour"
+	Day   Interval = "day"
+	Week  Interval = "week" // starting on Monday
+	Month Interval = "month"
+)
+
+// ParseInterval returns the interval named s, Day when s is empty.
+func ParseInterval(s string) (Interval, error) {
+	switch i := Interval(s); i {
+	case "":
+		return Day, nil
+	case Hour, Day, Week, Month:
+		return i, nil
+	default:
+		return "", &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("interval %q is not hour, day, week or month", s)}
+	}
+}
+
+// Start returns the start of the interval t falls in, in UTC.
+func (i Interval) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case Hour:
+		return t.Truncate(time.Hour)
+	case Week:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Add returns t moved by n intervals.
+func (i Interval) Add(t time.Time, n int) time.Time {
+	switch i {
+	case Hour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case Week:
+		return t.AddDate(0, 0, 7*n)
+	case Month:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// Point is the KPI of a detector over one interval: that of the last run
+// started in it.
+type Point struct {
+	Start time.Time `json:"start"` // start of the interval
+	Snapshot
+	Percentage float64 `json:"percentage"` // unused share of the total, 0 when nothing was scanned
+}
+
+// History returns the KPI of detector for each interval between from and
+// to in which it was scanned, oldest first. Intervals without a run are
+// left out rather than reported as zero.
+func History(ctx context.Context, s Store, detector string, from, to time.Time, interval Interval) ([]Point, error) {
+	if !from.Before(to) {
+		return nil, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("from %s is not before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))}
+	}
+	snapshots, err := s.Snapshots(ctx, detector, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var points []Point
+	for _, snap := range snapshots {
+		p := Point{Start: interval.Start(snap.StartedAt), Snapshot: snap, Percentage: scanner.Percentage(snap.UnusedInstancesCount, snap.TotalInstancesCount)}
+		if n := len(points); n > 0 && points[n-1].Start.Equal(p.Start) {
+			points[n-1] = p
+			continue
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+func TestIntervalStart(t *testing.T) {
+	at := time.Date(2026, 10, 15, 13, 45, 0, 0, time.UTC) // a Thursday
+	for interval, want := range map[Interval]time.Time{
+		Hour:  time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC),
+		Day:   time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		Week:  time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		Month: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := interval.Start(at); !got.Equal(want) {
+			t.Errorf("%s: Start = %s, want %s", interval, got, want)
+		}
+	}
+	if got := Week.Start(time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)); got.Day() != 12 {
+		t.Errorf("a Sunday starts the week of %s, want Monday the 12th", got)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	if i, err := ParseInterval(""); i != Day || err != nil {
+		t.Errorf(`ParseInterval("") = %q, %v`, i, err)
+	}
+	if _, err := ParseInterval("fortnight"); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("err = %v, want invalid", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	s := NewDynamoDB(newFakeDynamoDB(), "runs")
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, run := range []Run{
+		testRun("empty", day.Add(6*time.Hour)), // nothing scanned
+		testRun("morning", day.Add(24*time.Hour+6*time.Hour), "vol-1"),
+		testRun("evening", day.Add(24*time.Hour+18*time.Hour), "vol-1", "vol-2"),
+		testRun("later", day.Add(4*24*time.Hour), "vol-1"),
+	} {
+		if err := s.PutRun(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	points, err := History(ctx, s, "aws/ebs", day, day.AddDate(0, 0, 3), Day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The 2nd has two runs and reports the later; the 3rd has none and
+	// the 5th is out of range.
+	if len(points) != 2 {
+		t.Fatalf("points = %+v, want the 1st and the 2nd", points)
+	}
+	if points[0].RunID != "empty" || points[0].Percentage != 0 {
+		t.Errorf("1st = %+v, want run empty at 0%%", points[0])
+	}
+	if p := points[1]; p.RunID != "evening" || !p.Start.Equal(day.AddDate(0, 0, 1)) || p.Percentage != 50 || p.MonthlyCost != 16 {
+		t.Errorf("2nd = %+v, want run evening, 50%% and 16 USD", p)
+	}
+
+	if _, err := History(ctx, s, "aws/ebs", day, day, Day); !errors.Is(err, scanner.ErrInvalid) {
+		t.Errorf("err = %v for an empty range, want invalid", err)
+	}
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// KPI is how much of a set of resources is unused and what that wastes.
+type KPI struct {
+	TotalInstancesCount    int      `json:"total_count"`
+	UnusedInstancesCount   int      `json:"unused_count"`
+	ExcludedInstancesCount int      `json:"excluded_count"`
+	Percentage             float64  `json:"percentage"` // unused share of the resources, 0 when there are none
+	TotalSizeGiB           float64  `json:"total_size_gib,omitempty"`
+	UnusedSizeGiB          float64  `json:"unused_size_gib,omitempty"`
+	SizePercentage         *float64 `json:"size_percentage,omitempty"` // unused share of the storage, when the resources have a size
+	MonthlyCost            float64  `json:"monthly_cost_usd"`          // estimated monthly waste in USD
+}
+
+// add counts the resources of r.
+func (k *KPI) add(r scanner.Result) {
+	k.TotalInstancesCount += r.TotalInstancesCount
+	k.UnusedInstancesCount += r.UnusedInstancesCount
+	k.ExcludedInstancesCount += r.ExcludedInstancesCount
+	k.TotalSizeGiB += r.TotalSizeGiB
+	k.UnusedSizeGiB += r.UnusedSizeGiB
+	k.MonthlyCost += r.MonthlyCost
+	k.Percentage = scanner.Percentage(k.UnusedInstancesCount, k.TotalInstancesCount)
+	k.SizePercentage = nil
+	if k.TotalSizeGiB > 0 {
+		p := 100 * k.UnusedSizeGiB / k.TotalSizeGiB
+		k.SizePercentage = &p
+	}
+}
+
+// ResourceKPI is the KPI of the latest run of one detector.
+type ResourceKPI struct {
+	Detector     string           `json:"detector"`
+	Provider     scanner.Provider `json:"provider"`
+	ResourceKind string           `json:"resource_kind"`
+	RunID        string           `json:"run_id"`
+	ScannedAt    time.Time        `json:"scanned_at"`
+	KPI
+}
+
+// Summary is every KPI at once: per resource type, per provider and
+// overall. The size percentages of providers and overall weigh only the
+// resources that have a size.
+type Summary struct {
+	Overall   KPI                      `json:"overall"`
+	Providers map[scanner.Provider]KPI `json:"providers"`
+	Resources []ResourceKPI            `json:"resources"`
+	Missing   []string                 `json:"missing,omitempty"` // detectors not run yet, left out of the KPIs
+}
+
+// Summarize returns the KPIs of the latest run of each of detectors, read
+// without their findings.
+func Summarize(ctx context.Context, s Store, detectors []string) (Summary, error) {
+	sum := Summary{Providers: map[scanner.Provider]KPI{}, Resources: []ResourceKPI{}}
+	for _, name := range detectors {
+		run, err := s.LatestSummary(ctx, name)
+		if errors.Is(err, scanner.ErrNotFound) {
+			sum.Missing = append(sum.Missing, name)
+			continue
+		}
+		if err != nil {
+			return Summary{}, err
+		}
+		d, ok := scanner.Lookup(name)
+		if !ok {
+			provider, _, _ := strings.Cut(name, "/")
+			d.Provider = scanner.Provider(provider)
+		}
+		res := ResourceKPI{Detector: name, Provider: d.Provider, ResourceKind: d.ResourceKind, RunID: run.ID, ScannedAt: run.FinishedAt}
+		res.add(run.Result)
+		sum.Resources = append(sum.Resources, res)
+
+		provider := sum.Providers[d.Provider]
+		provider.add(run.Result)
+		sum.Providers[d.Provider] = provider
+		sum.Overall.add(run.Result)
+	}
+	return sum, nil
+}
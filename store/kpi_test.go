@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+)
+
+// summariesOnly is a store that fails to load findings.
+type summariesOnly struct{ Store }
+
+func (summariesOnly) LatestRun(ctx context.Context, detector string) (Run, error) {
+	return Run{}, errors.New("Summarize loaded the findings")
+}
+
+func TestSummarize(t *testing.T) {
+	for name, s := range emptyStores(t) {
+		t.Run(name, func(t *testing.T) { testSummarize(t, summariesOnly{s}) })
+	}
+}
+
+func testSummarize(t *testing.T, s Store) {
+	ctx := context.Background()
+	for name, result := range map[string]scanner.Result{
+		"aws/ebs":   {TotalInstancesCount: 4, UnusedInstancesCount: 1, TotalSizeGiB: 400, UnusedSizeGiB: 300, MonthlyCost: 24},
+		"aws/ec2":   {TotalInstancesCount: 0, ExcludedInstancesCount: 2}, // nothing left to divide by
+		"gcp/disks": {TotalInstancesCount: 1, UnusedInstancesCount: 1, TotalSizeGiB: 100, UnusedSizeGiB: 100, MonthlyCost: 4},
+	} {
+		run := Run{ID: name + "-run", Detector: name, StartedAt: time.Now(), FinishedAt: time.Now(), Result: result}
+		if err := s.PutRun(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sum, err := Summarize(ctx, s, []string{"aws/ebs", "aws/ec2", "gcp/disks", "aws/rds"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sum.Resources) != 3 || len(sum.Missing) != 1 || sum.Missing[0] != "aws/rds" {
+		t.Fatalf("summary = %+v, want three resources and aws/rds missing", sum)
+	}
+	if ec2 := sum.Resources[1]; ec2.Detector != "aws/ec2" || ec2.Percentage != 0 || ec2.SizePercentage != nil || ec2.ExcludedInstancesCount != 2 {
+		t.Errorf("aws/ec2 = %+v", ec2)
+	}
+	if ebs := sum.Resources[0]; ebs.Percentage != 25 || *ebs.SizePercentage != 75 || ebs.RunID != "aws/ebs-run" {
+		t.Errorf("aws/ebs = %+v", ebs)
+	}
+	overall := sum.Overall
+	if overall.TotalInstancesCount != 5 || overall.UnusedInstancesCount != 2 || overall.Percentage != 40 || overall.MonthlyCost != 28 {
+		t.Errorf("overall = %+v", overall)
+	}
+	// 400 of the 500 GiB of sized resources are unused.
+	if overall.SizePercentage == nil || *overall.SizePercentage != 80 {
+		t.Errorf("overall size percentage = %v, want 80", overall.SizePercentage)
+	}
+	if aws := sum.Providers[scanner.AWS]; len(sum.Providers) != 2 || aws.TotalInstancesCount != 4 || aws.ExcludedInstancesCount != 2 || *aws.SizePercentage != 75 {
+		t.Errorf("providers = %+v", sum.Providers)
+	}
+}
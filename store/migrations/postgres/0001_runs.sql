@@ -0,0 +1,29 @@
+-- Scan runs, one per detector and collection, with the KPI as columns and
+-- the rest of the result as JSON.
+CREATE TABLE runs (
+    id               TEXT PRIMARY KEY,
+    detector         TEXT NOT NULL,
+    started_at       TIMESTAMPTZ NOT NULL,
+    finished_at      TIMESTAMPTZ NOT NULL,
+    total_count      INTEGER NOT NULL,
+    unused_count     INTEGER NOT NULL,
+    excluded_count   INTEGER NOT NULL,
+    monthly_cost_usd DOUBLE PRECISION NOT NULL,
+    result           JSONB NOT NULL
+);
+
+CREATE INDEX runs_detector_started_at ON runs (detector, started_at);
+
+-- Findings of each run, in the order the scan reported them.
+CREATE TABLE findings (
+    run_id           TEXT NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+    position         INTEGER NOT NULL,
+    resource_id      TEXT NOT NULL,
+    resource_kind    TEXT NOT NULL,
+    region           TEXT NOT NULL,
+    account          TEXT NOT NULL,
+    project          TEXT NOT NULL,
+    monthly_cost_usd DOUBLE PRECISION NOT NULL,
+    finding          JSONB NOT NULL,
+    PRIMARY KEY (run_id, position)
+);
@@ -0,0 +1,32 @@
+-- What the findings API filters and sorts on beyond the columns of 0001, so
+-- it pages through findings in SQL instead of loading whole runs.
+ALTER TABLE findings ADD COLUMN account_alias TEXT NOT NULL DEFAULT '';
+
+-- Unix milliseconds, NULL when the provider does not report a creation time.
+ALTER TABLE findings ADD COLUMN created_at_ms INTEGER;
+
+UPDATE findings SET
+    account_alias = COALESCE(json_extract(finding, '$.account_alias'), ''),
+    created_at_ms = CASE
+        WHEN json_extract(finding, '$.created_at') LIKE '0001-01-01T00:00:00%' THEN NULL
+        ELSE CAST(strftime('%s', json_extract(finding, '$.created_at')) AS INTEGER) * 1000
+            + CAST(substr(strftime('%f', json_extract(finding, '$.created_at')), 4) AS INTEGER)
+    END;
+
+CREATE INDEX findings_run_id_cost ON findings (run_id, monthly_cost_usd);
+CREATE INDEX findings_run_id_created_at ON findings (run_id, created_at_ms);
+
+-- Tags, or GCP labels, of each finding.
+CREATE TABLE finding_tags (
+    run_id   TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    key      TEXT NOT NULL,
+    value    TEXT NOT NULL,
+    PRIMARY KEY (run_id, position, key),
+    FOREIGN KEY (run_id, position) REFERENCES findings (run_id, position) ON DELETE CASCADE
+);
+
+INSERT INTO finding_tags (run_id, position, key, value)
+    SELECT f.run_id, f.position, t.key, t.value FROM findings f, json_each(f.finding, '$.tags') t;
+
+CREATE INDEX finding_tags_key_value ON finding_tags (key, value);
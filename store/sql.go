@@ -0,0 +1,489 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	_ "modernc.org/sqlite" // registers the "sqlite" driver, without cgo
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// SQL keeps runs in an embedded SQLite database or in PostgreSQL. A run is
+// a row of runs with its KPI as columns; its findings are rows of findings.
+type SQL struct {
+	db      *sql.DB
+	dialect string // "sqlite" or "postgres", also the directory of its migrations
+}
+
+// OpenSQLite opens, creating it if needed, the SQLite database at path and
+// migrates its schema.
+func OpenSQLite(ctx context.Context, path string) (*SQL, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows one writer at a time; one connection keeps writers
+	// from failing with SQLITE_BUSY instead of waiting.
+	db.SetMaxOpenConns(1)
+	return openSQL(ctx, db, "sqlite")
+}
+
+// OpenPostgres connects to the PostgreSQL database at url and migrates its
+// schema.
+func OpenPostgres(ctx context.Context, url string) (*SQL, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+	return openSQL(ctx, db, "postgres")
+}
+
+func openSQL(ctx context.Context, db *sql.DB, dialect string) (*SQL, error) {
+	s := &SQL{db: db, dialect: dialect}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating %s store: %w", dialect, err)
+	}
+	return s, nil
+}
+
+// Close closes the database.
+func (s *SQL) Close() error { return s.db.Close() }
+
+// migrate applies, in order and each in its own transaction, the migrations
+// of the dialect not yet recorded in schema_migrations. Migrations are
+// files named <version>_<name>.sql holding statements ended by ";".
+func (s *SQL) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+	files, err := fs.Glob(migrations, path.Join("migrations", s.dialect, "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.SplitN(path.Base(file), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("%s: no version number", file)
+		}
+		if version <= current {
+			continue
+		}
+		script, err := migrations.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := s.inTx(ctx, func(tx *sql.Tx) error {
+			for _, stmt := range strings.Split(string(script), ";") {
+				if strings.TrimSpace(stripComments(stmt)) == "" {
+					continue
+				}
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version)
+			return err
+		}); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// PutRun writes the run and its findings in one transaction.
+func (s *SQL) PutRun(ctx context.Context, run Run) error {
+	summary := run.Result
+	summary.Findings, summary.ResourceIDs = nil, nil
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO runs
+			(id, detector, started_at, finished_at, total_count, unused_count, excluded_count, monthly_cost_usd, result)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			run.ID, run.Detector, sqlTimeString(run.StartedAt), sqlTimeString(run.FinishedAt),
+			run.Result.TotalInstancesCount, run.Result.UnusedInstancesCount, run.Result.ExcludedInstancesCount,
+			run.Result.MonthlyCost, string(data))
+		if err != nil {
+			return err
+		}
+		insert, err := tx.PrepareContext(ctx, s.rebind(`INSERT INTO findings
+			(run_id, position, resource_id, resource_kind, region, account, account_alias, project, created_at_ms, monthly_cost_usd, finding)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`))
+		if err != nil {
+			return err
+		}
+		defer insert.Close()
+		insertTag, err := tx.PrepareContext(ctx, s.rebind(`INSERT INTO finding_tags (run_id, position, key, value) VALUES (?, ?, ?, ?)`))
+		if err != nil {
+			return err
+		}
+		defer insertTag.Close()
+		for i, f := range run.Result.Findings {
+			data, err := json.Marshal(f)
+			if err != nil {
+				return err
+			}
+			var created sql.NullInt64
+			if !f.CreatedAt.IsZero() {
+				created = sql.NullInt64{Int64: f.CreatedAt.UnixMilli(), Valid: true}
+			}
+			if _, err := insert.ExecContext(ctx, run.ID, i, f.ResourceID, f.ResourceKind, f.Region, f.Account, f.AccountAlias,
+				f.Project, created, f.MonthlyCost, string(data)); err != nil {
+				return err
+			}
+			for key, value := range f.Tags {
+				if _, err := insertTag.ExecContext(ctx, run.ID, i, key, value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// LatestRun returns the most recent run of detector with its findings.
+func (s *SQL) LatestRun(ctx context.Context, detector string) (Run, error) {
+	run, err := s.LatestSummary(ctx, detector)
+	if err != nil {
+		return Run{}, err
+	}
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT finding FROM findings WHERE run_id = ? ORDER BY position`), run.ID)
+	if err != nil {
+		return Run{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return Run{}, err
+		}
+		var f scanner.Finding
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+		}
+		run.Result.Findings = append(run.Result.Findings, f)
+		run.Result.ResourceIDs = append(run.Result.ResourceIDs, f.ResourceID)
+	}
+	return run, rows.Err()
+}
+
+// LatestSummary returns the most recent run of detector from its row of
+// runs alone.
+func (s *SQL) LatestSummary(ctx context.Context, detector string) (Run, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(`SELECT id, detector, started_at, finished_at, result
+		FROM runs WHERE detector = ? ORDER BY started_at DESC LIMIT 1`), detector)
+	var run Run
+	var started, finished sqlTime
+	var result string
+	err := row.Scan(&run.ID, &run.Detector, &started, &finished, &result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, noRuns(detector)
+	}
+	if err != nil {
+		return Run{}, err
+	}
+	run.StartedAt, run.FinishedAt = time.Time(started), time.Time(finished)
+	if err := json.Unmarshal([]byte(result), &run.Result); err != nil {
+		return Run{}, fmt.Errorf("run %s: %w", run.ID, err)
+	}
+	return run, nil
+}
+
+// Snapshots returns the KPI of the runs of detector started in [from, to).
+func (s *SQL) Snapshots(ctx context.Context, detector string, from, to time.Time) ([]Snapshot, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT id, detector, started_at, total_count, unused_count, excluded_count, monthly_cost_usd
+		FROM runs WHERE detector = ? AND started_at >= ? AND started_at < ? ORDER BY started_at`),
+		detector, sqlTimeString(from), sqlTimeString(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var snapshots []Snapshot
+	for rows.Next() {
+		var snap Snapshot
+		var started sqlTime
+		if err := rows.Scan(&snap.RunID, &snap.Detector, &started, &snap.TotalInstancesCount,
+			&snap.UnusedInstancesCount, &snap.ExcludedInstancesCount, &snap.MonthlyCost); err != nil {
+			return nil, err
+		}
+		snap.StartedAt = time.Time(started)
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+// findings returns the page of findings q asks for, after the key of the
+// last finding of the previous page if any; see Findings.
+func (s *SQL) findings(ctx context.Context, q FindingQuery, after *findingKey, limit int) (FindingPage, error) {
+	page := FindingPage{Findings: []Listed{}}
+	runs := map[string]Listed{} // detector, provider and scan time of the latest run of each detector
+	var ids []any
+	for _, detector := range q.Detectors {
+		var id string
+		var finished sqlTime
+		err := s.db.QueryRowContext(ctx, s.rebind(`SELECT id, finished_at FROM runs WHERE detector = ? ORDER BY started_at DESC LIMIT 1`),
+			detector).Scan(&id, &finished)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return FindingPage{}, err
+		}
+		d, _ := scanner.Lookup(detector)
+		runs[id] = Listed{Detector: detector, Provider: d.Provider, RunID: id, ScannedAt: time.Time(finished)}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return page, nil
+	}
+
+	where, args := q.where(ids)
+	if err := s.db.QueryRowContext(ctx, s.rebind(`SELECT COUNT(*) FROM findings WHERE `+where), args...).Scan(&page.Total); err != nil {
+		return FindingPage{}, err
+	}
+
+	column, desc := sortColumn(q.Order)
+	direction, than := "ASC", ">"
+	if desc {
+		direction, than = "DESC", "<"
+	}
+	if after != nil {
+		var value any = after.Value
+		switch {
+		case column == "created_at_ms" && desc:
+			value = -int64(after.Value)
+		case column == "created_at_ms":
+			value = int64(after.Value)
+		case desc:
+			value = -after.Value
+		}
+		tie := `(run_id > ? OR (run_id = ? AND position > ?))`
+		if after.Unknown {
+			where += ` AND ` + column + ` IS NULL AND ` + tie
+		} else {
+			where += ` AND (` + column + ` IS NULL OR ` + column + ` ` + than + ` ? OR (` + column + ` = ? AND ` + tie + `))`
+			args = append(args, value, value)
+		}
+		args = append(args, after.RunID, after.RunID, after.Position)
+	}
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT run_id, position, `+column+`, finding FROM findings WHERE `+where+
+		` ORDER BY `+column+` IS NULL, `+column+` `+direction+`, run_id, position LIMIT ?`), append(args, limit+1)...)
+	if err != nil {
+		return FindingPage{}, err
+	}
+	defer rows.Close()
+	var keys []findingKey
+	for rows.Next() {
+		var runID, data string
+		var position int
+		var value sql.NullFloat64
+		if err := rows.Scan(&runID, &position, &value, &data); err != nil {
+			return FindingPage{}, err
+		}
+		l := runs[runID]
+		l.position = position
+		if err := json.Unmarshal([]byte(data), &l.Finding); err != nil {
+			return FindingPage{}, fmt.Errorf("run %s: %w", runID, err)
+		}
+		page.Findings = append(page.Findings, l)
+		// The key comes from the column sorted on, so the next page starts
+		// where the database sorted this one to end.
+		k := findingKey{Unknown: !value.Valid, Value: value.Float64, RunID: runID, Position: position}
+		if desc {
+			k.Value = -k.Value
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return FindingPage{}, err
+	}
+	if len(page.Findings) > limit {
+		page.Findings = page.Findings[:limit]
+		page.NextCursor = keys[limit-1].cursor(q.Order)
+	}
+	return page, nil
+}
+
+// where returns the condition, and its arguments, selecting the findings
+// of the runs ids that pass every filter of q.
+func (q FindingQuery) where(ids []any) (string, []any) {
+	conds := []string{`run_id IN (` + placeholders(len(ids)) + `)`}
+	args := append([]any{}, ids...)
+	in := func(column string, values []string) {
+		if len(values) > 0 {
+			conds = append(conds, column+` IN (`+placeholders(len(values))+`)`)
+			for _, v := range values {
+				args = append(args, v)
+			}
+		}
+	}
+	if len(q.Accounts) > 0 {
+		conds = append(conds, `(account IN (`+placeholders(len(q.Accounts))+`) OR (account_alias <> '' AND account_alias IN (`+placeholders(len(q.Accounts))+`)))`)
+		for range 2 {
+			for _, a := range q.Accounts {
+				args = append(args, a)
+			}
+		}
+	}
+	in("project", q.Projects)
+	if len(q.Regions) > 0 {
+		var or []string
+		for _, r := range q.Regions {
+			or = append(or, `region = ? OR region LIKE ? ESCAPE '\'`)
+			args = append(args, r, likeEscaper.Replace(r)+"-%")
+		}
+		conds = append(conds, `(`+strings.Join(or, ` OR `)+`)`)
+	}
+	in("resource_kind", q.Kinds)
+	// created_at_ms is to the millisecond, as precise as providers report
+	// creation times, so bounds are rounded outwards.
+	if !q.CreatedAfter.IsZero() {
+		conds = append(conds, `created_at_ms > ?`)
+		args = append(args, q.CreatedAfter.UnixMilli())
+	}
+	if !q.CreatedBefore.IsZero() {
+		conds = append(conds, `created_at_ms < ?`)
+		args = append(args, q.CreatedBefore.Add(time.Millisecond-1).UnixMilli())
+	}
+	if q.MinCost != nil {
+		conds = append(conds, `monthly_cost_usd >= ?`)
+		args = append(args, *q.MinCost)
+	}
+	if q.MaxCost != nil {
+		conds = append(conds, `monthly_cost_usd <= ?`)
+		args = append(args, *q.MaxCost)
+	}
+	keys := make([]string, 0, len(q.Tags))
+	for key := range q.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tag := `EXISTS (SELECT 1 FROM finding_tags t WHERE t.run_id = findings.run_id AND t.position = findings.position AND t.key = ?`
+		args = append(args, key)
+		if value := q.Tags[key]; value != "" {
+			tag += ` AND t.value = ?`
+			args = append(args, value)
+		}
+		conds = append(conds, tag+`)`)
+	}
+	return strings.Join(conds, ` AND `), args
+}
+
+// sortColumn returns the column of findings that order sorts by, and
+// whether descending.
+func sortColumn(order Order) (column string, desc bool) {
+	switch order {
+	case CostAsc:
+		return "monthly_cost_usd", false
+	case AgeDesc:
+		return "created_at_ms", false
+	case AgeAsc:
+		return "created_at_ms", true
+	default:
+		return "monthly_cost_usd", true
+	}
+}
+
+// likeEscaper escapes the wildcards of LIKE, with \ as the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// inTx runs fn in a transaction, committed when fn succeeds.
+func (s *SQL) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebind turns the ? placeholders of query into $1, $2, ... for PostgreSQL.
+func (s *SQL) rebind(query string) string {
+	if s.dialect != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sqlTimeString formats t so SQLite, which keeps times as text, orders and
+// compares them correctly; PostgreSQL parses it into a TIMESTAMPTZ.
+func sqlTimeString(t time.Time) string { return t.UTC().Format(sortableTimeFmt) }
+
+// sqlTime scans a time kept as text by SQLite or as a TIMESTAMPTZ by PostgreSQL.
+type sqlTime time.Time
+
+func (t *sqlTime) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*t = sqlTime(v.UTC())
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into a time", src)
+	}
+}
+
+func (t *sqlTime) parse(s string) error {
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	*t = sqlTime(parsed.UTC())
+	return nil
+}
+
+// stripComments drops the -- comments of a migration statement.
+func stripComments(stmt string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(stmt, "\n") {
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
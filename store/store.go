@@ -0,0 +1,147 @@
+// Package store persists scan runs so the API server can serve the results
+// of the last scheduled scan instead of scanning on every request.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+// Run is one scan of every configured scope of a detector, merged.
+type Run struct {
+	ID         string         `json:"id"`
+	Detector   string         `json:"detector"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Result     scanner.Result `json:"result"`
+}
+
+// Snapshot is the KPI of one run, without its findings.
+type Snapshot struct {
+	RunID                  string    `json:"run_id"`
+	Detector               string    `json:"detector"`
+	StartedAt              time.Time `json:"started_at"`
+	TotalInstancesCount    int       `json:"total_count"`
+	UnusedInstancesCount   int       `json:"unused_count"`
+	ExcludedInstancesCount int       `json:"excluded_count"`
+	MonthlyCost            float64   `json:"monthly_cost_usd"`
+}
+
+// Store keeps scan runs, their findings and the KPI of each.
+type Store interface {
+	PutRun(ctx context.Context, run Run) error
+	LatestRun(ctx context.Context, detector string) (Run, error)                            // a *scanner.Error of KindNotFound before the first run
+	LatestSummary(ctx context.Context, detector string) (Run, error)                        // LatestRun without the findings and resource IDs
+	Snapshots(ctx context.Context, detector string, from, to time.Time) ([]Snapshot, error) // runs started in [from, to), oldest first
+	Close() error
+}
+
+// Open returns the store cfg configures, the embedded SQLite database at
+// config.DefaultSQLite when it configures none. SQL schemas are migrated
+// to the latest version.
+func Open(ctx context.Context, cfg config.Store) (Store, error) {
+	switch {
+	case cfg.DynamoDB != nil:
+		return OpenDynamoDB(ctx, *cfg.DynamoDB)
+	case cfg.Postgres != nil:
+		return OpenPostgres(ctx, cfg.Postgres.URL)
+	case cfg.SQLite != nil && cfg.SQLite.Path != "":
+		return OpenSQLite(ctx, cfg.SQLite.Path)
+	default:
+		return OpenSQLite(ctx, config.DefaultSQLite)
+	}
+}
+
+// Scan runs scans, which must all be of the same detector, merges their
+// results and prices the findings from catalog. The run is not stored.
+func Scan(ctx context.Context, scans []config.Scan, catalog *pricing.Catalog) (Run, error) {
+	if len(scans) == 0 {
+		return Run{}, errors.New("no scans to run")
+	}
+	id, err := newID()
+	if err != nil {
+		return Run{}, err
+	}
+	run := Run{ID: id, Detector: scans[0].Detector.Name, StartedAt: time.Now().UTC()}
+	for _, scan := range scans {
+		r, err := scan.Detector.New(scan.Scope, scan.Params).Scan(ctx)
+		if err != nil {
+			return Run{}, err
+		}
+		catalog.Apply(scan.Detector.Provider, &r)
+		run.Result.Merge(r)
+	}
+	run.FinishedAt = time.Now().UTC()
+	return run, nil
+}
+
+// Collect runs the scans of every detector and stores one run per detector.
+// A detector whose scan fails is not stored; the others still are, and the
+// failures are returned together.
+func Collect(ctx context.Context, s Store, scans []config.Scan, catalog *pricing.Catalog) ([]Run, error) {
+	var runs []Run
+	var errs []error
+	for _, group := range ByDetector(scans) {
+		run, err := Scan(ctx, group, catalog)
+		if err == nil {
+			err = s.PutRun(ctx, run)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group[0].Detector.Name, err))
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, errors.Join(errs...)
+}
+
+// ByDetector groups scans by detector, in the order detectors first appear.
+func ByDetector(scans []config.Scan) [][]config.Scan {
+	var groups [][]config.Scan
+	index := map[string]int{}
+	for _, scan := range scans {
+		i, ok := index[scan.Detector.Name]
+		if !ok {
+			i = len(groups)
+			index[scan.Detector.Name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], scan)
+	}
+	return groups
+}
+
+// snapshot returns the KPI of run.
+func snapshot(run Run) Snapshot {
+	return Snapshot{
+		RunID:                  run.ID,
+		Detector:               run.Detector,
+		StartedAt:              run.StartedAt,
+		TotalInstancesCount:    run.Result.TotalInstancesCount,
+		UnusedInstancesCount:   run.Result.UnusedInstancesCount,
+		ExcludedInstancesCount: run.Result.ExcludedInstancesCount,
+		MonthlyCost:            run.Result.MonthlyCost,
+	}
+}
+
+// noRuns is the error for a detector that has not been scanned yet.
+func noRuns(detector string) error {
+	return &scanner.Error{Kind: scanner.KindNotFound, Resource: detector, Err: errors.New("no scan stored yet")}
+}
+
+// newID returns a random run ID.
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
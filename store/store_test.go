@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+)
+
+// detector returns a detector that finds the volume named after the
+// scope's region, or fails with err.
+func detector(name string, err error) scanner.Detector {
+	return scanner.Detector{
+		Name:     name,
+		Provider: scanner.AWS,
+		Scan: func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+			if err != nil {
+				return scanner.Result{}, err
+			}
+			id := "vol-" + scope.Region
+			return scanner.Result{
+				ResourceIDs:          []string{id},
+				TotalInstancesCount:  2,
+				UnusedInstancesCount: 1,
+				Findings: []scanner.Finding{{
+					ResourceID: id, ResourceKind: "ebs", Region: scope.Region,
+					Attributes: map[string]string{"volume_type": "gp3", "size_gib": "100"},
+				}},
+			}, nil
+		},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewDynamoDB(newFakeDynamoDB(), "runs")
+	ebs, broken := detector("aws/ebs", nil), detector("aws/ec2", scanner.ErrThrottled)
+	scans := []config.Scan{
+		{Detector: ebs, Scope: scanner.Scope{Region: "us-east-1"}},
+		{Detector: broken, Scope: scanner.Scope{Region: "us-east-1"}},
+		{Detector: ebs, Scope: scanner.Scope{Region: "eu-west-1"}},
+	}
+
+	runs, err := Collect(context.Background(), s, scans, catalog)
+
+	if !errors.Is(err, scanner.ErrThrottled) || !strings.Contains(err.Error(), "aws/ec2") {
+		t.Errorf("err = %v, want aws/ec2 throttled", err)
+	}
+	if len(runs) != 1 || runs[0].Result.TotalInstancesCount != 4 || runs[0].Result.MonthlyCost == 0 {
+		t.Fatalf("runs = %+v, want one priced aws/ebs run over both regions", runs)
+	}
+	got, err := s.LatestRun(context.Background(), "aws/ebs")
+	if err != nil || got.ID != runs[0].ID || len(got.Result.Findings) != 2 {
+		t.Errorf("LatestRun = %+v, %v", got, err)
+	}
+	if _, err := s.LatestRun(context.Background(), "aws/ec2"); !errors.Is(err, scanner.ErrNotFound) {
+		t.Errorf("stored the failed aws/ec2 run: %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/jobs"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+var (
+	accountID = regexp.MustCompile(`^\d{12}$`)
+	location  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// apiError is the body of every error response of the /v1 API.
+type apiError struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Status  int               `json:"status"`
+	Kind    scanner.Kind      `json:"kind"`
+	Message string            `json:"message"`
+	Params  map[string]string `json:"params,omitempty"` // the problem with each bad parameter
+}
+
+// paramError reports bad scan parameters, from a query or a job request:
+// the problem with each, by name.
+type paramError map[string]string
+
+func (e paramError) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + ": " + e[name]
+	}
+	return strings.Join(names, "; ")
+}
+
+// abortV1 responds with the /v1 error envelope for err.
+func abortV1(c *gin.Context, err error) {
+	body := errorBody{Status: errorStatus(err), Kind: scanner.KindOf(err), Message: err.Error()}
+	var params paramError
+	if errors.As(err, &params) {
+		body.Params = params
+	}
+	c.AbortWithStatusJSON(body.Status, apiError{Error: body})
+}
+
+// v1API serves the versioned API: one scan endpoint for every registered
+// detector, scoped and tuned by query parameters, the same scans as
+// background jobs, and the findings and KPIs of the stored runs.
+type v1API struct {
+	cfg     *config.Config
+	catalog *pricing.Catalog
+	jobs    *jobs.Manager
+	runs    store.Store
+}
+
+// register adds the /v1 routes to r.
+func (a v1API) register(r *gin.Engine) {
+	v1 := r.Group("/v1")
+	v1.GET("/:provider/:resource", a.scan)
+	v1.POST("/scans", a.submit)
+	v1.GET("/scans", a.listJobs)
+	v1.GET("/scans/:id", a.getJob)
+	v1.DELETE("/scans/:id", a.cancelJob)
+	v1.GET("/findings", a.findings)
+	v1.GET("/kpi", a.kpi)
+}
+
+// scan runs detector provider/resource live in the scope the query asks
+// for and returns its KPI. The run is not stored: stored runs are those of
+// the configured scopes.
+func (a v1API) scan(c *gin.Context) {
+	name := c.Param("provider") + "/" + c.Param("resource")
+	d, ok := scanner.Lookup(name)
+	if !ok {
+		abortV1(c, &scanner.Error{Kind: scanner.KindNotFound, Resource: name, Err: errors.New("unknown detector, see GET /scanners")})
+		return
+	}
+	scan, err := a.parseScan(d, c.Request.URL.Query())
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	run, err := store.Scan(c.Request.Context(), []config.Scan{scan}, a.catalog)
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	s := scan.Detector.New(scan.Scope, scan.Params)
+	body := kpi(run.Result)
+	body["detector"] = d.Name
+	body["scope"] = s.Scope()
+	body["parameters"] = s.Parameters()
+	body["scanned_at"] = run.FinishedAt
+	c.JSON(http.StatusOK, body)
+}
+
+// kpi returns every KPI of the latest stored run of each configured
+// detector: per resource type, per provider and overall. Detectors that
+// have not run yet are listed as missing.
+func (a v1API) kpi(c *gin.Context) {
+	if len(c.Request.URL.Query()) > 0 {
+		bad := paramError{}
+		for name := range c.Request.URL.Query() {
+			bad[name] = "not a parameter of /v1/kpi"
+		}
+		abortV1(c, &scanner.Error{Kind: scanner.KindInvalid, Err: bad})
+		return
+	}
+	sum, err := store.Summarize(c.Request.Context(), a.runs, a.findingDetectors(paramError{}, nil, nil))
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sum)
+}
+
+// submit queues a scan job for the detector and parameters in the JSON
+// body, e.g. {"detector": "aws/ec2", "region": "all", "threshold": 2}; the
+// parameters are those of GET /v1/{provider}/{resource}. It responds 202
+// with the job, whose status and progress GET /v1/scans/{id} reports.
+func (a v1API) submit(c *gin.Context) {
+	var body map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortV1(c, &scanner.Error{Kind: scanner.KindInvalid, Err: fmt.Errorf("body: %w", err)})
+		return
+	}
+	name, query, err := bodyParams(body)
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	d, ok := scanner.Lookup(name)
+	if !ok {
+		abortV1(c, &scanner.Error{Kind: scanner.KindInvalid, Err: paramError{"detector": "unknown detector " + strconv.Quote(name) + ", see GET /scanners"}})
+		return
+	}
+	scan, err := a.parseScan(d, query)
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	job, err := a.jobs.Submit(scan)
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	c.Header("Location", "/v1/scans/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// bodyParams splits a scan job request into the detector name and the scan
+// parameters, given as strings or numbers, keyed like query parameters.
+func bodyParams(body map[string]json.RawMessage) (string, map[string][]string, error) {
+	bad := paramError{}
+	var name string
+	if err := json.Unmarshal(body["detector"], &name); err != nil || name == "" {
+		bad["detector"] = "required, e.g. \"aws/ebs\""
+	}
+	query := map[string][]string{}
+	for key, raw := range body {
+		if key == "detector" {
+			continue
+		}
+		var s string
+		var n json.Number
+		switch {
+		case json.Unmarshal(raw, &s) == nil:
+			query[key] = []string{s}
+		case json.Unmarshal(raw, &n) == nil:
+			query[key] = []string{n.String()}
+		default:
+			bad[key] = "not a string or a number"
+		}
+	}
+	if len(bad) > 0 {
+		return "", nil, &scanner.Error{Kind: scanner.KindInvalid, Err: bad}
+	}
+	return name, query, nil
+}
+
+// listJobs returns the scan jobs the server keeps, newest first, without
+// their results.
+func (a v1API) listJobs(c *gin.Context) {
+	list := a.jobs.List()
+	for i := range list {
+		list[i].Result = nil
+	}
+	c.JSON(http.StatusOK, gin.H{"scans": list})
+}
+
+// getJob returns a scan job: its status and progress, and its result once
+// it succeeded.
+func (a v1API) getJob(c *gin.Context) {
+	job, err := a.jobs.Get(c.Param("id"))
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// cancelJob cancels a queued or running scan job. A running job stays
+// "cancelling" until its detector notices; cancelling a finished job is a
+// conflict.
+func (a v1API) cancelJob(c *gin.Context) {
+	job, err := a.jobs.Cancel(c.Param("id"))
+	if err != nil {
+		abortV1(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// parseScan binds d to the scope and parameters in query:
+//
+//	region     AWS and GCP regional detectors; "all" by default
+//	zone       GCP zonal detectors; "all" by default
+//	project    GCP detectors, required
+//	account    AWS detectors: an account ID or "all"; the credentials' own by default
+//	threshold  a positive number; the config's or the detector's default otherwise
+//	days       a positive number of days; likewise
+//
+// Every bad parameter is reported at once, as a paramError of kind invalid.
+// Parameters the detector does not take are bad too, rather than ignored.
+func (a v1API) parseScan(d scanner.Detector, query map[string][]string) (config.Scan, error) {
+	bad := paramError{}
+	get := func(name string) string {
+		values := query[name]
+		if len(values) > 1 {
+			bad[name] = "given more than once"
+		}
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	takes := map[string]bool{"threshold": true, "days": true}
+	scope := scanner.Scope{Tags: a.cfg.Tags}
+	switch d.Provider {
+	case scanner.AWS:
+		takes["account"] = true
+		scope.Account = get("account")
+		if scope.Account != "" && scope.Account != scanner.AllAccounts && !accountID.MatchString(scope.Account) {
+			bad["account"] = "not a 12 digit account ID or " + strconv.Quote(scanner.AllAccounts)
+		}
+		if a.cfg.AWS != nil {
+			scope.Role = a.cfg.AWS.Role
+		}
+	case scanner.GCP:
+		takes["project"] = true
+		if scope.Project = get("project"); scope.Project == "" {
+			bad["project"] = "required"
+		}
+	}
+	switch {
+	case d.Scope == scanner.Regional:
+		takes["region"] = true
+		scope.Region = locationParam(bad, "region", get("region"), scanner.AllRegions)
+	case d.Scope == scanner.Zonal:
+		takes["zone"] = true
+		scope.Zone = locationParam(bad, "zone", get("zone"), scanner.AllZones)
+	case d.Provider == scanner.AWS:
+		scope.Region = scanner.AllRegions // global detectors list from every region
+	}
+
+	params := a.cfg.Detectors[d.Name]
+	if s := get("threshold"); s != "" {
+		t, err := strconv.ParseFloat(s, 64)
+		// A zero threshold means the detector's default, so it cannot be asked for.
+		if err != nil || t <= 0 || math.IsInf(t, 0) || math.IsNaN(t) {
+			bad["threshold"] = "not a positive number"
+		}
+		params.Threshold = t
+	}
+	if s := get("days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil || days < 1 {
+			bad["days"] = "not a positive whole number"
+		}
+		params.Days = days
+	}
+
+	for name := range query {
+		if !takes[name] {
+			bad[name] = "not a parameter of " + d.Name
+		}
+	}
+	if len(bad) > 0 {
+		return config.Scan{}, &scanner.Error{Kind: scanner.KindInvalid, Err: bad}
+	}
+	return config.Scan{Detector: d, Scope: scope, Params: params}, nil
+}
+
+// locationParam checks the region or zone value of query parameter name,
+// returning all when it is empty.
+func locationParam(bad paramError, name, value, all string) string {
+	if value == "" {
+		return all
+	}
+	if value != all && !location.MatchString(value) {
+		bad[name] = strconv.Quote(value) + " is not a " + name + " or " + strconv.Quote(all)
+	}
+	return value
+}
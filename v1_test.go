@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sawlemon/unused-cloud-resources/config"
+	"github.com/sawlemon/unused-cloud-resources/jobs"
+	"github.com/sawlemon/unused-cloud-resources/scanner"
+	"github.com/sawlemon/unused-cloud-resources/scanner/pricing"
+	"github.com/sawlemon/unused-cloud-resources/store"
+)
+
+// scanned is the scope and parameters the fake detector last ran with.
+var scanned struct {
+	scope  scanner.Scope
+	params scanner.Parameters
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	// aws/fake finds nothing, and so has no percentage to divide by zero.
+	scanner.Register(scanner.Detector{
+		Name:     "aws/fake",
+		Provider: scanner.AWS,
+		Scope:    scanner.Regional,
+		Defaults: scanner.Parameters{Threshold: 1, Days: 7},
+		Scan: func(ctx context.Context, scope scanner.Scope, params scanner.Parameters) (scanner.Result, error) {
+			scanned.scope, scanned.params = scope, params
+			return scanner.Result{}, nil
+		},
+	})
+}
+
+func newV1(t *testing.T) (*gin.Engine, store.Store) {
+	catalog, err := pricing.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs, err := store.OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "unused.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { runs.Close() })
+	cfg := &config.Config{
+		AWS:       &config.AWS{Role: "FinOpsReadOnly"},
+		Detectors: map[string]scanner.Parameters{"aws/fake": {Days: 30}},
+	}
+	scanJobs := jobs.New(catalog, 1)
+	t.Cleanup(scanJobs.Close)
+	r := gin.New()
+	v1API{cfg: cfg, catalog: catalog, jobs: scanJobs, runs: runs}.register(r)
+	return r, runs
+}
+
+func get(t *testing.T, r *gin.Engine, target string, body any) int {
+	t.Helper()
+	return do(t, r, http.MethodGet, target, "", body)
+}
+
+func do(t *testing.T, r *gin.Engine, method, target, payload string, body any) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(payload)))
+	if err := json.Unmarshal(w.Body.Bytes(), body); err != nil {
+		t.Fatalf("%s: %v in %s", target, err, w.Body)
+	}
+	return w.Code
+}
+
+func TestV1Scan(t *testing.T) {
+	r, _ := newV1(t)
+	var body map[string]any
+	code := get(t, r, "/v1/aws/fake?region=eu-west-1&account=123456789012&threshold=2.5", &body)
+
+	if code != http.StatusOK || body["percentage"] != 0.0 || body["detector"] != "aws/fake" {
+		t.Errorf("%d %v", code, body)
+	}
+	want := scanner.Scope{Account: "123456789012", Role: "FinOpsReadOnly", Region: "eu-west-1"}
+	if s := scanned.scope; s.Account != want.Account || s.Role != want.Role || s.Region != want.Region {
+		t.Errorf("scope = %+v, want %+v", s, want)
+	}
+	// days comes from the config, threshold from the query.
+	if scanned.params != (scanner.Parameters{Threshold: 2.5, Days: 30}) {
+		t.Errorf("params = %+v", scanned.params)
+	}
+
+	get(t, r, "/v1/aws/fake", &body)
+	if scanned.scope.Region != scanner.AllRegions {
+		t.Errorf("region = %q, want every region by default", scanned.scope.Region)
+	}
+}
+
+func TestV1Errors(t *testing.T) {
+	r, _ := newV1(t)
+	for _, tc := range []struct {
+		target string
+		status int
+		kind   scanner.Kind
+		params []string
+	}{
+		{"/v1/aws/nat", http.StatusNotFound, scanner.KindNotFound, nil},
+		{"/v1/aws/fake?threshold=-1&days=0.5", http.StatusBadRequest, scanner.KindInvalid, []string{"threshold", "days"}},
+		{"/v1/aws/fake?account=prod&region=US_EAST", http.StatusBadRequest, scanner.KindInvalid, []string{"account", "region"}},
+		{"/v1/aws/fake?zone=us-east-1a&project=p", http.StatusBadRequest, scanner.KindInvalid, []string{"zone", "project"}},
+		{"/v1/aws/fake?days=7&days=8", http.StatusBadRequest, scanner.KindInvalid, []string{"days"}},
+		{"/v1/aws/fake?threshold=0", http.StatusBadRequest, scanner.KindInvalid, []string{"threshold"}},
+		{"/v1/gcp/disks?region=us-central1", http.StatusBadRequest, scanner.KindInvalid, []string{"project", "region"}},
+	} {
+		var body apiError
+		code := get(t, r, tc.target, &body)
+		if code != tc.status || body.Error.Status != tc.status || body.Error.Kind != tc.kind || body.Error.Message == "" {
+			t.Errorf("%s: %d %+v, want %d %s", tc.target, code, body, tc.status, tc.kind)
+		}
+		if len(body.Error.Params) != len(tc.params) {
+			t.Errorf("%s: params = %v, want %v", tc.target, body.Error.Params, tc.params)
+		}
+		for _, name := range tc.params {
+			if body.Error.Params[name] == "" {
+				t.Errorf("%s: no problem reported with %s in %v", tc.target, name, body.Error.Params)
+			}
+		}
+	}
+}
+
+func TestV1ScanJobs(t *testing.T) {
+	r, _ := newV1(t)
+	var job jobs.Job
+	if code := do(t, r, http.MethodPost, "/v1/scans", `{"detector": "aws/fake", "region": "us-west-2", "threshold": 3}`, &job); code != http.StatusAccepted {
+		t.Fatalf("POST: %d %+v", code, job)
+	}
+	if job.ID == "" || job.Scope.Region != "us-west-2" || job.Parameters != (scanner.Parameters{Threshold: 3, Days: 30}) {
+		t.Errorf("job = %+v", job)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); !job.Status.Done() && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		get(t, r, "/v1/scans/"+job.ID, &job)
+	}
+	if job.Status != jobs.Succeeded || job.Result == nil {
+		t.Errorf("job = %+v, want succeeded with a result", job)
+	}
+
+	var body apiError
+	if code := do(t, r, http.MethodDelete, "/v1/scans/"+job.ID, "", &body); code != http.StatusConflict || body.Error.Kind != scanner.KindConflict {
+		t.Errorf("DELETE of a finished job: %d %+v", code, body)
+	}
+	if code := get(t, r, "/v1/scans/nope", &body); code != http.StatusNotFound || body.Error.Kind != scanner.KindNotFound {
+		t.Errorf("GET of an unknown job: %d %+v", code, body)
+	}
+	for payload, params := range map[string]int{
+		`{"region": "us-east-1"}`:                          1, // no detector
+		`{"detector": "aws/nat"}`:                          1,
+		`{"detector": "aws/fake", "days": [7]}`:            1,
+		`{"detector": "aws/fake", "days": 0, "zone": "a"}`: 2,
+		`not json`: 0,
+	} {
+		body = apiError{}
+		if code := do(t, r, http.MethodPost, "/v1/scans", payload, &body); code != http.StatusBadRequest || len(body.Error.Params) != params {
+			t.Errorf("POST %s: %d %+v, want %d bad parameters", payload, code, body, params)
+		}
+	}
+}
+
+func TestV1KPI(t *testing.T) {
+	r, runs := newV1(t)
+	var sum store.Summary
+	if code := get(t, r, "/v1/kpi", &sum); code != http.StatusOK || len(sum.Resources) != 0 || len(sum.Missing) != 1 {
+		t.Fatalf("before any run: %d %+v, want aws/fake missing", code, sum)
+	}
+
+	result := scanner.Result{TotalInstancesCount: 8, UnusedInstancesCount: 2, TotalSizeGiB: 80, UnusedSizeGiB: 40, MonthlyCost: 3.2}
+	run := store.Run{ID: "run-1", Detector: "aws/fake", StartedAt: time.Now(), FinishedAt: time.Now(), Result: result}
+	if err := runs.PutRun(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+	sum = store.Summary{}
+	if code := get(t, r, "/v1/kpi", &sum); code != http.StatusOK || len(sum.Resources) != 1 || len(sum.Missing) != 0 {
+		t.Fatalf("after a run: %d %+v", code, sum)
+	}
+	if fake := sum.Resources[0]; fake.Detector != "aws/fake" || fake.RunID != "run-1" || fake.Percentage != 25 || *fake.SizePercentage != 50 {
+		t.Errorf("aws/fake = %+v", fake)
+	}
+	if aws := sum.Providers[scanner.AWS]; aws.MonthlyCost != 3.2 || sum.Overall.UnusedInstancesCount != 2 {
+		t.Errorf("aws = %+v, overall = %+v", aws, sum.Overall)
+	}
+
+	var body apiError
+	if code := get(t, r, "/v1/kpi?provider=aws", &body); code != http.StatusBadRequest || body.Error.Params["provider"] == "" {
+		t.Errorf("unknown parameter: %d %+v", code, body)
+	}
+}